@@ -0,0 +1,145 @@
+package Analyzer
+
+import (
+	"path/filepath"
+	"sort"
+)
+
+// RootResults holds the analysis results of one analyzed root.
+type RootResults struct {
+	RootPath string
+	Results  []AnalyzeFileResult
+}
+
+// AggregateResult is the union of the results of several roots.
+type AggregateResult struct {
+	Roots       []RootResults
+	Results     []AnalyzeFileResult // Every file once, even if several roots contain it
+	SharedFiles int                 // Files contained in more than one root
+}
+
+// LanguageComparison compares the size of a language across roots.
+type LanguageComparison struct {
+	Language   Language
+	RootFiles  []int   // Files per root, in the order of the roots
+	RootSizes  []int64 // Size per root, in the order of the roots
+	TotalFiles int     // Files of the merged results
+	TotalSize  int64   // Size of the merged results
+}
+
+// MergeRootResults merges the results of several roots. Files reached from more than one root,
+// e.g. when one root contains another, are only kept once so totals aren't counted twice.
+//
+// Args:
+//   - roots: The results of every analyzed root.
+//
+// Returns:
+//   - AggregateResult: The merged results.
+func MergeRootResults(roots []RootResults) AggregateResult {
+	aggregate := AggregateResult{Roots: roots}
+	seen := make(map[string]int)
+
+	for _, root := range roots {
+		for _, result := range root.Results {
+			key := filepath.Clean(result.FileMetadata.Path)
+			seen[key]++
+			if seen[key] == 1 {
+				aggregate.Results = append(aggregate.Results, result)
+			} else if seen[key] == 2 {
+				aggregate.SharedFiles++
+			}
+		}
+	}
+
+	return aggregate
+}
+
+// CompareLanguages returns the files and size of every language for each root and for the merged results,
+// largest language first.
+//
+// Args:
+//   - includeComment: A boolean indicating whether to include comment size in the size calculation.
+//
+// Returns:
+//   - []LanguageComparison: One entry per language found in any root.
+func (aggregate AggregateResult) CompareLanguages(includeComment bool) []LanguageComparison {
+	comparisons := make(map[Language]*LanguageComparison)
+	get := func(lang Language) *LanguageComparison {
+		if comparisons[lang] == nil {
+			comparisons[lang] = &LanguageComparison{
+				Language:  lang,
+				RootFiles: make([]int, len(aggregate.Roots)),
+				RootSizes: make([]int64, len(aggregate.Roots)),
+			}
+		}
+		return comparisons[lang]
+	}
+
+	for i, root := range aggregate.Roots {
+		for _, result := range root.Results {
+			comparison := get(result.Language)
+			comparison.RootFiles[i]++
+			comparison.RootSizes[i] += result.Size(includeComment)
+		}
+	}
+	for _, result := range aggregate.Results {
+		comparison := get(result.Language)
+		comparison.TotalFiles++
+		comparison.TotalSize += result.Size(includeComment)
+	}
+
+	sorted := make([]LanguageComparison, 0, len(comparisons))
+	for _, comparison := range comparisons {
+		sorted = append(sorted, *comparison)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].TotalSize != sorted[j].TotalSize {
+			return sorted[i].TotalSize > sorted[j].TotalSize
+		}
+		return sorted[i].Language.String() < sorted[j].Language.String()
+	})
+
+	return sorted
+}
+
+// ResultSummary holds the totals of a set of results.
+type ResultSummary struct {
+	Files                       int
+	TestFiles                   int
+	Languages                   int
+	TotalSize                   int64
+	CodeSize                    int64
+	CommentSize                 int64
+	BlankLines                  int
+	AverageMaintainabilityIndex float64
+}
+
+// SummarizeResults sums the sizes and counts of a set of results.
+//
+// Args:
+//   - results: A slice of AnalyzeFileResult containing analysis data for multiple files.
+//
+// Returns:
+//   - ResultSummary: The totals of the results.
+func SummarizeResults(results []AnalyzeFileResult) ResultSummary {
+	summary := ResultSummary{Files: len(results)}
+	languages := make(map[Language]bool)
+
+	for _, result := range results {
+		languages[result.Language] = true
+		summary.TotalSize += result.TotalSize
+		summary.CodeSize += result.CodeSize
+		summary.CommentSize += result.CommentSize
+		summary.BlankLines += result.BlankLines
+		summary.AverageMaintainabilityIndex += result.MaintainabilityIndex
+		if result.IsTest {
+			summary.TestFiles++
+		}
+	}
+
+	summary.Languages = len(languages)
+	if summary.Files > 0 {
+		summary.AverageMaintainabilityIndex /= float64(summary.Files)
+	}
+	return summary
+}
@@ -0,0 +1,43 @@
+package Analyzer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeRootResults(t *testing.T) {
+	file := func(path string, lang Language, size int64) AnalyzeFileResult {
+		return AnalyzeFileResult{FileMetadata: FileMetadata{Path: path}, Language: lang, CodeSize: size, TotalSize: size + 10}
+	}
+	outer := RootResults{RootPath: "/repo", Results: []AnalyzeFileResult{
+		file("/repo/main.go", Go, 100),
+		file("/repo/lib/util.go", Go, 50),
+		file("/repo/lib/tool.py", Python, 20),
+	}}
+	inner := RootResults{RootPath: "/repo/lib", Results: []AnalyzeFileResult{
+		file("/repo/lib/util.go", Go, 50),
+		file("/repo/lib/tool.py", Python, 20),
+	}}
+
+	aggregate := MergeRootResults([]RootResults{outer, inner})
+	require.Len(t, aggregate.Results, 3)
+	require.Equal(t, 2, aggregate.SharedFiles)
+
+	comparisons := aggregate.CompareLanguages(false)
+	require.Equal(t, LanguageComparison{
+		Language:   Go,
+		RootFiles:  []int{2, 1},
+		RootSizes:  []int64{150, 50},
+		TotalFiles: 2,
+		TotalSize:  150,
+	}, comparisons[0])
+	require.Equal(t, Python, comparisons[1].Language)
+	require.Equal(t, int64(30), aggregate.CompareLanguages(true)[1].TotalSize)
+
+	summary := SummarizeResults(aggregate.Results)
+	require.Equal(t, 3, summary.Files)
+	require.Equal(t, 2, summary.Languages)
+	require.Equal(t, int64(170), summary.CodeSize)
+	require.Equal(t, int64(200), summary.TotalSize)
+}
@@ -1,6 +1,7 @@
 package Analyzer
 
 import (
+	"io/fs"
 	"statfiy/FileManager"
 	"unicode/utf8"
 )
@@ -17,6 +18,23 @@ type AnalyzeFileResult struct {
 	CommentSize  int64
 	CodeSize     int64
 	BlankLines   int
+	IsTest       bool // Set by ClassifyTestFiles
+	LineStats    LineStats
+
+	CodeLines            int // Lines containing code, after comments are stripped
+	Complexity           int // Estimated cyclomatic complexity of the whole file
+	Halstead             HalsteadMetrics
+	MaintainabilityIndex float64 // 0-100, higher is better
+	Nesting              NestingStats
+	Imports              []string // Imported paths as written in the source, see ExtractImports
+}
+
+// Size returns the code size of the file, including comments if requested.
+func (result AnalyzeFileResult) Size(includeComment bool) int64 {
+	if includeComment {
+		return result.TotalSize
+	}
+	return result.CodeSize
 }
 
 // AnalyzeSingleFile analyzes a file to determine its language, size, comment size, and blank lines.
@@ -28,9 +46,22 @@ type AnalyzeFileResult struct {
 //   - AnalyzeFileResult: Analysis result including code size, comment size, and blank lines.
 //   - error: An error if file reading fails.
 func AnalyzeSingleFile(metadata FileMetadata) (AnalyzeFileResult, error) {
+	return AnalyzeSingleFileFS(FileManager.OSFS, metadata)
+}
+
+// AnalyzeSingleFileFS is like AnalyzeSingleFile but reads the file from a filesystem.
+//
+// Arguments:
+//   - fsys: The filesystem the file is read from.
+//   - metadata: FileMetadata containing file details such as path and extension.
+//
+// Returns:
+//   - AnalyzeFileResult: Analysis result including code size, comment size, and blank lines.
+//   - error: An error if file reading fails.
+func AnalyzeSingleFileFS(fsys fs.FS, metadata FileMetadata) (AnalyzeFileResult, error) {
 	analysis := AnalyzeFileResult{
 		FileMetadata: metadata,
-		Language:     GetLanguage(metadata),
+		Language:     GetLanguageFS(fsys, metadata),
 	}
 
 	if analysis.Language == Unknown {
@@ -46,11 +77,17 @@ func AnalyzeSingleFile(metadata FileMetadata) (AnalyzeFileResult, error) {
 	// I have to track a lot of cases,
 	// and people often place comment symbols inside strings,
 	// like this in C: "/* *\" — which is not a comment.
-	source, err := FileManager.ReadFileString(metadata.Path)
+	source, err := FileManager.ReadFileStringFS(fsys, metadata.Path)
 	if err != nil {
 		return analysis, err
 	}
 
+	analyzeSource(&analysis, source)
+	return analysis, nil
+}
+
+// analyzeSource fills in the sizes and metrics of a file whose language is known from its content.
+func analyzeSource(analysis *AnalyzeFileResult, source string) {
 	extractedComments := ExtractCommentsByLanguage(source, analysis.Language)
 	for _, comment := range extractedComments {
 		analysis.CommentSize += int64(utf8.RuneCountInString(comment))
@@ -58,9 +95,17 @@ func AnalyzeSingleFile(metadata FileMetadata) (AnalyzeFileResult, error) {
 
 	analysis.TotalSize = int64(utf8.RuneCountInString(source))
 	analysis.BlankLines = CountBlankLines(source)
+	analysis.LineStats = CalculateLineStats(source, LineLengthLimit)
 	analysis.CodeSize = analysis.TotalSize - (analysis.CommentSize + int64(analysis.BlankLines))
 
-	return analysis, nil
+	stripped := StripCommentsByLanguage(source, analysis.Language)
+	tokens := Tokenize(stripped)
+	analysis.CodeLines = countCodeLines(tokens)
+	analysis.Complexity = CalculateComplexity(tokens)
+	analysis.Halstead = CalculateHalstead(tokens, analysis.Language)
+	analysis.MaintainabilityIndex = CalculateMaintainabilityIndex(analysis.Halstead.Volume(), analysis.Complexity, analysis.CodeLines)
+	analysis.Nesting = CalculateNesting(stripped, tokens, analysis.Language)
+	analysis.Imports = ExtractImports(stripped, analysis.Language)
 }
 
 // AnalyzeMultipleFiles processes multiple files and returns analysis results.
@@ -72,14 +117,28 @@ func AnalyzeSingleFile(metadata FileMetadata) (AnalyzeFileResult, error) {
 //   - []AnalyzeFileResult: Analysis results for each valid file.
 //   - error: An error if any file reading operation fails.
 func AnalyzeMultipleFiles(files []FileMetadata) ([]AnalyzeFileResult, error) {
+	return AnalyzeMultipleFilesFS(FileManager.OSFS, files)
+}
+
+// AnalyzeMultipleFilesFS is like AnalyzeMultipleFiles but reads the files from a filesystem,
+// e.g. a git revision opened with FileManager.OpenGitRevision.
+//
+// Arguments:
+//   - fsys: The filesystem the files are read from.
+//   - files: A slice of FileMetadata representing the files to be analyzed.
+//
+// Returns:
+//   - []AnalyzeFileResult: Analysis results for each valid file.
+//   - error: An error if any file reading operation fails.
+func AnalyzeMultipleFilesFS(fsys fs.FS, files []FileMetadata) ([]AnalyzeFileResult, error) {
 	var results []AnalyzeFileResult
 
 	for _, file := range files {
-		if GetLanguage(file) == Unknown {
+		if GetLanguageFS(fsys, file) == Unknown {
 			continue
 		}
 
-		result, err := AnalyzeSingleFile(file)
+		result, err := AnalyzeSingleFileFS(fsys, file)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,168 @@
+package Analyzer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"statfiy/FileManager"
+	"time"
+)
+
+// CacheVersion is stored with every cached result. Bump it when the analysis changes, so results
+// computed by older versions are recomputed instead of reused.
+const CacheVersion = 2
+
+// CachedResult is the analysis of a file as stored in a ResultCache, with what is needed to tell
+// whether the file changed since.
+type CachedResult struct {
+	Path        string
+	Size        int64
+	ModifiedAt  time.Time
+	ContentHash string // Hex encoded SHA-256 of the content
+	Settings    string // The CacheVersion and the settings the result depends on, see cacheSettings
+	Result      AnalyzeFileResult
+}
+
+// ResultCache stores analysis results between runs, keyed by file path. The Database package
+// implements it on top of SQLite.
+type ResultCache interface {
+	// Lookup returns the cached result of a path and whether one was found.
+	Lookup(path string) (CachedResult, bool, error)
+	// Store adds or replaces the cached results of the entries' paths, all at once.
+	Store(entries ...CachedResult) error
+	// Remove drops the cached results of the paths, e.g. of deleted files.
+	Remove(paths ...string) error
+}
+
+// MemoryCache is a ResultCache kept in memory, e.g. to re-analyze only changed files in watch mode
+// when the database isn't used.
+type MemoryCache map[string]CachedResult
+
+// Lookup returns the cached result of a path and whether one was found.
+func (c MemoryCache) Lookup(path string) (CachedResult, bool, error) {
+	entry, found := c[path]
+	return entry, found, nil
+}
+
+// Store adds or replaces the cached results of the entries' paths.
+func (c MemoryCache) Store(entries ...CachedResult) error {
+	for _, entry := range entries {
+		c[entry.Path] = entry
+	}
+	return nil
+}
+
+// Remove drops the cached results of the paths.
+func (c MemoryCache) Remove(paths ...string) error {
+	for _, path := range paths {
+		delete(c, path)
+	}
+	return nil
+}
+
+// CacheStats counts how the files of a run were served by a ResultCache.
+type CacheStats struct {
+	Hits          int // Unchanged files whose result was reused
+	Misses        int // Files without a cached result
+	Invalidations int // Files whose cached result was outdated by a content or settings change
+}
+
+// Add returns the sum of two stats, e.g. of several root paths.
+func (s CacheStats) Add(other CacheStats) CacheStats {
+	return CacheStats{
+		Hits:          s.Hits + other.Hits,
+		Misses:        s.Misses + other.Misses,
+		Invalidations: s.Invalidations + other.Invalidations,
+	}
+}
+
+// AnalyzeMultipleFilesCachedFS is like AnalyzeMultipleFilesFS but reuses the results of unchanged files
+// from a cache and stores the results of the others, all at once after the analysis.
+// A file with the size and modification time of its cached result isn't read at all. Otherwise its
+// content hash decides: an equal hash reuses the result (e.g. after a fresh checkout), a different
+// one invalidates it.
+//
+// Args:
+//   - fsys: The filesystem the files are read from.
+//   - files: A slice of FileMetadata representing the files to be analyzed.
+//   - cache: The cache to read results from and store them in.
+//
+// Returns:
+//   - []AnalyzeFileResult: Analysis results for each valid file.
+//   - CacheStats: The hits, misses and invalidations of the cache.
+//   - error: An error if a file or the cache can't be read or written.
+func AnalyzeMultipleFilesCachedFS(fsys fs.FS, files []FileMetadata, cache ResultCache) ([]AnalyzeFileResult, CacheStats, error) {
+	var results []AnalyzeFileResult
+	var updates []CachedResult
+	var stats CacheStats
+	settings := cacheSettings()
+
+	for _, file := range files {
+		language := GetLanguageFS(fsys, file)
+		if language == Unknown {
+			continue
+		}
+
+		entry, found, err := cache.Lookup(file.Path)
+		if err != nil {
+			return nil, stats, fmt.Errorf("failed to read cached result of %s: %w", file.Path, err)
+		}
+		valid := found && entry.Settings == settings
+		if valid && entry.Size == file.Size && entry.ModifiedAt.Equal(file.ModifiedAt) {
+			stats.Hits++
+			results = append(results, cachedResult(entry, file))
+			continue
+		}
+
+		content, err := FileManager.ReadFileBytesFS(fsys, file.Path)
+		if err != nil {
+			return nil, stats, err
+		}
+		hash := sha256.Sum256(content)
+		contentHash := hex.EncodeToString(hash[:])
+
+		var result AnalyzeFileResult
+		if valid && entry.ContentHash == contentHash {
+			stats.Hits++
+			result = cachedResult(entry, file)
+		} else {
+			if found {
+				stats.Invalidations++
+			} else {
+				stats.Misses++
+			}
+			result = AnalyzeFileResult{FileMetadata: file, Language: language}
+			analyzeSource(&result, string(content))
+		}
+
+		updates = append(updates, CachedResult{
+			Path:        file.Path,
+			Size:        file.Size,
+			ModifiedAt:  file.ModifiedAt,
+			ContentHash: contentHash,
+			Settings:    settings,
+			Result:      result,
+		})
+		results = append(results, result)
+	}
+
+	if len(updates) > 0 {
+		if err := cache.Store(updates...); err != nil {
+			return nil, stats, fmt.Errorf("failed to cache results: %w", err)
+		}
+	}
+	return results, stats, nil
+}
+
+// cacheSettings describes everything besides the content a cached result depends on.
+func cacheSettings() string {
+	return fmt.Sprintf("v%d,line-length-limit=%d", CacheVersion, LineLengthLimit)
+}
+
+// cachedResult returns a cached result with the current metadata of its file.
+func cachedResult(entry CachedResult, file FileMetadata) AnalyzeFileResult {
+	result := entry.Result
+	result.FileMetadata = file
+	return result
+}
@@ -0,0 +1,61 @@
+package Analyzer
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"statfiy/FileManager"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeMultipleFilesCachedFS(t *testing.T) {
+	modTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	fsys := fstest.MapFS{
+		"main.go":   {Data: []byte("package main\n\n// entry point\nfunc main() {}\n"), ModTime: modTime},
+		"util.py":   {Data: []byte("def add(a, b):\n    return a + b\n"), ModTime: modTime},
+		"README.md": {Data: []byte("# readme\n")},
+	}
+	collect := func() []FileMetadata {
+		files, err := FileManager.CollectFilesMetadataFS(fsys, ".")
+		require.NoError(t, err)
+		return files
+	}
+	cache := MemoryCache{}
+
+	expected, err := AnalyzeMultipleFilesFS(fsys, collect())
+	require.NoError(t, err)
+
+	results, stats, err := AnalyzeMultipleFilesCachedFS(fsys, collect(), cache)
+	require.NoError(t, err)
+	require.Equal(t, expected, results)
+	require.Equal(t, CacheStats{Misses: 2}, stats)
+
+	results, stats, err = AnalyzeMultipleFilesCachedFS(fsys, collect(), cache)
+	require.NoError(t, err)
+	require.Equal(t, expected, results)
+	require.Equal(t, CacheStats{Hits: 2}, stats)
+
+	// A touched file with the same content is still a hit, a changed file is analyzed again
+	fsys["main.go"].ModTime = modTime.Add(time.Hour)
+	fsys["util.py"] = &fstest.MapFile{Data: []byte("def add(a, b):\n    if a:\n        return a + b\n    return b\n"), ModTime: modTime}
+	results, stats, err = AnalyzeMultipleFilesCachedFS(fsys, collect(), cache)
+	require.NoError(t, err)
+	require.Equal(t, CacheStats{Hits: 1, Invalidations: 1}, stats)
+	require.Equal(t, modTime.Add(time.Hour), results[0].FileMetadata.ModifiedAt)
+	require.Equal(t, 2, results[1].Complexity)
+
+	// Results depend on the line length limit
+	defer func(limit int) { LineLengthLimit = limit }(LineLengthLimit)
+	LineLengthLimit = 10
+	_, stats, err = AnalyzeMultipleFilesCachedFS(fsys, collect(), cache)
+	require.NoError(t, err)
+	require.Equal(t, CacheStats{Invalidations: 2}, stats)
+
+	require.NoError(t, cache.Remove("util.py"))
+	require.Len(t, cache, 1)
+	_, found, err := cache.Lookup("util.py")
+	require.NoError(t, err)
+	require.False(t, found)
+}
@@ -3,6 +3,7 @@ package Analyzer
 import (
 	"regexp"
 	"strings"
+	"unicode/utf8"
 )
 
 type ExtractComment func(source string) []string
@@ -10,47 +11,48 @@ type ExtractComment func(source string) []string
 // CommentSyntax represents symbols used for single-line and multi-line comments.
 type CommentSyntax struct {
 	ExtractComment ExtractComment
+	Pattern        string // Regex matching the comments, used when stripping them from source
 }
 
 // Mapping of programming languages to their respective comment syntax.
 var languageToCommentSyntax = map[Language]CommentSyntax{
-	Go:         {ExtractComment: ExtractCComments},
-	C:          {ExtractComment: ExtractCComments},
-	CPlusPlus:  {ExtractComment: ExtractCComments},
-	CSharp:     {ExtractComment: ExtractCComments},
-	Rust:       {ExtractComment: ExtractCComments},
-	JavaScript: {ExtractComment: ExtractCComments},
-	TypeScript: {ExtractComment: ExtractCComments},
-	Java:       {ExtractComment: ExtractCComments},
-	Kotlin:     {ExtractComment: ExtractCComments},
-	Swift:      {ExtractComment: ExtractCComments},
-	PHP:        {ExtractComment: ExtractCComments},
-	Dart:       {ExtractComment: ExtractCComments},
-	Scala:      {ExtractComment: ExtractCComments},
-	ObjectiveC: {ExtractComment: ExtractCComments},
-	Python:     {ExtractComment: ExtractPythonComments},
-	R:          {ExtractComment: ExtractPythonComments},
-	Ruby:       {ExtractComment: ExtractRubyComments},
-	Perl:       {ExtractComment: ExtractRubyComments},
-	Pascal:     {ExtractComment: ExtractPascalComments},
-	FSharp:     {ExtractComment: ExtractPascalComments},
-	Elixir:     {ExtractComment: ExtractElixirComments},
-	HTML:       {ExtractComment: ExtractHTMLComments},
-	CSS:        {ExtractComment: ExtractCSSComments},
-	SQL:        {ExtractComment: ExtractSQLComments},
-	Lua:        {ExtractComment: ExtractLuaComments},
-	Haskell:    {ExtractComment: ExtractHaskellComments},
-	Assembly:   {ExtractComment: ExtractAssemblyComments},
-	Bash:       {ExtractComment: ExtractBashComments},
-	Shell:      {ExtractComment: ExtractShellComments},
-	PowerShell: {ExtractComment: ExtractPowerShellComments},
-	Matlab:     {ExtractComment: ExtractMatlabComments},
-	VB:         {ExtractComment: ExtractVBComments},
-	Clojure:    {ExtractComment: ExtractClojureComments},
-	Julia:      {ExtractComment: ExtractJuliaComments},
-	Fortran:    {ExtractComment: ExtractFortranComments},
-	Zig:        {ExtractComment: ExtractCComments},
-	Unknown:    {ExtractComment: ExtractCComments}, // Default for unknown languages
+	Go:         {ExtractComment: ExtractCComments, Pattern: CommentRegexs.CComment},
+	C:          {ExtractComment: ExtractCComments, Pattern: CommentRegexs.CComment},
+	CPlusPlus:  {ExtractComment: ExtractCComments, Pattern: CommentRegexs.CComment},
+	CSharp:     {ExtractComment: ExtractCComments, Pattern: CommentRegexs.CComment},
+	Rust:       {ExtractComment: ExtractCComments, Pattern: CommentRegexs.CComment},
+	JavaScript: {ExtractComment: ExtractCComments, Pattern: CommentRegexs.CComment},
+	TypeScript: {ExtractComment: ExtractCComments, Pattern: CommentRegexs.CComment},
+	Java:       {ExtractComment: ExtractCComments, Pattern: CommentRegexs.CComment},
+	Kotlin:     {ExtractComment: ExtractCComments, Pattern: CommentRegexs.CComment},
+	Swift:      {ExtractComment: ExtractCComments, Pattern: CommentRegexs.CComment},
+	PHP:        {ExtractComment: ExtractCComments, Pattern: CommentRegexs.CComment},
+	Dart:       {ExtractComment: ExtractCComments, Pattern: CommentRegexs.CComment},
+	Scala:      {ExtractComment: ExtractCComments, Pattern: CommentRegexs.CComment},
+	ObjectiveC: {ExtractComment: ExtractCComments, Pattern: CommentRegexs.CComment},
+	Python:     {ExtractComment: ExtractPythonComments, Pattern: CommentRegexs.PythonComment},
+	R:          {ExtractComment: ExtractPythonComments, Pattern: CommentRegexs.PythonComment},
+	Ruby:       {ExtractComment: ExtractRubyComments, Pattern: CommentRegexs.RubyComment},
+	Perl:       {ExtractComment: ExtractRubyComments, Pattern: CommentRegexs.RubyComment},
+	Pascal:     {ExtractComment: ExtractPascalComments, Pattern: CommentRegexs.PascalComment},
+	FSharp:     {ExtractComment: ExtractPascalComments, Pattern: CommentRegexs.PascalComment},
+	Elixir:     {ExtractComment: ExtractElixirComments, Pattern: CommentRegexs.ElixirComment},
+	HTML:       {ExtractComment: ExtractHTMLComments, Pattern: CommentRegexs.HTMLComment},
+	CSS:        {ExtractComment: ExtractCSSComments, Pattern: CommentRegexs.CSSComment},
+	SQL:        {ExtractComment: ExtractSQLComments, Pattern: CommentRegexs.SQLComment},
+	Lua:        {ExtractComment: ExtractLuaComments, Pattern: CommentRegexs.LuaComment},
+	Haskell:    {ExtractComment: ExtractHaskellComments, Pattern: CommentRegexs.HaskellComment},
+	Assembly:   {ExtractComment: ExtractAssemblyComments, Pattern: CommentRegexs.AssemblyComment},
+	Bash:       {ExtractComment: ExtractBashComments, Pattern: CommentRegexs.BashComment},
+	Shell:      {ExtractComment: ExtractShellComments, Pattern: CommentRegexs.ShellComment},
+	PowerShell: {ExtractComment: ExtractPowerShellComments, Pattern: CommentRegexs.PowerShellComment},
+	Matlab:     {ExtractComment: ExtractMatlabComments, Pattern: CommentRegexs.MatlabComment},
+	VB:         {ExtractComment: ExtractVBComments, Pattern: CommentRegexs.VBComment},
+	Clojure:    {ExtractComment: ExtractClojureComments, Pattern: CommentRegexs.ClojureComment},
+	Julia:      {ExtractComment: ExtractJuliaComments, Pattern: CommentRegexs.JuliaComment},
+	Fortran:    {ExtractComment: ExtractFortranComments, Pattern: CommentRegexs.FortranComment},
+	Zig:        {ExtractComment: ExtractCComments, Pattern: CommentRegexs.CComment},
+	Unknown:    {ExtractComment: ExtractCComments, Pattern: CommentRegexs.CComment}, // Default for unknown languages
 }
 
 // ExtractCommentsByLanguage extracts all comments from the given source code based on the specified programming language.
@@ -76,6 +78,79 @@ func ExtractCommentsByLanguage(source string, lang Language) []string {
 	return groups
 }
 
+// StripCommentsByLanguage removes all comments from the given source code based on the specified programming language.
+// Comment characters are replaced by spaces and line breaks are kept, so line numbers of the
+// remaining code match the original source.
+//
+// Arguments:
+//   - source: The source code as a string.
+//   - lang: The programming language used in the source code.
+//
+// Returns:
+//   - string: The source code without comments.
+func StripCommentsByLanguage(source string, lang Language) string {
+	strip, found := commentStrippers[lang]
+	if !found || lang == Unknown {
+		return source
+	}
+
+	var builder strings.Builder
+	last := 0
+	for _, loc := range strip.regex.FindAllStringIndex(source, -1) {
+		start, end := loc[0], loc[1]
+		if strip.guarded && start < end {
+			_, size := utf8.DecodeRuneInString(source[start:end])
+			if start > 0 || strip.fullMatch.MatchString(source[start+size:end]) {
+				start += size
+			}
+		}
+
+		builder.WriteString(source[last:start])
+		builder.WriteString(blankOut(source[start:end]))
+		last = end
+	}
+	builder.WriteString(source[last:])
+
+	return builder.String()
+}
+
+// commentStripper holds the compiled regexes StripCommentsByLanguage uses for a language.
+type commentStripper struct {
+	regex     *regexp.Regexp
+	fullMatch *regexp.Regexp
+	// Some patterns consume one character in front of the comment to make sure
+	// it isn't inside a string, e.g. `(?:^|[^"'/])`. That character is code and has to survive.
+	guarded bool
+}
+
+// commentStrippers holds the comment stripping regexes of every language, compiled once.
+var commentStrippers = compileCommentStrippers()
+
+func compileCommentStrippers() map[Language]commentStripper {
+	strippers := make(map[Language]commentStripper, len(languageToCommentSyntax))
+	for lang, syntax := range languageToCommentSyntax {
+		if syntax.Pattern == "" {
+			continue
+		}
+		strippers[lang] = commentStripper{
+			regex:     regexp.MustCompile(syntax.Pattern),
+			fullMatch: regexp.MustCompile(`^(?:` + syntax.Pattern + `)$`),
+			guarded:   strings.HasPrefix(syntax.Pattern, "(?:^|[^"),
+		}
+	}
+	return strippers
+}
+
+// blankOut replaces every character of text with a space, keeping line breaks.
+func blankOut(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\r' {
+			return r
+		}
+		return ' '
+	}, text)
+}
+
 // extractComments extracts comments from the given source using a regex pattern.
 // This function ensures that extracted comments do not have leading newlines or tabs.
 func extractComments(source string, pattern string) []string {
@@ -0,0 +1,356 @@
+package Analyzer
+
+import (
+	"io/fs"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"statfiy/FileManager"
+	"strings"
+)
+
+// DependencyEdge is a dependency of a module on an internal module or an external package.
+type DependencyEdge struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Imports  int    `json:"imports"` // Number of files of From importing To
+	External bool   `json:"external"`
+}
+
+// ModuleDependencies holds the fan-in and fan-out of a module.
+// A module is a directory relative to the analyzed root, "." being the root itself.
+type ModuleDependencies struct {
+	Module         string `json:"module"`
+	Files          int    `json:"files"`
+	FanIn          int    `json:"fanIn"`          // Internal modules depending on this module
+	FanOut         int    `json:"fanOut"`         // Internal modules this module depends on
+	ExternalFanOut int    `json:"externalFanOut"` // External packages this module depends on
+}
+
+// DependencyGraph is the import graph between the modules of the analyzed root.
+type DependencyGraph struct {
+	Modules  []ModuleDependencies `json:"modules"`
+	Edges    []DependencyEdge     `json:"edges"`
+	External []string             `json:"external"`
+}
+
+var goModulePattern = regexp.MustCompile(`(?m)^\s*module\s+(\S+)`)
+
+// BuildDependencyGraph resolves the imports of every file to internal modules or external packages
+// and counts the fan-in and fan-out of every module. Go imports are resolved with the module path
+// of the go.mod file at the root, if any.
+//
+// Args:
+//   - rootPath: The analyzed root.
+//   - results: A slice of AnalyzeFileResult with their Imports.
+//
+// Returns:
+//   - DependencyGraph: The modules, edges and external packages, sorted by name.
+func BuildDependencyGraph(rootPath string, results []AnalyzeFileResult) DependencyGraph {
+	return BuildDependencyGraphFS(FileManager.OSFS, rootPath, results)
+}
+
+// BuildDependencyGraphFS is like BuildDependencyGraph but reads go.mod from a filesystem.
+//
+// Args:
+//   - fsys: The filesystem the root is read from.
+//   - rootPath: The analyzed root.
+//   - results: A slice of AnalyzeFileResult with their Imports.
+//
+// Returns:
+//   - DependencyGraph: The modules, edges and external packages, sorted by name.
+func BuildDependencyGraphFS(fsys fs.FS, rootPath string, results []AnalyzeFileResult) DependencyGraph {
+	return BuildDependencyGraphWithModule(ReadGoModulePathFS(fsys, rootPath), rootPath, results)
+}
+
+// BuildDependencyGraphWithModule is like BuildDependencyGraph but takes the Go module path instead of
+// reading go.mod, e.g. the one stored with an analysis.
+//
+// Args:
+//   - goModule: The module path Go imports are resolved with, empty without go.mod.
+//   - rootPath: The analyzed root.
+//   - results: A slice of AnalyzeFileResult with their Imports.
+//
+// Returns:
+//   - DependencyGraph: The modules, edges and external packages, sorted by name.
+func BuildDependencyGraphWithModule(goModule, rootPath string, results []AnalyzeFileResult) DependencyGraph {
+	relativePaths := make([]string, len(results))
+	for i, result := range results {
+		relativePath, err := FileManager.GetRelativePath(rootPath, result.FileMetadata.Path)
+		if err != nil {
+			relativePath = result.FileMetadata.Path
+		}
+		relativePaths[i] = filepath.ToSlash(relativePath)
+	}
+
+	return buildDependencyGraph(goModule, relativePaths, results)
+}
+
+// ReadGoModulePathFS returns the module path declared by the go.mod file at a root.
+//
+// Args:
+//   - fsys: The filesystem the root is read from.
+//   - rootPath: The analyzed root.
+//
+// Returns:
+//   - string: The module path, empty if the root has no readable go.mod.
+func ReadGoModulePathFS(fsys fs.FS, rootPath string) string {
+	goMod, err := FileManager.ReadFileStringFS(fsys, filepath.Join(rootPath, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	if match := goModulePattern.FindStringSubmatch(goMod); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// moduleIndex knows the files and directories of the analyzed root, relative to it.
+type moduleIndex struct {
+	directories map[string]bool
+	files       map[string]bool // Relative paths without extension
+	suffixes    map[string]string
+}
+
+func newModuleIndex(relativePaths []string) moduleIndex {
+	index := moduleIndex{
+		directories: map[string]bool{".": true},
+		files:       make(map[string]bool),
+		suffixes:    make(map[string]string),
+	}
+
+	for _, relativePath := range relativePaths {
+		withoutExtension := strings.TrimSuffix(relativePath, path.Ext(relativePath))
+		index.files[withoutExtension] = true
+		index.addSuffixes(withoutExtension, path.Dir(relativePath))
+
+		for dir := path.Dir(relativePath); dir != "." && !index.directories[dir]; dir = path.Dir(dir) {
+			index.directories[dir] = true
+			index.addSuffixes(dir, dir)
+		}
+	}
+
+	return index
+}
+
+// addSuffixes maps every trailing part of a path (b/c, c for a/b/c) to its module, keeping the first one found.
+func (index moduleIndex) addSuffixes(slashPath, module string) {
+	parts := strings.Split(slashPath, "/")
+	for i := range parts {
+		suffix := strings.Join(parts[i:], "/")
+		if _, found := index.suffixes[suffix]; !found {
+			index.suffixes[suffix] = module
+		}
+	}
+}
+
+// resolve returns the module of the longest prefix of segments found under base.
+func (index moduleIndex) resolve(base string, segments []string) (string, bool) {
+	for n := len(segments); n > 0; n-- {
+		candidate := path.Join(append([]string{base}, segments[:n]...)...)
+		if index.directories[candidate] {
+			return candidate, true
+		}
+		if index.files[candidate] {
+			return path.Dir(candidate), true
+		}
+	}
+	return "", false
+}
+
+// resolveSuffix returns the module of the longest prefix of segments matching the end of a known path.
+// At least minSegments segments must match.
+func (index moduleIndex) resolveSuffix(segments []string, minSegments int) (string, bool) {
+	for n := len(segments); n >= minSegments && n > 0; n-- {
+		if module, found := index.suffixes[strings.Join(segments[:n], "/")]; found {
+			return module, true
+		}
+	}
+	return "", false
+}
+
+// resolveImport returns the internal module an import refers to,
+// or the external package name and false.
+func (index moduleIndex) resolveImport(goModule, relativePath string, lang Language, imported string) (string, bool) {
+	dir := path.Dir(relativePath)
+
+	switch lang {
+	case Go:
+		if goModule != "" && (imported == goModule || strings.HasPrefix(imported, goModule+"/")) {
+			return path.Join(".", strings.TrimPrefix(imported, goModule)), true
+		}
+		return imported, false
+
+	case Python:
+		dots := len(imported) - len(strings.TrimLeft(imported, "."))
+		segments := strings.Split(imported[dots:], ".")
+		if imported[dots:] == "" {
+			segments = nil
+		}
+		if dots > 0 {
+			base := dir
+			for i := 1; i < dots; i++ {
+				base = path.Dir(base)
+			}
+			if module, found := index.resolve(base, segments); found {
+				return module, true
+			}
+			return base, true
+		}
+		for _, base := range []string{".", "src", dir} {
+			if module, found := index.resolve(base, segments); found {
+				return module, true
+			}
+		}
+		return segments[0], false
+
+	case JavaScript, TypeScript:
+		if strings.HasPrefix(imported, ".") || strings.HasPrefix(imported, "/") {
+			target := path.Join(dir, imported)
+			if strings.HasPrefix(imported, "/") {
+				target = path.Clean(strings.TrimPrefix(imported, "/"))
+			}
+			if index.directories[target] {
+				return target, true
+			}
+			return path.Dir(target), true
+		}
+		parts := strings.Split(imported, "/")
+		if strings.HasPrefix(imported, "@") && len(parts) > 1 {
+			return parts[0] + "/" + parts[1], false
+		}
+		return parts[0], false
+
+	case Java, Kotlin:
+		segments := strings.Split(strings.TrimSuffix(imported, ".*"), ".")
+		if module, found := index.resolveSuffix(segments, min(2, len(segments))); found {
+			return module, true
+		}
+		if !strings.HasSuffix(imported, ".*") && len(segments) > 1 {
+			segments = segments[:len(segments)-1]
+		}
+		return strings.Join(segments, "."), false
+
+	case Rust:
+		segments := strings.Split(imported, "::")
+		base := ""
+		switch segments[0] {
+		case "crate":
+			base = rustCrateRoot(dir)
+		case "self":
+			base = dir
+		case "super":
+			base = path.Dir(dir)
+		default:
+			return segments[0], false
+		}
+		if module, found := index.resolve(base, segments[1:]); found {
+			return module, true
+		}
+		return base, true
+
+	case C, CPlusPlus:
+		header := imported[1 : len(imported)-1]
+		if strings.HasPrefix(imported, "<") {
+			return header, false
+		}
+		for _, base := range []string{dir, "."} {
+			candidate := strings.TrimSuffix(path.Join(base, header), path.Ext(header))
+			if index.files[candidate] {
+				return path.Dir(candidate), true
+			}
+		}
+		segments := strings.Split(strings.TrimSuffix(header, path.Ext(header)), "/")
+		if module, found := index.suffixes[strings.Join(segments, "/")]; found && len(segments) > 1 {
+			return module, true
+		}
+		return header, false
+	}
+
+	return imported, false
+}
+
+// rustCrateRoot returns the nearest "src" directory containing dir, or dir itself.
+func rustCrateRoot(dir string) string {
+	for current := dir; current != "." && current != "/"; current = path.Dir(current) {
+		if path.Base(current) == "src" {
+			return current
+		}
+	}
+	return dir
+}
+
+// buildDependencyGraph builds the graph from the imports of the results and their paths relative to the root.
+func buildDependencyGraph(goModule string, relativePaths []string, results []AnalyzeFileResult) DependencyGraph {
+	index := newModuleIndex(relativePaths)
+
+	type edgeKey struct {
+		from, to string
+		external bool
+	}
+	edgeImports := make(map[edgeKey]int)
+	moduleFiles := make(map[string]int)
+
+	for i, result := range results {
+		from := path.Dir(relativePaths[i])
+		moduleFiles[from]++
+
+		fileEdges := make(map[edgeKey]bool)
+		for _, imported := range result.Imports {
+			to, internal := index.resolveImport(goModule, relativePaths[i], result.Language, imported)
+			if internal && to == from {
+				continue
+			}
+			fileEdges[edgeKey{from: from, to: to, external: !internal}] = true
+		}
+		for key := range fileEdges {
+			edgeImports[key]++
+		}
+	}
+
+	graph := DependencyGraph{}
+	modules := make(map[string]*ModuleDependencies)
+	getModule := func(name string) *ModuleDependencies {
+		if modules[name] == nil {
+			modules[name] = &ModuleDependencies{Module: name, Files: moduleFiles[name]}
+		}
+		return modules[name]
+	}
+	for name := range moduleFiles {
+		getModule(name)
+	}
+
+	external := make(map[string]bool)
+	for key, imports := range edgeImports {
+		graph.Edges = append(graph.Edges, DependencyEdge{From: key.from, To: key.to, Imports: imports, External: key.external})
+		if key.external {
+			getModule(key.from).ExternalFanOut++
+			external[key.to] = true
+			continue
+		}
+		getModule(key.from).FanOut++
+		getModule(key.to).FanIn++
+	}
+
+	for _, module := range modules {
+		graph.Modules = append(graph.Modules, *module)
+	}
+	for name := range external {
+		graph.External = append(graph.External, name)
+	}
+
+	sort.Slice(graph.Modules, func(i, j int) bool { return graph.Modules[i].Module < graph.Modules[j].Module })
+	sort.Strings(graph.External)
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		if graph.Edges[i].From != graph.Edges[j].From {
+			return graph.Edges[i].From < graph.Edges[j].From
+		}
+		if graph.Edges[i].External != graph.Edges[j].External {
+			return !graph.Edges[i].External
+		}
+		return graph.Edges[i].To < graph.Edges[j].To
+	})
+
+	return graph
+}
@@ -0,0 +1,59 @@
+package Analyzer
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractImports(t *testing.T) {
+	goSource := "package main\n\nimport (\n\t\"fmt\"\n\tfm \"statfiy/FileManager\"\n)\nimport \"os\"\n"
+	require.Equal(t, []string{"fmt", "statfiy/FileManager", "os"}, ExtractImports(goSource, Go))
+
+	python := "import os, sys as system\nfrom . import util\nfrom ..core.models import (User,\n  Group)\n"
+	require.Equal(t, []string{"os", "sys", ".util", "..core.models.User", "..core.models.Group"}, ExtractImports(python, Python))
+
+	js := "import React from 'react';\nimport { a } from \"./lib/a\";\nconst fs = require('fs');\nimport './style.css';\n"
+	require.Equal(t, []string{"react", "./lib/a", "./style.css", "fs"}, ExtractImports(js, JavaScript))
+
+	rust := "use std::io;\nuse crate::net::{tcp, udp};\nmod parser;\n"
+	require.Equal(t, []string{"std::io", "crate::net", "self::parser"}, ExtractImports(rust, Rust))
+
+	c := "#include <stdio.h>\n#include \"util/strings.h\"\n"
+	require.Equal(t, []string{"<stdio.h>", "\"util/strings.h\""}, ExtractImports(c, C))
+}
+
+func TestBuildDependencyGraph(t *testing.T) {
+	relativePaths := []string{"main.go", "Analyzer/analyzer.go", "FileManager/read.go", "web/app.js", "web/lib/a.js"}
+	results := []AnalyzeFileResult{
+		{Language: Go, Imports: []string{"fmt", "statfiy/Analyzer", "statfiy/FileManager"}},
+		{Language: Go, Imports: []string{"statfiy/FileManager", "strings"}},
+		{Language: Go, Imports: []string{"os"}},
+		{Language: JavaScript, Imports: []string{"./lib/a", "react"}},
+		{Language: JavaScript, Imports: []string{"../app"}},
+	}
+
+	graph := buildDependencyGraph("statfiy", relativePaths, results)
+
+	modules := make(map[string]ModuleDependencies)
+	for _, module := range graph.Modules {
+		modules[module.Module] = module
+	}
+	require.Equal(t, ModuleDependencies{Module: ".", Files: 1, FanOut: 2, ExternalFanOut: 1}, modules["."])
+	require.Equal(t, ModuleDependencies{Module: "FileManager", Files: 1, FanIn: 2, ExternalFanOut: 1}, modules["FileManager"])
+	require.Equal(t, 1, modules["web"].FanIn)
+	require.Equal(t, 1, modules["web/lib"].FanIn)
+	require.Equal(t, []string{"fmt", "os", "react", "strings"}, graph.External)
+	require.Equal(t, DependencyEdge{From: ".", To: "Analyzer", Imports: 1}, graph.Edges[0])
+}
+
+func TestReadGoModulePathFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"repo/go.mod":  {Data: []byte("// comment\nmodule example.com/repo\n\ngo 1.22\n")},
+		"other/go.mod": {Data: []byte("go 1.22\n")},
+	}
+	require.Equal(t, "example.com/repo", ReadGoModulePathFS(fsys, "repo"))
+	require.Empty(t, ReadGoModulePathFS(fsys, "other"))
+	require.Empty(t, ReadGoModulePathFS(fsys, "missing"))
+}
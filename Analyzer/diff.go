@@ -0,0 +1,169 @@
+package Analyzer
+
+import (
+	"path/filepath"
+	"sort"
+	"statfiy/FileManager"
+)
+
+// FileChange describes how a file differs between two snapshots.
+type FileChange string
+
+const (
+	FileAdded     FileChange = "added"
+	FileRemoved   FileChange = "removed"
+	FileChanged   FileChange = "changed"
+	FileUnchanged FileChange = "unchanged"
+)
+
+// FileDelta is the difference of a single file between two snapshots.
+// Deltas are after minus before; a missing side counts as zero.
+type FileDelta struct {
+	Path             string // Path relative to the snapshot roots
+	Language         Language
+	Change           FileChange
+	CodeSizeDelta    int64
+	CommentSizeDelta int64
+	BlankLinesDelta  int
+}
+
+// LanguageDelta is the difference of a language between two snapshots.
+type LanguageDelta struct {
+	Language         Language
+	FilesBefore      int
+	FilesAfter       int
+	CodeSizeBefore   int64
+	CodeSizeAfter    int64
+	CodeSizeDelta    int64
+	CommentSizeDelta int64
+	BlankLinesDelta  int
+}
+
+// SnapshotDiff holds the differences between two snapshots.
+type SnapshotDiff struct {
+	Languages []LanguageDelta // Largest absolute code size change first
+	Files     []FileDelta     // Added, removed and changed files, sorted by path
+	Added     int
+	Removed   int
+	Changed   int
+	Unchanged int
+}
+
+// DiffSnapshots compares two snapshots file by file. Files are matched by their path
+// relative to the root of their snapshot, so two checkouts of a project in different
+// directories can be compared.
+//
+// Args:
+//   - before: The older snapshot.
+//   - after: The newer snapshot.
+//
+// Returns:
+//   - SnapshotDiff: The per-file and per-language differences.
+func DiffSnapshots(before, after Snapshot) SnapshotDiff {
+	beforeFiles := resultsByRelativePath(before)
+	afterFiles := resultsByRelativePath(after)
+
+	diff := SnapshotDiff{}
+	languages := make(map[Language]*LanguageDelta)
+	languageDelta := func(lang Language) *LanguageDelta {
+		if languages[lang] == nil {
+			languages[lang] = &LanguageDelta{Language: lang}
+		}
+		return languages[lang]
+	}
+
+	for relativePath, old := range beforeFiles {
+		delta := languageDelta(old.Language)
+		delta.FilesBefore++
+		delta.CodeSizeBefore += old.CodeSize
+		delta.CodeSizeDelta -= old.CodeSize
+		delta.CommentSizeDelta -= old.CommentSize
+		delta.BlankLinesDelta -= old.BlankLines
+
+		if _, found := afterFiles[relativePath]; !found {
+			diff.Files = append(diff.Files, fileDelta(relativePath, old.Language, FileRemoved, AnalyzeFileResult{}, old))
+		}
+	}
+
+	for relativePath, current := range afterFiles {
+		delta := languageDelta(current.Language)
+		delta.FilesAfter++
+		delta.CodeSizeAfter += current.CodeSize
+		delta.CodeSizeDelta += current.CodeSize
+		delta.CommentSizeDelta += current.CommentSize
+		delta.BlankLinesDelta += current.BlankLines
+
+		old, found := beforeFiles[relativePath]
+		change := FileAdded
+		if found {
+			change = FileChanged
+			if old.TotalSize == current.TotalSize && old.CodeSize == current.CodeSize &&
+				old.CommentSize == current.CommentSize && old.BlankLines == current.BlankLines &&
+				old.FileMetadata.Size == current.FileMetadata.Size {
+				change = FileUnchanged
+			}
+		}
+
+		if change == FileUnchanged {
+			diff.Unchanged++
+			continue
+		}
+		diff.Files = append(diff.Files, fileDelta(relativePath, current.Language, change, current, old))
+	}
+
+	for _, file := range diff.Files {
+		switch file.Change {
+		case FileAdded:
+			diff.Added++
+		case FileRemoved:
+			diff.Removed++
+		case FileChanged:
+			diff.Changed++
+		}
+	}
+
+	for _, delta := range languages {
+		diff.Languages = append(diff.Languages, *delta)
+	}
+	sort.Slice(diff.Languages, func(i, j int) bool {
+		a, b := abs(diff.Languages[i].CodeSizeDelta), abs(diff.Languages[j].CodeSizeDelta)
+		if a != b {
+			return a > b
+		}
+		return diff.Languages[i].Language.String() < diff.Languages[j].Language.String()
+	})
+	sort.Slice(diff.Files, func(i, j int) bool { return diff.Files[i].Path < diff.Files[j].Path })
+
+	return diff
+}
+
+func fileDelta(relativePath string, lang Language, change FileChange, current, old AnalyzeFileResult) FileDelta {
+	return FileDelta{
+		Path:             relativePath,
+		Language:         lang,
+		Change:           change,
+		CodeSizeDelta:    current.CodeSize - old.CodeSize,
+		CommentSizeDelta: current.CommentSize - old.CommentSize,
+		BlankLinesDelta:  current.BlankLines - old.BlankLines,
+	}
+}
+
+// resultsByRelativePath indexes the results of a snapshot by their slash separated path relative to its root.
+func resultsByRelativePath(snapshot Snapshot) map[string]AnalyzeFileResult {
+	files := make(map[string]AnalyzeFileResult, len(snapshot.Results))
+	for _, result := range snapshot.Results {
+		relativePath, err := FileManager.GetRelativePath(snapshot.RootPath, result.FileMetadata.Path)
+		if err != nil {
+			relativePath = result.FileMetadata.Path
+		}
+		files[filepath.ToSlash(relativePath)] = result
+	}
+	return files
+}
+
+func abs(value int64) int64 {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
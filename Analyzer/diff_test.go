@@ -0,0 +1,66 @@
+package Analyzer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	file := func(path string, lang Language, code, comment int64, blank int) AnalyzeFileResult {
+		return AnalyzeFileResult{
+			FileMetadata: FileMetadata{Path: path},
+			Language:     lang,
+			CodeSize:     code,
+			CommentSize:  comment,
+			BlankLines:   blank,
+			TotalSize:    code + comment + int64(blank),
+		}
+	}
+	before := Snapshot{RootPath: "/old", Results: []AnalyzeFileResult{
+		file("/old/main.go", Go, 100, 10, 5),
+		file("/old/util.go", Go, 50, 0, 2),
+		file("/old/build.sh", Bash, 25, 5, 1),
+	}}
+	after := Snapshot{RootPath: "/new", Results: []AnalyzeFileResult{
+		file("/new/main.go", Go, 80, 30, 5),
+		file("/new/util.go", Go, 50, 0, 2),
+		file("/new/tool.py", Python, 40, 0, 3),
+	}}
+
+	diff := DiffSnapshots(before, after)
+	require.Equal(t, 1, diff.Added)
+	require.Equal(t, 1, diff.Removed)
+	require.Equal(t, 1, diff.Changed)
+	require.Equal(t, 1, diff.Unchanged)
+	require.Equal(t, []FileDelta{
+		{Path: "build.sh", Language: Bash, Change: FileRemoved, CodeSizeDelta: -25, CommentSizeDelta: -5, BlankLinesDelta: -1},
+		{Path: "main.go", Language: Go, Change: FileChanged, CodeSizeDelta: -20, CommentSizeDelta: 20},
+		{Path: "tool.py", Language: Python, Change: FileAdded, CodeSizeDelta: 40, BlankLinesDelta: 3},
+	}, diff.Files)
+
+	require.Equal(t, []Language{Python, Bash, Go}, []Language{diff.Languages[0].Language, diff.Languages[1].Language, diff.Languages[2].Language})
+	require.Equal(t, LanguageDelta{
+		Language: Go, FilesBefore: 2, FilesAfter: 2,
+		CodeSizeBefore: 150, CodeSizeAfter: 130, CodeSizeDelta: -20, CommentSizeDelta: 20,
+	}, diff.Languages[2])
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	snapshotPath := filepath.Join(t.TempDir(), "snapshot.json")
+	snapshot := NewSnapshot("/repo", []AnalyzeFileResult{{
+		FileMetadata: FileMetadata{Name: "main.go", Path: "/repo/main.go"},
+		Language:     Go,
+		CodeSize:     42,
+		Imports:      []string{"fmt"},
+	}})
+
+	require.NoError(t, WriteSnapshot(snapshotPath, snapshot))
+	loaded, err := ReadSnapshot(snapshotPath)
+	require.NoError(t, err)
+	require.Equal(t, snapshot.RootPath, loaded.RootPath)
+	require.Equal(t, snapshot.Results[0].Language, loaded.Results[0].Language)
+	require.Equal(t, snapshot.Results[0].CodeSize, loaded.Results[0].CodeSize)
+	require.Equal(t, snapshot.Results[0].Imports, loaded.Results[0].Imports)
+}
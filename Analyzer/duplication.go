@@ -0,0 +1,299 @@
+package Analyzer
+
+import (
+	"hash/fnv"
+	"io/fs"
+	"sort"
+	"statfiy/FileManager"
+)
+
+// DuplicationConfig holds the settings used by the copy-paste detector.
+type DuplicationConfig struct {
+	MinTokens      int // Minimum length of a duplicated block, in tokens
+	MaxOccurrences int // Token windows occurring more often than this are skipped
+}
+
+// DefaultDuplicationConfig is used when no explicit configuration is given.
+var DefaultDuplicationConfig = DuplicationConfig{
+	MinTokens:      50,
+	MaxOccurrences: 100,
+}
+
+// CloneLocation points to a duplicated block inside a file.
+type CloneLocation struct {
+	Path      string
+	StartLine int
+	EndLine   int
+}
+
+// ClonePair represents two locations sharing the same normalized token sequence.
+type ClonePair struct {
+	Language Language
+	First    CloneLocation
+	Second   CloneLocation
+	Tokens   int // Length of the duplicated block in tokens
+}
+
+// LanguageDuplication summarizes duplication for a single language.
+type LanguageDuplication struct {
+	TotalLines      int     // Lines containing code, after comments are stripped
+	DuplicatedLines int     // Lines covered by at least one clone
+	Percentage      float64 // DuplicatedLines relative to TotalLines (0-100)
+}
+
+// DuplicationReport is the result of DetectDuplicates.
+type DuplicationReport struct {
+	Pairs     []ClonePair
+	Languages map[Language]LanguageDuplication
+	Skipped   []string // Files that couldn't be read and were left out
+}
+
+// tokenizedFile holds the normalized tokens of a single file.
+type tokenizedFile struct {
+	path       string
+	tokens     []Token
+	normalized []uint64
+	codeLines  map[int]bool
+}
+
+// clonePosition is the start of a token window inside a tokenized file.
+type clonePosition struct {
+	file  int
+	index int
+}
+
+// DetectDuplicates finds duplicated code fragments across the analyzed files.
+// Comments are stripped before tokenizing, and identifiers, numbers and strings are
+// normalized, so renamed copies are detected as well. Files are only compared with
+// files of the same language.
+//
+// Arguments:
+//   - results: The analysis results of the files to compare.
+//   - config: The detector settings.
+//
+// Returns:
+//   - DuplicationReport: The clone pairs and per-language duplication percentages; files that can't be
+//     read are listed in Skipped.
+func DetectDuplicates(results []AnalyzeFileResult, config DuplicationConfig) DuplicationReport {
+	return DetectDuplicatesFS(FileManager.OSFS, results, config)
+}
+
+// DetectDuplicatesFS is like DetectDuplicates but reads the files from a filesystem.
+//
+// Arguments:
+//   - fsys: The filesystem the files are read from.
+//   - results: The analysis results of the files to compare.
+//   - config: The detector settings.
+//
+// Returns:
+//   - DuplicationReport: The clone pairs and per-language duplication percentages; files that can't be
+//     read are listed in Skipped.
+func DetectDuplicatesFS(fsys fs.FS, results []AnalyzeFileResult, config DuplicationConfig) DuplicationReport {
+	if config.MinTokens <= 0 {
+		config.MinTokens = DefaultDuplicationConfig.MinTokens
+	}
+	if config.MaxOccurrences <= 0 {
+		config.MaxOccurrences = DefaultDuplicationConfig.MaxOccurrences
+	}
+
+	filesByLanguage := make(map[Language][]tokenizedFile)
+	var skipped []string
+	for _, result := range results {
+		if result.Language == Unknown {
+			continue
+		}
+
+		source, err := FileManager.ReadFileStringFS(fsys, result.FileMetadata.Path)
+		if err != nil {
+			skipped = append(skipped, result.FileMetadata.Path)
+			continue
+		}
+
+		filesByLanguage[result.Language] = append(filesByLanguage[result.Language],
+			tokenizeForDuplication(result.FileMetadata.Path, source, result.Language))
+	}
+
+	report := DuplicationReport{Languages: make(map[Language]LanguageDuplication), Skipped: skipped}
+	for lang, files := range filesByLanguage {
+		pairs := findClonePairs(files, lang, config.MinTokens, config.MaxOccurrences)
+		report.Pairs = append(report.Pairs, pairs...)
+		report.Languages[lang] = summarizeDuplication(files, pairs)
+	}
+
+	sort.Slice(report.Pairs, func(i, j int) bool {
+		a, b := report.Pairs[i], report.Pairs[j]
+		if a.Tokens != b.Tokens {
+			return a.Tokens > b.Tokens
+		}
+		if a.First.Path != b.First.Path {
+			return a.First.Path < b.First.Path
+		}
+		return a.First.StartLine < b.First.StartLine
+	})
+
+	return report
+}
+
+// tokenizeForDuplication strips comments from source and hashes its normalized tokens.
+func tokenizeForDuplication(path, source string, lang Language) tokenizedFile {
+	tokens := Tokenize(StripCommentsByLanguage(source, lang))
+	file := tokenizedFile{
+		path:       path,
+		tokens:     tokens,
+		normalized: make([]uint64, len(tokens)),
+		codeLines:  make(map[int]bool),
+	}
+
+	for i, token := range tokens {
+		file.normalized[i] = hashToken(normalizeToken(token))
+		file.codeLines[token.Line] = true
+	}
+
+	return file
+}
+
+// normalizeToken replaces names and literals by placeholders so renamed copies still match.
+func normalizeToken(token Token) string {
+	switch token.Kind {
+	case TokenIdentifier:
+		return "$id"
+	case TokenNumber:
+		return "$num"
+	case TokenString:
+		return "$str"
+	default:
+		return token.Text
+	}
+}
+
+func hashToken(text string) uint64 {
+	hasher := fnv.New64a()
+	hasher.Write([]byte(text))
+	return hasher.Sum64()
+}
+
+// findClonePairs uses a rolling hash over windows of minTokens tokens to find matching
+// windows, then extends every match to its maximal length.
+// Every window is paired with every other window of the same hash, so windows occurring more than
+// maxOccurrences times, like generated tables or long runs of the same statement, are skipped to
+// keep the pairing from growing quadratically.
+func findClonePairs(files []tokenizedFile, lang Language, minTokens, maxOccurrences int) []ClonePair {
+	const base uint64 = 1099511628211
+
+	// base^(minTokens-1), used to remove the leading token from the rolling hash.
+	var highPower uint64 = 1
+	for i := 1; i < minTokens; i++ {
+		highPower *= base
+	}
+
+	windows := make(map[uint64][]clonePosition)
+	for fileIndex, file := range files {
+		if len(file.normalized) < minTokens {
+			continue
+		}
+
+		var hash uint64
+		for i, value := range file.normalized {
+			if i >= minTokens {
+				hash -= file.normalized[i-minTokens] * highPower
+			}
+			hash = hash*base + value
+
+			if i >= minTokens-1 {
+				windows[hash] = append(windows[hash], clonePosition{file: fileIndex, index: i - minTokens + 1})
+			}
+		}
+	}
+
+	var pairs []ClonePair
+	for _, positions := range windows {
+		if len(positions) > maxOccurrences {
+			continue
+		}
+		for i := 0; i < len(positions); i++ {
+			for j := i + 1; j < len(positions); j++ {
+				if pair, ok := extendClone(files, positions[i], positions[j], minTokens); ok {
+					pair.Language = lang
+					pairs = append(pairs, pair)
+				}
+			}
+		}
+	}
+
+	return pairs
+}
+
+// extendClone verifies that two windows really match and grows them to the right.
+// Only left-maximal matches are reported, so every clone is found exactly once.
+func extendClone(files []tokenizedFile, a, b clonePosition, minTokens int) (ClonePair, bool) {
+	first, second := files[a.file].normalized, files[b.file].normalized
+
+	if a.index > 0 && b.index > 0 && first[a.index-1] == second[b.index-1] {
+		return ClonePair{}, false
+	}
+
+	length := 0
+	for a.index+length < len(first) && b.index+length < len(second) &&
+		first[a.index+length] == second[b.index+length] {
+		length++
+	}
+
+	// Don't let a block overlap with its own copy inside the same file.
+	if a.file == b.file && a.index+length > b.index {
+		length = b.index - a.index
+	}
+
+	if length < minTokens {
+		return ClonePair{}, false
+	}
+
+	return ClonePair{
+		First:  cloneLocation(files[a.file], a.index, length),
+		Second: cloneLocation(files[b.file], b.index, length),
+		Tokens: length,
+	}, true
+}
+
+func cloneLocation(file tokenizedFile, index, length int) CloneLocation {
+	return CloneLocation{
+		Path:      file.path,
+		StartLine: file.tokens[index].Line,
+		EndLine:   file.tokens[index+length-1].Line,
+	}
+}
+
+// summarizeDuplication counts the code lines of a language covered by at least one clone.
+func summarizeDuplication(files []tokenizedFile, pairs []ClonePair) LanguageDuplication {
+	duplicated := make(map[string]map[int]bool)
+	markLines := func(location CloneLocation, codeLines map[int]bool) {
+		if duplicated[location.Path] == nil {
+			duplicated[location.Path] = make(map[int]bool)
+		}
+		for line := location.StartLine; line <= location.EndLine; line++ {
+			if codeLines[line] {
+				duplicated[location.Path][line] = true
+			}
+		}
+	}
+
+	codeLinesByPath := make(map[string]map[int]bool)
+	summary := LanguageDuplication{}
+	for _, file := range files {
+		codeLinesByPath[file.path] = file.codeLines
+		summary.TotalLines += len(file.codeLines)
+	}
+
+	for _, pair := range pairs {
+		markLines(pair.First, codeLinesByPath[pair.First.Path])
+		markLines(pair.Second, codeLinesByPath[pair.Second.Path])
+	}
+
+	for _, lines := range duplicated {
+		summary.DuplicatedLines += len(lines)
+	}
+	if summary.TotalLines > 0 {
+		summary.Percentage = float64(summary.DuplicatedLines) / float64(summary.TotalLines) * 100
+	}
+
+	return summary
+}
@@ -0,0 +1,70 @@
+package Analyzer
+
+import (
+	"statfiy/FileManager"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStripCommentsByLanguage(t *testing.T) {
+	source := "int a = 1;// first\n/* second\n third */ int b = 2;\n"
+	stripped := StripCommentsByLanguage(source, C)
+
+	require.Equal(t, strings.Count(source, "\n"), strings.Count(stripped, "\n"))
+	require.Contains(t, stripped, "int a = 1;")
+	require.Contains(t, stripped, "int b = 2;")
+	require.NotContains(t, stripped, "first")
+	require.NotContains(t, stripped, "second")
+}
+
+func TestFindClonePairs(t *testing.T) {
+	body := "int sum(int a, int b) {\n  int total = a + b;\n  return total * 2;\n}\n"
+	renamed := "// another copy\nint add(int x, int y) {\n  int result = x + y;\n  return result * 3;\n}\n"
+	different := "void log(char *message) {\n  printf(\"%s\", message);\n}\n"
+
+	files := []tokenizedFile{
+		tokenizeForDuplication("a.c", body, C),
+		tokenizeForDuplication("b.c", renamed, C),
+		tokenizeForDuplication("c.c", different, C),
+	}
+
+	pairs := findClonePairs(files, C, 10, 100)
+	require.Len(t, pairs, 1)
+	require.Equal(t, "a.c", pairs[0].First.Path)
+	require.Equal(t, "b.c", pairs[0].Second.Path)
+	require.Equal(t, 1, pairs[0].First.StartLine)
+	require.Equal(t, 2, pairs[0].Second.StartLine)
+
+	summary := summarizeDuplication(files, pairs)
+	require.Equal(t, 11, summary.TotalLines)
+	require.Equal(t, 8, summary.DuplicatedLines)
+}
+
+func TestFindClonePairsSkipsFrequentWindows(t *testing.T) {
+	body := "int sum(int a, int b) {\n  int total = a + b;\n  return total * 2;\n}\n"
+
+	files := []tokenizedFile{
+		tokenizeForDuplication("a.c", body, C),
+		tokenizeForDuplication("b.c", body, C),
+		tokenizeForDuplication("c.c", body, C),
+	}
+
+	require.Len(t, findClonePairs(files, C, 10, 3), 3)
+	require.Empty(t, findClonePairs(files, C, 10, 2))
+}
+
+func TestDetectDuplicatesFSSkipsUnreadableFiles(t *testing.T) {
+	body := "int sum(int a, int b) {\n  int total = a + b;\n  return total * 2;\n}\n"
+	fsys := fstest.MapFS{"a.c": {Data: []byte(body)}, "b.c": {Data: []byte(body)}}
+	var results []AnalyzeFileResult
+	for _, path := range []string{"a.c", "missing.c", "b.c"} {
+		results = append(results, AnalyzeFileResult{FileMetadata: FileManager.FileMetadata{Path: path}, Language: C})
+	}
+
+	report := DetectDuplicatesFS(fsys, results, DuplicationConfig{MinTokens: 10})
+	require.Len(t, report.Pairs, 1)
+	require.Equal(t, []string{"missing.c"}, report.Skipped)
+}
@@ -0,0 +1,267 @@
+package Analyzer
+
+import (
+	"math"
+	"sort"
+)
+
+// HalsteadMetrics holds the operator and operand counts of a file.
+type HalsteadMetrics struct {
+	DistinctOperators int // n1
+	DistinctOperands  int // n2
+	TotalOperators    int // N1
+	TotalOperands     int // N2
+}
+
+// Vocabulary returns n1 + n2.
+func (h HalsteadMetrics) Vocabulary() int {
+	return h.DistinctOperators + h.DistinctOperands
+}
+
+// Length returns N1 + N2.
+func (h HalsteadMetrics) Length() int {
+	return h.TotalOperators + h.TotalOperands
+}
+
+// Volume returns N * log2(n).
+func (h HalsteadMetrics) Volume() float64 {
+	if h.Vocabulary() == 0 {
+		return 0
+	}
+	return float64(h.Length()) * math.Log2(float64(h.Vocabulary()))
+}
+
+// Difficulty returns (n1 / 2) * (N2 / n2).
+func (h HalsteadMetrics) Difficulty() float64 {
+	if h.DistinctOperands == 0 {
+		return 0
+	}
+	return float64(h.DistinctOperators) / 2 * float64(h.TotalOperands) / float64(h.DistinctOperands)
+}
+
+// Effort returns Difficulty * Volume.
+func (h HalsteadMetrics) Effort() float64 {
+	return h.Difficulty() * h.Volume()
+}
+
+// MaintainabilityDistribution summarizes the maintainability of all files of a language.
+type MaintainabilityDistribution struct {
+	Files             int
+	Min               float64
+	Median            float64
+	Mean              float64
+	Max               float64
+	AverageVolume     float64
+	AverageDifficulty float64
+	AverageEffort     float64
+	AverageComplexity float64
+	BelowThreshold    int
+}
+
+// languageFamily groups languages that share operator keywords.
+type languageFamily int
+
+const (
+	familyCLike languageFamily = iota
+	familyScripting
+	familyOther
+)
+
+var languageToFamily = map[Language]languageFamily{
+	Go: familyCLike, C: familyCLike, CPlusPlus: familyCLike, CSharp: familyCLike, Rust: familyCLike,
+	JavaScript: familyCLike, TypeScript: familyCLike, Java: familyCLike, Kotlin: familyCLike, Swift: familyCLike,
+	PHP: familyCLike, Dart: familyCLike, Scala: familyCLike, ObjectiveC: familyCLike, Zig: familyCLike,
+	Python: familyScripting, Ruby: familyScripting, Perl: familyScripting, Lua: familyScripting, R: familyScripting,
+	Elixir: familyScripting, Julia: familyScripting, Bash: familyScripting, Shell: familyScripting, PowerShell: familyScripting,
+}
+
+// familyOperatorKeywords lists words that act as operators only in some language families,
+// in addition to the shared keywords.
+var familyOperatorKeywords = map[languageFamily]map[string]bool{
+	familyCLike: {
+		"sizeof": true, "typeof": true, "instanceof": true, "typedef": true, "void": true,
+		"this": true, "self": true, "super": true, "where": true, "unsafe": true,
+	},
+	familyScripting: {
+		"pass": true, "del": true, "global": true, "nonlocal": true, "local": true, "unless": true,
+		"with": true, "assert": true, "print": true, "echo": true, "fi": true, "esac": true, "done": true,
+	},
+}
+
+// closingBrackets are not counted separately, the opening bracket stands for the pair.
+var closingBrackets = map[string]bool{")": true, "]": true, "}": true}
+
+// decisionKeywords and decisionOperators add a path to the cyclomatic complexity.
+var decisionKeywords = map[string]bool{
+	"if": true, "elif": true, "elsif": true, "elseif": true, "unless": true,
+	"for": true, "foreach": true, "while": true, "until": true,
+	"case": true, "when": true, "catch": true, "except": true, "rescue": true,
+	"and": true, "or": true,
+}
+var decisionOperators = map[string]bool{"&&": true, "||": true, "?": true}
+
+// CalculateHalstead counts the operators and operands of the tokens of a file.
+// Keywords, punctuation and operators count as operators; identifiers and literals as operands.
+//
+// Arguments:
+//   - tokens: The tokens of the comment-stripped source.
+//   - lang: The programming language of the source.
+//
+// Returns:
+//   - HalsteadMetrics: The operator and operand counts.
+func CalculateHalstead(tokens []Token, lang Language) HalsteadMetrics {
+	family, found := languageToFamily[lang]
+	if !found {
+		family = familyOther
+	}
+
+	operators := make(map[string]bool)
+	operands := make(map[string]bool)
+	metrics := HalsteadMetrics{}
+
+	for _, token := range tokens {
+		switch {
+		case token.Kind == TokenOperator && closingBrackets[token.Text]:
+			continue
+		case token.Kind == TokenOperator || token.Kind == TokenKeyword || familyOperatorKeywords[family][token.Text]:
+			operators[token.Text] = true
+			metrics.TotalOperators++
+		default:
+			operands[token.Text] = true
+			metrics.TotalOperands++
+		}
+	}
+
+	metrics.DistinctOperators = len(operators)
+	metrics.DistinctOperands = len(operands)
+	return metrics
+}
+
+// CalculateComplexity estimates the cyclomatic complexity of a whole file
+// as one plus the number of decision points.
+//
+// Arguments:
+//   - tokens: The tokens of the comment-stripped source.
+//
+// Returns:
+//   - int: The estimated cyclomatic complexity.
+func CalculateComplexity(tokens []Token) int {
+	complexity := 1
+	for _, token := range tokens {
+		if (token.Kind == TokenKeyword && decisionKeywords[token.Text]) ||
+			(token.Kind == TokenOperator && decisionOperators[token.Text]) {
+			complexity++
+		}
+	}
+	return complexity
+}
+
+// CalculateMaintainabilityIndex combines Halstead volume, cyclomatic complexity and lines of code
+// into the maintainability index, normalized to 0-100 where higher is better.
+//
+// Arguments:
+//   - volume: The Halstead volume.
+//   - complexity: The cyclomatic complexity.
+//   - codeLines: The number of lines containing code.
+//
+// Returns:
+//   - float64: The maintainability index.
+func CalculateMaintainabilityIndex(volume float64, complexity int, codeLines int) float64 {
+	if volume <= 0 || codeLines <= 0 {
+		return 100
+	}
+
+	index := 171 - 5.2*math.Log(volume) - 0.23*float64(complexity) - 16.2*math.Log(float64(codeLines))
+	return math.Max(0, math.Min(100, index*100/171))
+}
+
+// countCodeLines returns the number of distinct lines containing at least one token.
+func countCodeLines(tokens []Token) int {
+	lines := 0
+	lastLine := 0
+	for _, token := range tokens {
+		if token.Line != lastLine {
+			lines++
+			lastLine = token.Line
+		}
+	}
+	return lines
+}
+
+// CalculateMaintainabilityDistributions summarizes the maintainability index per language.
+//
+// Args:
+//   - results: A slice of AnalyzeFileResult containing analysis data for multiple files.
+//   - threshold: Files below this maintainability index are counted in BelowThreshold.
+//
+// Returns:
+//   - map[Language]MaintainabilityDistribution: The distribution per language.
+func CalculateMaintainabilityDistributions(results []AnalyzeFileResult, threshold float64) map[Language]MaintainabilityDistribution {
+	indexes := make(map[Language][]float64)
+	distributions := make(map[Language]MaintainabilityDistribution)
+
+	for _, result := range results {
+		indexes[result.Language] = append(indexes[result.Language], result.MaintainabilityIndex)
+
+		d := distributions[result.Language]
+		d.Files++
+		d.AverageVolume += result.Halstead.Volume()
+		d.AverageDifficulty += result.Halstead.Difficulty()
+		d.AverageEffort += result.Halstead.Effort()
+		d.AverageComplexity += float64(result.Complexity)
+		if result.MaintainabilityIndex < threshold {
+			d.BelowThreshold++
+		}
+		distributions[result.Language] = d
+	}
+
+	for lang, values := range indexes {
+		sort.Float64s(values)
+		d := distributions[lang]
+		files := float64(d.Files)
+
+		sum := 0.0
+		for _, value := range values {
+			sum += value
+		}
+
+		d.Min = values[0]
+		d.Max = values[len(values)-1]
+		d.Mean = sum / files
+		d.Median = values[len(values)/2]
+		if len(values)%2 == 0 {
+			d.Median = (values[len(values)/2-1] + values[len(values)/2]) / 2
+		}
+		d.AverageVolume /= files
+		d.AverageDifficulty /= files
+		d.AverageEffort /= files
+		d.AverageComplexity /= files
+
+		distributions[lang] = d
+	}
+
+	return distributions
+}
+
+// FilesBelowMaintainability returns the results whose maintainability index is below the threshold,
+// least maintainable first.
+//
+// Args:
+//   - results: A slice of AnalyzeFileResult containing analysis data for multiple files.
+//   - threshold: The maintainability index threshold.
+//
+// Returns:
+//   - []AnalyzeFileResult: The flagged results.
+func FilesBelowMaintainability(results []AnalyzeFileResult, threshold float64) []AnalyzeFileResult {
+	var flagged []AnalyzeFileResult
+	for _, result := range results {
+		if result.MaintainabilityIndex < threshold {
+			flagged = append(flagged, result)
+		}
+	}
+
+	sort.Slice(flagged, func(i, j int) bool {
+		return flagged[i].MaintainabilityIndex < flagged[j].MaintainabilityIndex
+	})
+	return flagged
+}
@@ -0,0 +1,35 @@
+package Analyzer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalculateHalstead(t *testing.T) {
+	tokens := Tokenize("if (a > b) { return a + b; }")
+	metrics := CalculateHalstead(tokens, C)
+
+	// Operators: if ( > { return + ;   Operands: a b a b
+	require.Equal(t, 7, metrics.DistinctOperators)
+	require.Equal(t, 7, metrics.TotalOperators)
+	require.Equal(t, 2, metrics.DistinctOperands)
+	require.Equal(t, 4, metrics.TotalOperands)
+	require.InDelta(t, 11*3.1699, metrics.Volume(), 0.01)
+	require.InDelta(t, 7.0, metrics.Difficulty(), 0.001)
+}
+
+func TestCalculateComplexity(t *testing.T) {
+	tokens := Tokenize("if a && b { for x { } } else if c || d { }")
+	require.Equal(t, 6, CalculateComplexity(tokens))
+}
+
+func TestCalculateMaintainabilityIndex(t *testing.T) {
+	require.Equal(t, 100.0, CalculateMaintainabilityIndex(0, 1, 0))
+
+	small := CalculateMaintainabilityIndex(100, 2, 10)
+	large := CalculateMaintainabilityIndex(10000, 40, 500)
+	require.Greater(t, small, large)
+	require.GreaterOrEqual(t, large, 0.0)
+	require.LessOrEqual(t, small, 100.0)
+}
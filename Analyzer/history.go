@@ -0,0 +1,112 @@
+package Analyzer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"statfiy/FileManager"
+	"strconv"
+	"time"
+)
+
+// HistoryPoint holds the lines per language of a repository at one commit.
+type HistoryPoint struct {
+	Commit string
+	Time   time.Time
+	Lines  map[Language]int
+}
+
+// History is a time series of the lines per language of a repository.
+type History struct {
+	Languages []Language     // Every language seen in any point, most lines at their peak first
+	Points    []HistoryPoint // Oldest first
+}
+
+// LinesOf returns the line count of a file used by the history: lines with code, plus
+// comment-only lines if requested. Blank lines are never counted.
+func (result AnalyzeFileResult) LinesOf(includeComment bool) int {
+	if includeComment {
+		return max(result.LineStats.Lines-result.BlankLines, 0)
+	}
+	return result.CodeLines
+}
+
+// BuildHistory turns snapshots of a repository at several commits into a time series of lines per language.
+//
+// Args:
+//   - snapshots: The snapshots, one per commit, with Revision and CreatedAt set to the commit hash and time.
+//   - includeComment: Whether comment-only lines are counted.
+//
+// Returns:
+//   - History: One point per snapshot, sorted by time.
+func BuildHistory(snapshots []Snapshot, includeComment bool) History {
+	history := History{}
+	peaks := make(map[Language]int)
+
+	for _, snapshot := range snapshots {
+		point := HistoryPoint{Commit: snapshot.Revision, Time: snapshot.CreatedAt, Lines: make(map[Language]int)}
+		for _, result := range snapshot.Results {
+			point.Lines[result.Language] += result.LinesOf(includeComment)
+		}
+		for lang, lines := range point.Lines {
+			peaks[lang] = max(peaks[lang], lines)
+		}
+		history.Points = append(history.Points, point)
+	}
+
+	sort.SliceStable(history.Points, func(i, j int) bool { return history.Points[i].Time.Before(history.Points[j].Time) })
+
+	for lang := range peaks {
+		history.Languages = append(history.Languages, lang)
+	}
+	sort.Slice(history.Languages, func(i, j int) bool {
+		a, b := history.Languages[i], history.Languages[j]
+		if peaks[a] != peaks[b] {
+			return peaks[a] > peaks[b]
+		}
+		return a.String() < b.String()
+	})
+
+	return history
+}
+
+// WriteHistoryCSV writes a history as CSV with one row per point and one column per language.
+//
+// Args:
+//   - filePath: The path of the CSV file.
+//   - history: The history to write.
+//
+// Returns:
+//   - error: An error if encoding or writing fails.
+func WriteHistoryCSV(filePath string, history History) error {
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+
+	header := []string{"date", "commit", "total"}
+	for _, lang := range history.Languages {
+		header = append(header, lang.String())
+	}
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to encode history: %w", err)
+	}
+
+	for _, point := range history.Points {
+		total := 0
+		columns := make([]string, len(history.Languages))
+		for i, lang := range history.Languages {
+			total += point.Lines[lang]
+			columns[i] = strconv.Itoa(point.Lines[lang])
+		}
+		row := append([]string{point.Time.UTC().Format(time.RFC3339), point.Commit, strconv.Itoa(total)}, columns...)
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("failed to encode history: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to encode history: %w", err)
+	}
+	return FileManager.OverwriteFile(filePath, buffer.Bytes())
+}
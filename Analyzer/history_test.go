@@ -0,0 +1,41 @@
+package Analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildHistory(t *testing.T) {
+	file := func(lang Language, codeLines, lines, blank int) AnalyzeFileResult {
+		return AnalyzeFileResult{Language: lang, CodeLines: codeLines, BlankLines: blank, LineStats: LineStats{Lines: lines}}
+	}
+	january := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	february := time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)
+	snapshots := []Snapshot{
+		{Revision: "b", CreatedAt: february, Results: []AnalyzeFileResult{file(Go, 50, 70, 10), file(Python, 30, 30, 0)}},
+		{Revision: "a", CreatedAt: january, Results: []AnalyzeFileResult{file(Go, 10, 14, 2), file(Go, 20, 20, 0), file(Bash, 40, 45, 5)}},
+	}
+
+	history := BuildHistory(snapshots, false)
+	require.Equal(t, []Language{Go, Bash, Python}, history.Languages)
+	require.Len(t, history.Points, 2)
+	require.Equal(t, "a", history.Points[0].Commit)
+	require.Equal(t, map[Language]int{Go: 30, Bash: 40}, history.Points[0].Lines)
+	require.Equal(t, map[Language]int{Go: 50, Python: 30}, history.Points[1].Lines)
+
+	withComments := BuildHistory(snapshots, true)
+	require.Equal(t, map[Language]int{Go: 32, Bash: 40}, withComments.Points[0].Lines)
+	require.Equal(t, map[Language]int{Go: 60, Python: 30}, withComments.Points[1].Lines)
+
+	csvPath := filepath.Join(t.TempDir(), "history.csv")
+	require.NoError(t, WriteHistoryCSV(csvPath, history))
+	content, err := os.ReadFile(csvPath)
+	require.NoError(t, err)
+	require.Equal(t, "date,commit,total,Go,Bash,Python\n"+
+		"2024-01-31T00:00:00Z,a,70,30,40,0\n"+
+		"2024-02-29T00:00:00Z,b,80,50,0,30\n", string(content))
+}
@@ -0,0 +1,121 @@
+package Analyzer
+
+import (
+	"path"
+	"path/filepath"
+	"sort"
+	"statfiy/FileManager"
+)
+
+// Hotspot is a file that changes often and is complex, where changes are both frequent and risky.
+type Hotspot struct {
+	Path         string // Slash separated path relative to the repository root
+	Language     Language
+	Commits      int // Commits changing the file within the churn window
+	LinesChanged int // Lines added plus lines deleted within the churn window
+	Complexity   int
+	CodeLines    int
+	Score        float64 // Commits × Complexity
+}
+
+// ChurnStats holds the churn of a language or a directory.
+type ChurnStats struct {
+	Name         string
+	Files        int // Files with at least one commit within the window
+	Commits      int // Distinct commits changing at least one of the files
+	LinesAdded   int
+	LinesDeleted int
+}
+
+// HotspotReport holds the churn of the analyzed files and the files ranked as hotspots.
+type HotspotReport struct {
+	Hotspots    []Hotspot    // Changed files, highest score first
+	Languages   []ChurnStats // Most changed lines first
+	Directories []ChurnStats // Directories directly containing changed files, most changed lines first
+}
+
+// BuildHotspotReport combines the churn of a repository with the size and complexity of the analyzed files.
+// Only analyzed files are reported; churn of deleted or unknown files is ignored.
+//
+// Args:
+//   - repoRoot: The root of the repository the churn paths are relative to.
+//   - results: The analyzed files, all below repoRoot.
+//   - churn: The churn per file, see FileManager.ReadGitChurn.
+//
+// Returns:
+//   - HotspotReport: The hotspots and the churn per language and directory.
+func BuildHotspotReport(repoRoot string, results []AnalyzeFileResult, churn map[string]FileManager.GitFileChurn) HotspotReport {
+	report := HotspotReport{}
+	languages := make(map[string]*ChurnStats)
+	directories := make(map[string]*ChurnStats)
+	commits := make(map[*ChurnStats]map[string]bool) // Distinct commit hashes of each stats entry
+
+	addTo := func(stats map[string]*ChurnStats, name string, file FileManager.GitFileChurn) {
+		if stats[name] == nil {
+			stats[name] = &ChurnStats{Name: name}
+			commits[stats[name]] = make(map[string]bool)
+		}
+		stats[name].Files++
+		stats[name].LinesAdded += file.LinesAdded
+		stats[name].LinesDeleted += file.LinesDeleted
+		for _, hash := range file.CommitHashes {
+			commits[stats[name]][hash] = true
+		}
+		stats[name].Commits = len(commits[stats[name]])
+	}
+
+	for _, result := range results {
+		relativePath, err := FileManager.GetRelativePath(repoRoot, result.FileMetadata.Path)
+		if err != nil {
+			continue
+		}
+		relativePath = filepath.ToSlash(relativePath)
+
+		file, found := churn[relativePath]
+		if !found || file.Commits == 0 {
+			continue
+		}
+
+		report.Hotspots = append(report.Hotspots, Hotspot{
+			Path:         relativePath,
+			Language:     result.Language,
+			Commits:      file.Commits,
+			LinesChanged: file.LinesAdded + file.LinesDeleted,
+			Complexity:   result.Complexity,
+			CodeLines:    result.CodeLines,
+			Score:        float64(file.Commits * result.Complexity),
+		})
+		addTo(languages, result.Language.String(), file)
+		addTo(directories, path.Dir(relativePath), file)
+	}
+
+	sort.Slice(report.Hotspots, func(i, j int) bool {
+		a, b := report.Hotspots[i], report.Hotspots[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.LinesChanged != b.LinesChanged {
+			return a.LinesChanged > b.LinesChanged
+		}
+		return a.Path < b.Path
+	})
+	report.Languages = sortChurnStats(languages)
+	report.Directories = sortChurnStats(directories)
+
+	return report
+}
+
+func sortChurnStats(stats map[string]*ChurnStats) []ChurnStats {
+	sorted := make([]ChurnStats, 0, len(stats))
+	for _, stat := range stats {
+		sorted = append(sorted, *stat)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i].LinesAdded+sorted[i].LinesDeleted, sorted[j].LinesAdded+sorted[j].LinesDeleted
+		if a != b {
+			return a > b
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
@@ -0,0 +1,64 @@
+package Analyzer
+
+import (
+	"fmt"
+	"path/filepath"
+	"statfiy/FileManager"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildHotspotReport(t *testing.T) {
+	root := filepath.FromSlash("/repo")
+	file := func(relativePath string, lang Language, complexity, codeLines int) AnalyzeFileResult {
+		return AnalyzeFileResult{
+			FileMetadata: FileMetadata{Path: filepath.Join(root, filepath.FromSlash(relativePath))},
+			Language:     lang,
+			Complexity:   complexity,
+			CodeLines:    codeLines,
+		}
+	}
+	results := []AnalyzeFileResult{
+		file("main.go", Go, 5, 50),
+		file("pkg/parser.go", Go, 40, 400),
+		file("pkg/lexer.go", Go, 20, 200),
+		file("scripts/build.py", Python, 3, 30),
+		file("stable.go", Go, 100, 1000),
+	}
+	// Commits first..last of c1, c2, ..., c10
+	commits := func(first, last int) []string {
+		var hashes []string
+		for i := last; i >= first; i-- {
+			hashes = append(hashes, fmt.Sprintf("c%d", i))
+		}
+		return hashes
+	}
+	churn := map[string]FileManager.GitFileChurn{
+		"main.go":          {Commits: 10, CommitHashes: commits(1, 10), LinesAdded: 30, LinesDeleted: 10},
+		"pkg/parser.go":    {Commits: 4, CommitHashes: commits(1, 4), LinesAdded: 100, LinesDeleted: 50},
+		"pkg/lexer.go":     {Commits: 8, CommitHashes: commits(3, 10), LinesAdded: 5, LinesDeleted: 5},
+		"scripts/build.py": {Commits: 2, CommitHashes: commits(1, 2), LinesAdded: 20},
+		"deleted.go":       {Commits: 9, CommitHashes: commits(1, 9), LinesDeleted: 90},
+	}
+
+	report := BuildHotspotReport(root, results, churn)
+
+	// Equal scores are ranked by changed lines
+	require.Equal(t, []Hotspot{
+		{Path: "pkg/parser.go", Language: Go, Commits: 4, LinesChanged: 150, Complexity: 40, CodeLines: 400, Score: 160},
+		{Path: "pkg/lexer.go", Language: Go, Commits: 8, LinesChanged: 10, Complexity: 20, CodeLines: 200, Score: 160},
+		{Path: "main.go", Language: Go, Commits: 10, LinesChanged: 40, Complexity: 5, CodeLines: 50, Score: 50},
+		{Path: "scripts/build.py", Language: Python, Commits: 2, LinesChanged: 20, Complexity: 3, CodeLines: 30, Score: 6},
+	}, report.Hotspots)
+
+	require.Equal(t, []ChurnStats{
+		{Name: "Go", Files: 3, Commits: 10, LinesAdded: 135, LinesDeleted: 65},
+		{Name: "Python", Files: 1, Commits: 2, LinesAdded: 20},
+	}, report.Languages)
+	require.Equal(t, []ChurnStats{
+		{Name: "pkg", Files: 2, Commits: 10, LinesAdded: 105, LinesDeleted: 55},
+		{Name: ".", Files: 1, Commits: 10, LinesAdded: 30, LinesDeleted: 10},
+		{Name: "scripts", Files: 1, Commits: 2, LinesAdded: 20},
+	}, report.Directories)
+}
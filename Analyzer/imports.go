@@ -0,0 +1,114 @@
+package Analyzer
+
+import (
+	"regexp"
+	"strings"
+)
+
+// importPatterns holds the regular expressions used to find import statements.
+// Each expression captures the imported path in its last non-empty group.
+var importPatterns = map[Language][]*regexp.Regexp{
+	Go: {
+		regexp.MustCompile(`(?m)^\s*import\s+(?:[\w.]+\s+)?"([^"]+)"`),
+	},
+	Python: {
+		regexp.MustCompile(`(?m)^[ \t]*import[ \t]+([\w. \t,]+)`),
+		regexp.MustCompile(`(?m)^[ \t]*from[ \t]+(\.*[\w.]*)[ \t]+import[ \t]+(\([^)]*\)|.+)`),
+	},
+	JavaScript: jsImportPatterns,
+	TypeScript: jsImportPatterns,
+	Java:       jvmImportPatterns,
+	Kotlin:     jvmImportPatterns,
+	Rust: {
+		regexp.MustCompile(`(?m)^\s*(?:pub(?:\([^)]*\))?\s+)?use\s+(?:::)?((?:\w+::)*\w+)`),
+		regexp.MustCompile(`(?m)^\s*(?:pub(?:\([^)]*\))?\s+)?mod\s+(\w+)\s*;`),
+		regexp.MustCompile(`(?m)^\s*extern\s+crate\s+(\w+)`),
+	},
+	C:         cIncludePatterns,
+	CPlusPlus: cIncludePatterns,
+}
+
+var goImportBlock = regexp.MustCompile(`(?m)^\s*import\s*\(([^)]*)\)`)
+var goImportSpec = regexp.MustCompile(`"([^"]+)"`)
+
+var jsImportPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?:import|export)\s[^'"` + "`" + `;]*?\sfrom\s*['"]([^'"]+)['"]`),
+	regexp.MustCompile(`(?m)^\s*import\s*['"]([^'"]+)['"]`),
+	regexp.MustCompile(`(?:\brequire|\bimport)\s*\(\s*['"]([^'"]+)['"]\s*\)`),
+}
+
+var jvmImportPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?m)^\s*import\s+(?:static\s+)?([\w.]+(?:\.\*)?)`),
+}
+
+var cIncludePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?m)^\s*#\s*include\s*(<[^>\n]+>|"[^"\n]+")`),
+}
+
+// ExtractImports returns the import paths used by a source file, without duplicates.
+// Python `from x import a, b` yields `x.a` and `x.b`, Rust `mod x;` yields `self::x`,
+// and C/C++ includes keep their delimiters (`<stdio.h>` or `"util.h"`) to tell system headers apart.
+//
+// Arguments:
+//   - stripped: The source code with comments removed, see StripCommentsByLanguage.
+//   - lang: The programming language of the source.
+//
+// Returns:
+//   - []string: The imported paths, or nil if the language isn't supported.
+func ExtractImports(stripped string, lang Language) []string {
+	var imports []string
+	seen := make(map[string]bool)
+	add := func(path string) {
+		path = strings.TrimSpace(path)
+		if path != "" && !seen[path] {
+			seen[path] = true
+			imports = append(imports, path)
+		}
+	}
+
+	if lang == Go {
+		for _, block := range goImportBlock.FindAllStringSubmatch(stripped, -1) {
+			for _, spec := range goImportSpec.FindAllStringSubmatch(block[1], -1) {
+				add(spec[1])
+			}
+		}
+	}
+
+	for index, pattern := range importPatterns[lang] {
+		for _, match := range pattern.FindAllStringSubmatch(stripped, -1) {
+			switch {
+			case lang == Python && index == 0:
+				for _, module := range strings.Split(match[1], ",") {
+					if fields := strings.Fields(module); len(fields) > 0 {
+						add(fields[0])
+					}
+				}
+			case lang == Python:
+				for _, name := range strings.Split(strings.Trim(match[2], "()\r\n\t "), ",") {
+					add(joinPythonImport(match[1], name))
+				}
+			case lang == Rust && index == 1:
+				add("self::" + match[1])
+			default:
+				add(match[len(match)-1])
+			}
+		}
+	}
+
+	return imports
+}
+
+// joinPythonImport combines the module and an imported name of `from module import name`.
+func joinPythonImport(module, name string) string {
+	fields := strings.Fields(name)
+	if len(fields) == 0 {
+		return ""
+	}
+	if fields[0] == "*" {
+		return module
+	}
+	if strings.HasSuffix(module, ".") {
+		return module + fields[0]
+	}
+	return module + "." + fields[0]
+}
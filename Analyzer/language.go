@@ -2,6 +2,7 @@ package Analyzer
 
 import (
 	"fmt"
+	"io/fs"
 	"regexp"
 	"statfiy/FileManager"
 	"strings"
@@ -182,6 +183,24 @@ func (l Language) String() string {
 	return "Unknown"
 }
 
+// MarshalText encodes a Language by its name, so snapshots stay readable
+// and valid when languages are added.
+func (l Language) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText decodes a Language from its name. Unknown names decode to Unknown.
+func (l *Language) UnmarshalText(text []byte) error {
+	*l = Unknown
+	for lang, name := range languageNames {
+		if name == string(text) {
+			*l = lang
+			break
+		}
+	}
+	return nil
+}
+
 func (l Language) GetColor() string {
 	if color, exists := GitHubLanguageColors[l]; exists {
 		return color
@@ -191,9 +210,14 @@ func (l Language) GetColor() string {
 
 // GetLanguage determines the programming language based on file extension
 func GetLanguage(metadata FileMetadata) Language {
+	return GetLanguageFS(FileManager.OSFS, metadata)
+}
+
+// GetLanguageFS is like GetLanguage but reads ambiguous files from a filesystem.
+func GetLanguageFS(fsys fs.FS, metadata FileMetadata) Language {
 	if lang, exists := extensionToLanguage[metadata.Extension]; exists {
 		if metadata.Extension == ".m" {
-			return DetectMFileType(metadata)
+			return DetectMFileTypeFS(fsys, metadata)
 		}
 		return lang
 	}
@@ -201,6 +225,12 @@ func GetLanguage(metadata FileMetadata) Language {
 }
 
 func DetectMFileType(metadata FileMetadata) Language {
+	return DetectMFileTypeFS(FileManager.OSFS, metadata)
+}
+
+// DetectMFileTypeFS tells Objective-C and MATLAB `.m` files apart by their first 20 lines,
+// read from a filesystem.
+func DetectMFileTypeFS(fsys fs.FS, metadata FileMetadata) Language {
 	objcPatterns := []*regexp.Regexp{
 		regexp.MustCompile(`@interface`),
 		regexp.MustCompile(`@implementation`),
@@ -220,7 +250,7 @@ func DetectMFileType(metadata FileMetadata) Language {
 	detectedType := Matlab
 	linesRead := 0
 
-	err := FileManager.ReadLinesLimit(metadata.Path, 20, func(line string) error {
+	err := FileManager.ReadLinesLimitFS(fsys, metadata.Path, 20, func(line string) error {
 		// Check for Objective-C patterns
 		for _, pattern := range objcPatterns {
 			if pattern.MatchString(line) {
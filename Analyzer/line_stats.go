@@ -0,0 +1,262 @@
+package Analyzer
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// LineLengthLimit is the line length (in utf8 chars) above which a line counts as too long.
+var LineLengthLimit = 120
+
+// IndentationStyle describes which whitespace a file uses to indent lines.
+type IndentationStyle string
+
+const (
+	IndentNone   IndentationStyle = "none"
+	IndentTabs   IndentationStyle = "tabs"
+	IndentSpaces IndentationStyle = "spaces"
+	IndentMixed  IndentationStyle = "mixed"
+)
+
+// LineEndingStyle describes which line terminators a file uses.
+type LineEndingStyle string
+
+const (
+	LineEndingNone  LineEndingStyle = "none"
+	LineEndingLF    LineEndingStyle = "LF"
+	LineEndingCRLF  LineEndingStyle = "CRLF"
+	LineEndingMixed LineEndingStyle = "mixed"
+)
+
+// LineStats holds line length, indentation and whitespace statistics of a file.
+type LineStats struct {
+	Lines                   int
+	MaxLineLength           int
+	AverageLineLength       float64
+	P95LineLength           int
+	LongLines               int // Lines longer than LineLengthLimit
+	TabIndentedLines        int
+	SpaceIndentedLines      int
+	MixedIndentedLines      int // Lines whose indentation contains both tabs and spaces
+	TrailingWhitespaceLines int
+	LFLines                 int
+	CRLFLines               int
+	MissingFinalNewline     bool
+
+	lengthHistogram map[int]int // Number of lines per line length, used to merge percentiles
+}
+
+// lineStatsJSON is LineStats with its length histogram, which is needed to merge percentiles of
+// results read back from snapshots, the result cache or the database.
+type lineStatsJSON struct {
+	plainLineStats
+	LengthHistogram map[int]int `json:",omitempty"`
+}
+
+// plainLineStats has the fields of LineStats without its JSON methods.
+type plainLineStats LineStats
+
+// MarshalJSON encodes the stats including the line length histogram.
+func (s LineStats) MarshalJSON() ([]byte, error) {
+	return json.Marshal(lineStatsJSON{plainLineStats: plainLineStats(s), LengthHistogram: s.lengthHistogram})
+}
+
+// UnmarshalJSON decodes stats encoded by MarshalJSON.
+func (s *LineStats) UnmarshalJSON(data []byte) error {
+	var decoded lineStatsJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*s = LineStats(decoded.plainLineStats)
+	s.lengthHistogram = decoded.LengthHistogram
+	return nil
+}
+
+// LanguageLineStats sums the LineStats of all files of a language.
+type LanguageLineStats struct {
+	LineStats
+	Files                    int
+	MixedIndentationFiles    int
+	MixedLineEndingFiles     int
+	MissingFinalNewlineFiles int
+}
+
+// Indentation returns the indentation style derived from the indented line counts.
+func (s LineStats) Indentation() IndentationStyle {
+	switch {
+	case s.MixedIndentedLines > 0 || (s.TabIndentedLines > 0 && s.SpaceIndentedLines > 0):
+		return IndentMixed
+	case s.TabIndentedLines > 0:
+		return IndentTabs
+	case s.SpaceIndentedLines > 0:
+		return IndentSpaces
+	default:
+		return IndentNone
+	}
+}
+
+// LineEnding returns the line ending style derived from the line terminator counts.
+func (s LineStats) LineEnding() LineEndingStyle {
+	switch {
+	case s.LFLines > 0 && s.CRLFLines > 0:
+		return LineEndingMixed
+	case s.CRLFLines > 0:
+		return LineEndingCRLF
+	case s.LFLines > 0:
+		return LineEndingLF
+	default:
+		return LineEndingNone
+	}
+}
+
+// CalculateLineStats measures line lengths, indentation, trailing whitespace and line endings in a single pass.
+//
+// Arguments:
+//   - source: The input string representing the source code or text.
+//   - lineLengthLimit: Lines longer than this are counted in LongLines.
+//
+// Returns:
+//   - LineStats: The statistics of the source.
+func CalculateLineStats(source string, lineLengthLimit int) LineStats {
+	stats := LineStats{lengthHistogram: make(map[int]int)}
+	if source == "" {
+		return stats
+	}
+
+	totalLength := 0
+	for start := 0; start < len(source); {
+		end := strings.IndexByte(source[start:], '\n')
+		var line string
+		if end < 0 {
+			line = source[start:]
+			start = len(source)
+			stats.MissingFinalNewline = true
+		} else {
+			line = source[start : start+end]
+			start += end + 1
+			if strings.HasSuffix(line, "\r") {
+				stats.CRLFLines++
+			} else {
+				stats.LFLines++
+			}
+		}
+		line = strings.TrimSuffix(line, "\r")
+
+		length := utf8.RuneCountInString(line)
+		stats.Lines++
+		stats.lengthHistogram[length]++
+		totalLength += length
+		stats.MaxLineLength = max(stats.MaxLineLength, length)
+		if length > lineLengthLimit {
+			stats.LongLines++
+		}
+
+		trimmed := strings.TrimRight(line, " \t")
+		if trimmed != line && trimmed != "" {
+			stats.TrailingWhitespaceLines++
+		}
+
+		indentation := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		if indentation == "" || trimmed == "" {
+			continue
+		}
+		hasTabs, hasSpaces := strings.Contains(indentation, "\t"), strings.Contains(indentation, " ")
+		switch {
+		case hasTabs && hasSpaces:
+			stats.MixedIndentedLines++
+		case hasTabs:
+			stats.TabIndentedLines++
+		default:
+			stats.SpaceIndentedLines++
+		}
+	}
+
+	stats.AverageLineLength = float64(totalLength) / float64(stats.Lines)
+	stats.P95LineLength = percentileFromHistogram(stats.lengthHistogram, stats.Lines, 95)
+
+	return stats
+}
+
+// CalculateLanguageLineStats merges the line statistics of all files per language.
+//
+// Args:
+//   - results: A slice of AnalyzeFileResult containing analysis data for multiple files.
+//
+// Returns:
+//   - map[Language]LanguageLineStats: The merged statistics per language.
+func CalculateLanguageLineStats(results []AnalyzeFileResult) map[Language]LanguageLineStats {
+	merged := make(map[Language]LanguageLineStats)
+	totalLengths := make(map[Language]float64)
+
+	for _, result := range results {
+		file := result.LineStats
+		stats := merged[result.Language]
+		if stats.lengthHistogram == nil {
+			stats.lengthHistogram = make(map[int]int)
+		}
+
+		stats.Files++
+		stats.Lines += file.Lines
+		stats.MaxLineLength = max(stats.MaxLineLength, file.MaxLineLength)
+		stats.LongLines += file.LongLines
+		stats.TabIndentedLines += file.TabIndentedLines
+		stats.SpaceIndentedLines += file.SpaceIndentedLines
+		stats.MixedIndentedLines += file.MixedIndentedLines
+		stats.TrailingWhitespaceLines += file.TrailingWhitespaceLines
+		stats.LFLines += file.LFLines
+		stats.CRLFLines += file.CRLFLines
+
+		if file.Indentation() == IndentMixed {
+			stats.MixedIndentationFiles++
+		}
+		if file.LineEnding() == LineEndingMixed {
+			stats.MixedLineEndingFiles++
+		}
+		if file.MissingFinalNewline {
+			stats.MissingFinalNewlineFiles++
+		}
+
+		for length, count := range file.lengthHistogram {
+			stats.lengthHistogram[length] += count
+		}
+		totalLengths[result.Language] += file.AverageLineLength * float64(file.Lines)
+
+		merged[result.Language] = stats
+	}
+
+	for lang, stats := range merged {
+		if stats.Lines > 0 {
+			stats.AverageLineLength = totalLengths[lang] / float64(stats.Lines)
+			stats.P95LineLength = percentileFromHistogram(stats.lengthHistogram, stats.Lines, 95)
+		}
+		merged[lang] = stats
+	}
+
+	return merged
+}
+
+// percentileFromHistogram returns the smallest length that at least percentile% of the lines don't exceed.
+func percentileFromHistogram(histogram map[int]int, total int, percentile float64) int {
+	if total == 0 || len(histogram) == 0 {
+		return 0
+	}
+
+	lengths := make([]int, 0, len(histogram))
+	for length := range histogram {
+		lengths = append(lengths, length)
+	}
+	sort.Ints(lengths)
+
+	target := int(math.Ceil(float64(total) * percentile / 100))
+	seen := 0
+	for _, length := range lengths {
+		seen += histogram[length]
+		if seen >= target {
+			return length
+		}
+	}
+	return lengths[len(lengths)-1]
+}
@@ -0,0 +1,54 @@
+package Analyzer
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalculateLineStats(t *testing.T) {
+	source := "func main() {\r\n\tx := 1 \n    \ty := 2\n\n  return\n}"
+	stats := CalculateLineStats(source, 10)
+
+	require.Equal(t, 6, stats.Lines)
+	require.Equal(t, 13, stats.MaxLineLength)
+	require.Equal(t, 2, stats.LongLines)
+	require.Equal(t, 1, stats.TabIndentedLines)
+	require.Equal(t, 1, stats.SpaceIndentedLines)
+	require.Equal(t, 1, stats.MixedIndentedLines)
+	require.Equal(t, 1, stats.TrailingWhitespaceLines)
+	require.Equal(t, 1, stats.CRLFLines)
+	require.Equal(t, 4, stats.LFLines)
+	require.True(t, stats.MissingFinalNewline)
+	require.Equal(t, IndentMixed, stats.Indentation())
+	require.Equal(t, LineEndingMixed, stats.LineEnding())
+}
+
+func TestCalculateLanguageLineStats(t *testing.T) {
+	results := []AnalyzeFileResult{
+		{Language: Go, LineStats: CalculateLineStats("a\nbb\n", 120)},
+		{Language: Go, LineStats: CalculateLineStats("cccc\n", 120)},
+	}
+
+	stats := CalculateLanguageLineStats(results)[Go]
+	require.Equal(t, 2, stats.Files)
+	require.Equal(t, 3, stats.Lines)
+	require.Equal(t, 4, stats.MaxLineLength)
+	require.InDelta(t, 7.0/3.0, stats.AverageLineLength, 0.001)
+	require.Equal(t, 4, stats.P95LineLength)
+}
+
+func TestLineStatsJSON(t *testing.T) {
+	stats := CalculateLineStats("a\nbb\ncccc\n", 120)
+
+	data, err := json.Marshal(AnalyzeFileResult{Language: Go, LineStats: stats})
+	require.NoError(t, err)
+	var decoded AnalyzeFileResult
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Equal(t, stats, decoded.LineStats)
+
+	// Percentiles of decoded stats can still be merged
+	merged := CalculateLanguageLineStats([]AnalyzeFileResult{decoded})[Go]
+	require.Equal(t, 4, merged.P95LineLength)
+}
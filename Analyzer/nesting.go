@@ -0,0 +1,320 @@
+package Analyzer
+
+import (
+	"sort"
+	"strings"
+)
+
+// MaxDeepestLocations is the number of deepest locations kept per file.
+var MaxDeepestLocations = 5
+
+// NestingLocation is a run of consecutive code lines at the same nesting depth.
+type NestingLocation struct {
+	StartLine int
+	EndLine   int
+	Depth     int
+}
+
+// NestingStats holds the block nesting depth of a file.
+type NestingStats struct {
+	MaxDepth     int
+	AverageDepth float64           // Average depth over all code lines
+	Deepest      []NestingLocation // Deepest locations, deepest first
+}
+
+// LanguageNesting summarizes the nesting depth of all files of a language.
+type LanguageNesting struct {
+	Files           int
+	MaxDepth        int
+	AverageMaxDepth float64
+	AverageDepth    float64 // Average depth over all code lines of the language
+}
+
+// nestingStyle describes how a language opens and closes blocks.
+type nestingStyle int
+
+const (
+	nestingNone nestingStyle = iota
+	nestingBraces
+	nestingIndentation
+	nestingKeywords
+)
+
+var languageNestingStyle = map[Language]nestingStyle{
+	Go: nestingBraces, C: nestingBraces, CPlusPlus: nestingBraces, CSharp: nestingBraces, Rust: nestingBraces,
+	JavaScript: nestingBraces, TypeScript: nestingBraces, Java: nestingBraces, Kotlin: nestingBraces, Swift: nestingBraces,
+	PHP: nestingBraces, Dart: nestingBraces, Perl: nestingBraces, Scala: nestingBraces, R: nestingBraces,
+	ObjectiveC: nestingBraces, PowerShell: nestingBraces, Zig: nestingBraces, CSS: nestingBraces,
+	Python: nestingIndentation, Haskell: nestingIndentation, FSharp: nestingIndentation,
+	Ruby: nestingKeywords, Lua: nestingKeywords, Pascal: nestingKeywords, Elixir: nestingKeywords,
+}
+
+// blockKeywords lists the words opening and closing blocks of keyword delimited languages.
+type blockKeywords struct {
+	openers          map[string]bool
+	statementOpeners map[string]bool // Open a block only at the start of a statement, e.g. Ruby's `if`
+	closers          map[string]bool
+	reopeners        map[string]bool // Close the current block and open the next one, e.g. Lua's `elseif`
+}
+
+var languageBlockKeywords = map[Language]blockKeywords{
+	Ruby: {
+		openers:          map[string]bool{"do": true, "begin": true, "def": true, "class": true, "module": true, "case": true},
+		statementOpeners: map[string]bool{"if": true, "unless": true, "while": true, "until": true, "for": true},
+		closers:          map[string]bool{"end": true},
+	},
+	Lua: {
+		openers:   map[string]bool{"function": true, "do": true, "then": true, "repeat": true},
+		closers:   map[string]bool{"end": true, "until": true},
+		reopeners: map[string]bool{"elseif": true},
+	},
+	Pascal: {
+		openers: map[string]bool{"begin": true, "case": true, "record": true, "try": true, "repeat": true},
+		closers: map[string]bool{"end": true, "until": true},
+	},
+	Elixir: {
+		openers: map[string]bool{"do": true, "fn": true},
+		closers: map[string]bool{"end": true},
+	},
+}
+
+// CalculateNesting measures the block nesting depth of every code line.
+// Braces are counted for C-family languages, indentation for Python, Haskell and F#,
+// and block keywords such as `do`/`end` for Ruby, Lua, Pascal and Elixir.
+//
+// Arguments:
+//   - stripped: The source code with comments removed, see StripCommentsByLanguage.
+//   - tokens: The tokens of stripped.
+//   - lang: The programming language of the source.
+//
+// Returns:
+//   - NestingStats: The maximum and average depth and the deepest locations.
+func CalculateNesting(stripped string, tokens []Token, lang Language) NestingStats {
+	var lineDepths map[int]int
+	switch languageNestingStyle[lang] {
+	case nestingBraces:
+		lineDepths = braceLineDepths(tokens)
+	case nestingIndentation:
+		lineDepths = indentationLineDepths(stripped, tokens)
+	case nestingKeywords:
+		lineDepths = keywordLineDepths(tokens, languageBlockKeywords[lang], lang == Pascal)
+	default:
+		return NestingStats{}
+	}
+
+	return summarizeLineDepths(lineDepths)
+}
+
+// braceLineDepths returns the depth of every code line, counting curly braces.
+func braceLineDepths(tokens []Token) map[int]int {
+	return trackLineDepths(tokens, func(i int, depth int) int {
+		switch tokens[i].Text {
+		case "{":
+			return depth + 1
+		case "}":
+			return depth - 1
+		}
+		return depth
+	})
+}
+
+// keywordLineDepths returns the depth of every code line, counting block keywords.
+func keywordLineDepths(tokens []Token, keywords blockKeywords, caseInsensitive bool) map[int]int {
+	statementBlockLine := 0
+
+	return trackLineDepths(tokens, func(i int, depth int) int {
+		token := tokens[i]
+		if token.Kind != TokenIdentifier && token.Kind != TokenKeyword {
+			return depth
+		}
+		word := token.Text
+		if caseInsensitive {
+			word = strings.ToLower(word)
+		}
+
+		switch {
+		case keywords.closers[word]:
+			return depth - 1
+		case keywords.reopeners[word]:
+			return depth - 1
+		case keywords.statementOpeners[word] && isStatementStart(tokens, i):
+			statementBlockLine = token.Line
+			return depth + 1
+		case keywords.openers[word]:
+			// `do:` is Elixir's one-line keyword syntax, and `while x do` opens a single block.
+			if word == "do" && (statementBlockLine == token.Line || (i+1 < len(tokens) && tokens[i+1].Text == ":")) {
+				return depth
+			}
+			return depth + 1
+		}
+		return depth
+	})
+}
+
+// isStatementStart reports whether the token at i starts a statement or an assigned expression.
+func isStatementStart(tokens []Token, i int) bool {
+	if i == 0 || tokens[i-1].Line != tokens[i].Line {
+		return true
+	}
+	switch tokens[i-1].Text {
+	case "=", ";", "(", "||=", "&&=":
+		return true
+	}
+	return false
+}
+
+// trackLineDepths applies step to every token and records the deepest depth of each line.
+// step returns the depth after the token; a decreasing step applies to the token itself,
+// so a closing `}` sits at the depth of its opening line.
+func trackLineDepths(tokens []Token, step func(i int, depth int) int) map[int]int {
+	lineDepths := make(map[int]int)
+	depth := 0
+
+	for i, token := range tokens {
+		next := max(step(i, depth), 0)
+		tokenDepth := depth
+		if next < depth {
+			tokenDepth = next
+		}
+		if current, found := lineDepths[token.Line]; !found || tokenDepth > current {
+			lineDepths[token.Line] = tokenDepth
+		}
+		depth = next
+	}
+
+	return lineDepths
+}
+
+// indentationLineDepths returns the depth of every code line from its indentation.
+// Continuation lines inside brackets keep the depth of the line that opened the bracket.
+func indentationLineDepths(stripped string, tokens []Token) map[int]int {
+	lines := strings.Split(stripped, "\n")
+	lineDepths := make(map[int]int)
+	indents := []int{0}
+	brackets := 0
+	lastLine := 0
+
+	for _, token := range tokens {
+		if token.Line != lastLine {
+			lastLine = token.Line
+			if brackets == 0 && token.Line <= len(lines) {
+				width := indentationWidth(lines[token.Line-1])
+				for len(indents) > 1 && width < indents[len(indents)-1] {
+					indents = indents[:len(indents)-1]
+				}
+				if width > indents[len(indents)-1] {
+					indents = append(indents, width)
+				}
+			}
+			lineDepths[token.Line] = len(indents) - 1
+		}
+
+		switch token.Text {
+		case "(", "[", "{":
+			brackets++
+		case ")", "]", "}":
+			brackets = max(brackets-1, 0)
+		}
+	}
+
+	return lineDepths
+}
+
+// indentationWidth returns the width of the leading whitespace of a line, with tabs expanded to 8 columns.
+func indentationWidth(line string) int {
+	width := 0
+	for _, char := range line {
+		switch char {
+		case ' ':
+			width++
+		case '\t':
+			width += 8 - width%8
+		default:
+			return width
+		}
+	}
+	return width
+}
+
+// summarizeLineDepths computes the statistics of the depth of every code line.
+func summarizeLineDepths(lineDepths map[int]int) NestingStats {
+	stats := NestingStats{}
+	if len(lineDepths) == 0 {
+		return stats
+	}
+
+	lineNumbers := make([]int, 0, len(lineDepths))
+	total := 0
+	for line, depth := range lineDepths {
+		lineNumbers = append(lineNumbers, line)
+		total += depth
+		stats.MaxDepth = max(stats.MaxDepth, depth)
+	}
+	sort.Ints(lineNumbers)
+	stats.AverageDepth = float64(total) / float64(len(lineDepths))
+
+	// Merge consecutive code lines at the same depth into one location
+	var locations []NestingLocation
+	for _, line := range lineNumbers {
+		depth := lineDepths[line]
+		if depth == 0 {
+			continue
+		}
+		if last := len(locations) - 1; last >= 0 && locations[last].Depth == depth && locations[last].EndLine == previousCodeLine(lineNumbers, line) {
+			locations[last].EndLine = line
+			continue
+		}
+		locations = append(locations, NestingLocation{StartLine: line, EndLine: line, Depth: depth})
+	}
+
+	sort.SliceStable(locations, func(i, j int) bool {
+		return locations[i].Depth > locations[j].Depth
+	})
+	if len(locations) > MaxDeepestLocations {
+		locations = locations[:MaxDeepestLocations]
+	}
+	stats.Deepest = locations
+
+	return stats
+}
+
+// previousCodeLine returns the code line before line in the sorted lineNumbers, or 0.
+func previousCodeLine(lineNumbers []int, line int) int {
+	index := sort.SearchInts(lineNumbers, line)
+	if index == 0 {
+		return 0
+	}
+	return lineNumbers[index-1]
+}
+
+// CalculateNestingByLanguage summarizes the nesting depth per language.
+//
+// Args:
+//   - results: A slice of AnalyzeFileResult containing analysis data for multiple files.
+//
+// Returns:
+//   - map[Language]LanguageNesting: The nesting summary per language.
+func CalculateNestingByLanguage(results []AnalyzeFileResult) map[Language]LanguageNesting {
+	summaries := make(map[Language]LanguageNesting)
+	lines := make(map[Language]int)
+
+	for _, result := range results {
+		summary := summaries[result.Language]
+		summary.Files++
+		summary.MaxDepth = max(summary.MaxDepth, result.Nesting.MaxDepth)
+		summary.AverageMaxDepth += float64(result.Nesting.MaxDepth)
+		summary.AverageDepth += result.Nesting.AverageDepth * float64(result.CodeLines)
+		lines[result.Language] += result.CodeLines
+		summaries[result.Language] = summary
+	}
+
+	for lang, summary := range summaries {
+		summary.AverageMaxDepth /= float64(summary.Files)
+		if lines[lang] > 0 {
+			summary.AverageDepth /= float64(lines[lang])
+		}
+		summaries[lang] = summary
+	}
+
+	return summaries
+}
@@ -0,0 +1,42 @@
+package Analyzer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func nestingOf(source string, lang Language) NestingStats {
+	stripped := StripCommentsByLanguage(source, lang)
+	return CalculateNesting(stripped, Tokenize(stripped), lang)
+}
+
+func TestCalculateNestingBraces(t *testing.T) {
+	source := "func f() {\n\tfor {\n\t\tif x { // {\n\t\t\ty(\"}\")\n\t\t}\n\t}\n}\n"
+	stats := nestingOf(source, Go)
+
+	require.Equal(t, 3, stats.MaxDepth)
+	require.InDelta(t, 9.0/7.0, stats.AverageDepth, 0.001)
+	require.Equal(t, NestingLocation{StartLine: 4, EndLine: 4, Depth: 3}, stats.Deepest[0])
+}
+
+func TestCalculateNestingIndentation(t *testing.T) {
+	source := "def f(x):\n    if x:\n        call(a,\n    b)\n        return 1\n    return 2\n"
+	stats := nestingOf(source, Python)
+
+	require.Equal(t, 2, stats.MaxDepth)
+	require.Equal(t, NestingLocation{StartLine: 3, EndLine: 5, Depth: 2}, stats.Deepest[0])
+}
+
+func TestCalculateNestingKeywords(t *testing.T) {
+	ruby := "def f\n  while x do\n    y if z\n    if a\n      b\n    end\n  end\nend\n"
+	require.Equal(t, 3, nestingOf(ruby, Ruby).MaxDepth)
+
+	lua := "function f()\n  if a then\n    b()\n  elseif c then\n    d()\n  end\nend\n"
+	stats := nestingOf(lua, Lua)
+	require.Equal(t, 2, stats.MaxDepth)
+	require.Equal(t, []NestingLocation{{StartLine: 3, EndLine: 3, Depth: 2}, {StartLine: 5, EndLine: 5, Depth: 2}}, stats.Deepest[:2])
+
+	elixir := "defmodule A do\n  def f(x), do: x\n  def g do\n    Enum.map(l, fn x -> x end)\n  end\nend\n"
+	require.Equal(t, 3, nestingOf(elixir, Elixir).MaxDepth)
+}
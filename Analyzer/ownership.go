@@ -0,0 +1,235 @@
+package Analyzer
+
+import (
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"statfiy/FileManager"
+	"strings"
+	"sync"
+)
+
+// MaxOwnershipDirectoryDepth limits the directories reported with a bus factor to this many levels below the root.
+var MaxOwnershipDirectoryDepth = 2
+
+// MaxParallelBlames limits the number of git blame processes AnalyzeOwnership runs at the same time.
+var MaxParallelBlames = runtime.NumCPU()
+
+// FileOwnership holds the authors and owners of a file.
+type FileOwnership struct {
+	Path     string // Slash separated path relative to the repository root
+	Language Language
+	Authors  map[string]int // Lines per author, from git blame
+	Owners   []string       // Owners from CODEOWNERS
+}
+
+// Lines returns the number of blamed lines of the file.
+func (file FileOwnership) Lines() int {
+	lines := 0
+	for _, count := range file.Authors {
+		lines += count
+	}
+	return lines
+}
+
+// OwnerStats holds the lines and files of an author or a CODEOWNERS owner.
+type OwnerStats struct {
+	Name      string
+	Files     int
+	Lines     int
+	Languages map[Language]int // Lines per language
+}
+
+// DirectoryOwnership holds how the lines of a directory, including its subdirectories, are spread over authors.
+type DirectoryOwnership struct {
+	Path           string // Slash separated path relative to the repository root, "." for the root
+	Lines          int
+	Authors        int
+	BusFactor      int // Smallest number of authors who together wrote more than half of the lines
+	TopAuthor      string
+	TopAuthorShare float64 // Percentage of the lines written by TopAuthor
+}
+
+// OwnershipReport holds the authorship and ownership statistics of a repository.
+type OwnershipReport struct {
+	Authors     []OwnerStats         // Most lines first
+	Owners      []OwnerStats         // CODEOWNERS users and teams, most lines first; files count for each of their owners
+	Unowned     []string             // Files without an owner, only set if there is a CODEOWNERS file
+	Directories []DirectoryOwnership // Sorted by path
+	Skipped     []string             // Files that couldn't be blamed, e.g. untracked files
+}
+
+// AnalyzeOwnership blames the analyzed files of a git repository and maps them to their CODEOWNERS owners.
+//
+// Args:
+//   - repoRoot: The root of the working tree, see FileManager.GitRepositoryRoot.
+//   - revision: The revision to blame, or an empty string for the working tree.
+//   - results: The analyzed files, all below repoRoot.
+//   - codeOwners: The CODEOWNERS rules of the repository, empty if there are none.
+//
+// Returns:
+//   - OwnershipReport: The statistics of the blamed files; files that can't be blamed are listed in Skipped.
+func AnalyzeOwnership(repoRoot, revision string, results []AnalyzeFileResult, codeOwners FileManager.CodeOwners) OwnershipReport {
+	blames := make([]map[string]int, len(results))
+	blameErrors := make([]error, len(results))
+
+	var wait sync.WaitGroup
+	slots := make(chan struct{}, max(MaxParallelBlames, 1))
+	for i, result := range results {
+		wait.Add(1)
+		slots <- struct{}{}
+		go func() {
+			defer func() {
+				<-slots
+				wait.Done()
+			}()
+			blames[i], blameErrors[i] = FileManager.BlameGitFile(repoRoot, result.FileMetadata.Path, revision)
+		}()
+	}
+	wait.Wait()
+
+	var files []FileOwnership
+	var skipped []string
+
+	for i, result := range results {
+		relativePath, err := FileManager.GetRelativePath(repoRoot, result.FileMetadata.Path)
+		if err != nil {
+			skipped = append(skipped, result.FileMetadata.Path)
+			continue
+		}
+		relativePath = filepath.ToSlash(relativePath)
+
+		if blameErrors[i] != nil {
+			skipped = append(skipped, relativePath)
+			continue
+		}
+
+		files = append(files, FileOwnership{
+			Path:     relativePath,
+			Language: result.Language,
+			Authors:  blames[i],
+			Owners:   codeOwners.Owners(relativePath),
+		})
+	}
+
+	report := BuildOwnershipReport(files, len(codeOwners.Rules) > 0)
+	report.Skipped = skipped
+	return report
+}
+
+// BuildOwnershipReport aggregates the authors and owners of files per author, owner and directory.
+//
+// Args:
+//   - files: The files with their blamed authors and owners.
+//   - withCodeOwners: Whether there is a CODEOWNERS file, i.e. whether files without owners are reported.
+//
+// Returns:
+//   - OwnershipReport: The statistics, without Skipped.
+func BuildOwnershipReport(files []FileOwnership, withCodeOwners bool) OwnershipReport {
+	report := OwnershipReport{}
+	authors := make(map[string]*OwnerStats)
+	owners := make(map[string]*OwnerStats)
+	directories := make(map[string]map[string]int) // Lines per author per directory
+
+	addTo := func(stats map[string]*OwnerStats, name string, lang Language, lines int) {
+		if stats[name] == nil {
+			stats[name] = &OwnerStats{Name: name, Languages: make(map[Language]int)}
+		}
+		stats[name].Files++
+		stats[name].Lines += lines
+		stats[name].Languages[lang] += lines
+	}
+
+	for _, file := range files {
+		for author, lines := range file.Authors {
+			addTo(authors, author, file.Language, lines)
+		}
+
+		for _, owner := range file.Owners {
+			addTo(owners, owner, file.Language, file.Lines())
+		}
+		if withCodeOwners && len(file.Owners) == 0 {
+			report.Unowned = append(report.Unowned, file.Path)
+		}
+
+		for _, dir := range ancestorDirectories(file.Path, MaxOwnershipDirectoryDepth) {
+			if directories[dir] == nil {
+				directories[dir] = make(map[string]int)
+			}
+			for author, lines := range file.Authors {
+				directories[dir][author] += lines
+			}
+		}
+	}
+
+	report.Authors = sortOwnerStats(authors)
+	report.Owners = sortOwnerStats(owners)
+	sort.Strings(report.Unowned)
+
+	for dir, lines := range directories {
+		report.Directories = append(report.Directories, directoryOwnership(dir, lines))
+	}
+	sort.Slice(report.Directories, func(i, j int) bool { return report.Directories[i].Path < report.Directories[j].Path })
+
+	return report
+}
+
+// ancestorDirectories returns "." and the directories containing a file, up to maxDepth levels below the root.
+func ancestorDirectories(filePath string, maxDepth int) []string {
+	directories := []string{"."}
+	parts := strings.Split(path.Dir(filePath), "/")
+	if parts[0] == "." {
+		return directories
+	}
+	for depth := 1; depth <= min(maxDepth, len(parts)); depth++ {
+		directories = append(directories, strings.Join(parts[:depth], "/"))
+	}
+	return directories
+}
+
+// directoryOwnership computes the bus factor of a directory from its lines per author.
+func directoryOwnership(dir string, lines map[string]int) DirectoryOwnership {
+	ownership := DirectoryOwnership{Path: dir, Authors: len(lines)}
+
+	authors := make([]string, 0, len(lines))
+	for author, count := range lines {
+		authors = append(authors, author)
+		ownership.Lines += count
+	}
+	sort.Slice(authors, func(i, j int) bool {
+		if lines[authors[i]] != lines[authors[j]] {
+			return lines[authors[i]] > lines[authors[j]]
+		}
+		return authors[i] < authors[j]
+	})
+
+	covered := 0
+	for _, author := range authors {
+		if 2*covered > ownership.Lines {
+			break
+		}
+		covered += lines[author]
+		ownership.BusFactor++
+	}
+
+	if len(authors) > 0 && ownership.Lines > 0 {
+		ownership.TopAuthor = authors[0]
+		ownership.TopAuthorShare = float64(lines[authors[0]]) / float64(ownership.Lines) * 100
+	}
+	return ownership
+}
+
+func sortOwnerStats(stats map[string]*OwnerStats) []OwnerStats {
+	sorted := make([]OwnerStats, 0, len(stats))
+	for _, stat := range stats {
+		sorted = append(sorted, *stat)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Lines != sorted[j].Lines {
+			return sorted[i].Lines > sorted[j].Lines
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
@@ -0,0 +1,47 @@
+package Analyzer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildOwnershipReport(t *testing.T) {
+	files := []FileOwnership{
+		{Path: "main.go", Language: Go, Authors: map[string]int{"alice": 10}, Owners: []string{"@org/core"}},
+		{Path: "api/server/server.go", Language: Go, Authors: map[string]int{"alice": 30, "bob": 30, "carol": 40}, Owners: []string{"@org/api", "@org/core"}},
+		{Path: "api/client.py", Language: Python, Authors: map[string]int{"bob": 20}},
+	}
+
+	report := BuildOwnershipReport(files, true)
+
+	require.Equal(t, []OwnerStats{
+		{Name: "bob", Files: 2, Lines: 50, Languages: map[Language]int{Go: 30, Python: 20}},
+		{Name: "alice", Files: 2, Lines: 40, Languages: map[Language]int{Go: 40}},
+		{Name: "carol", Files: 1, Lines: 40, Languages: map[Language]int{Go: 40}},
+	}, report.Authors)
+	require.Equal(t, []OwnerStats{
+		{Name: "@org/core", Files: 2, Lines: 110, Languages: map[Language]int{Go: 110}},
+		{Name: "@org/api", Files: 1, Lines: 100, Languages: map[Language]int{Go: 100}},
+	}, report.Owners)
+	require.Equal(t, []string{"api/client.py"}, report.Unowned)
+
+	shares := []float64{38.46, 41.67, 40}
+	for i := range report.Directories {
+		require.InDelta(t, shares[i], report.Directories[i].TopAuthorShare, 0.01)
+		report.Directories[i].TopAuthorShare = 0
+	}
+	require.Equal(t, []DirectoryOwnership{
+		{Path: ".", Lines: 130, Authors: 3, BusFactor: 2, TopAuthor: "bob"},
+		{Path: "api", Lines: 120, Authors: 3, BusFactor: 2, TopAuthor: "bob"},
+		{Path: "api/server", Lines: 100, Authors: 3, BusFactor: 2, TopAuthor: "carol"},
+	}, report.Directories)
+
+	require.Empty(t, BuildOwnershipReport(files, false).Unowned)
+}
+
+func TestAncestorDirectories(t *testing.T) {
+	require.Equal(t, []string{"."}, ancestorDirectories("main.go", 2))
+	require.Equal(t, []string{".", "a", "a/b"}, ancestorDirectories("a/b/c/d.go", 2))
+	require.Equal(t, []string{".", "a"}, ancestorDirectories("a/d.go", 3))
+}
@@ -0,0 +1,63 @@
+package Analyzer
+
+import (
+	"path/filepath"
+	"statfiy/FileManager"
+	"strings"
+)
+
+// ProjectResults holds the analysis results of the files belonging to a project.
+type ProjectResults struct {
+	Project FileManager.Project
+	Results []AnalyzeFileResult
+}
+
+// GroupResultsByProject assigns every result to the innermost project containing it.
+// Files outside of every project are grouped under a project for the root itself,
+// which is only added if such files exist.
+//
+// Args:
+//   - rootPath: The analyzed root.
+//   - projects: The projects found in the root, see FileManager.DetectProjects.
+//   - results: A slice of AnalyzeFileResult containing analysis data for multiple files.
+//
+// Returns:
+//   - []ProjectResults: The results per project, in the order of projects, followed by the root if needed.
+func GroupResultsByProject(rootPath string, projects []FileManager.Project, results []AnalyzeFileResult) []ProjectResults {
+	groups := make([]ProjectResults, len(projects))
+	for i, project := range projects {
+		groups[i].Project = project
+	}
+
+	var unassigned []AnalyzeFileResult
+	for _, result := range results {
+		best := -1
+		for i, project := range projects {
+			if isInsideDir(project.Path, result.FileMetadata.Path) &&
+				(best < 0 || len(project.Path) > len(projects[best].Path)) {
+				best = i
+			}
+		}
+
+		if best < 0 {
+			unassigned = append(unassigned, result)
+			continue
+		}
+		groups[best].Results = append(groups[best].Results, result)
+	}
+
+	if len(unassigned) > 0 {
+		groups = append(groups, ProjectResults{
+			Project: FileManager.Project{Name: filepath.Base(rootPath), Path: rootPath},
+			Results: unassigned,
+		})
+	}
+
+	return groups
+}
+
+// isInsideDir reports whether filePath is located in dir or one of its subdirectories.
+func isInsideDir(dir, filePath string) bool {
+	relativePath, err := FileManager.GetRelativePath(dir, filePath)
+	return err == nil && relativePath != ".." && !strings.HasPrefix(relativePath, ".."+string(filepath.Separator))
+}
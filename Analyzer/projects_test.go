@@ -0,0 +1,31 @@
+package Analyzer
+
+import (
+	"testing"
+
+	"statfiy/FileManager"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupResultsByProject(t *testing.T) {
+	projects := []FileManager.Project{
+		{Name: "services", Path: "/repo/services"},
+		{Name: "services/api", Path: "/repo/services/api"},
+	}
+	resultAt := func(path string) AnalyzeFileResult {
+		return AnalyzeFileResult{FileMetadata: FileMetadata{Path: path}}
+	}
+	results := []AnalyzeFileResult{
+		resultAt("/repo/services/api/main.go"),
+		resultAt("/repo/services/shared.go"),
+		resultAt("/repo/services-old/legacy.go"),
+	}
+
+	groups := GroupResultsByProject("/repo", projects, results)
+	require.Len(t, groups, 3)
+	require.Equal(t, []AnalyzeFileResult{results[1]}, groups[0].Results)
+	require.Equal(t, []AnalyzeFileResult{results[0]}, groups[1].Results)
+	require.Equal(t, "repo", groups[2].Project.Name)
+	require.Equal(t, []AnalyzeFileResult{results[2]}, groups[2].Results)
+}
@@ -0,0 +1,65 @@
+package Analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"statfiy/FileManager"
+	"time"
+)
+
+// Snapshot holds the analysis results of a root at a point in time, so runs can be compared later.
+type Snapshot struct {
+	RootPath  string              `json:"rootPath"`
+	Revision  string              `json:"revision,omitempty"` // Git commit the results were read from, if any
+	CreatedAt time.Time           `json:"createdAt"`
+	Results   []AnalyzeFileResult `json:"results"`
+}
+
+// NewSnapshot creates a snapshot of the given results taken now.
+//
+// Args:
+//   - rootPath: The analyzed root.
+//   - results: The analysis results of the root.
+//
+// Returns:
+//   - Snapshot: The snapshot.
+func NewSnapshot(rootPath string, results []AnalyzeFileResult) Snapshot {
+	return Snapshot{RootPath: rootPath, CreatedAt: time.Now(), Results: results}
+}
+
+// WriteSnapshot writes a snapshot as JSON.
+//
+// Args:
+//   - filePath: The path of the JSON file.
+//   - snapshot: The snapshot to write.
+//
+// Returns:
+//   - error: An error if encoding or writing fails.
+func WriteSnapshot(filePath string, snapshot Snapshot) error {
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	return FileManager.OverwriteFile(filePath, data)
+}
+
+// ReadSnapshot reads a snapshot written by WriteSnapshot.
+//
+// Args:
+//   - filePath: The path of the JSON file.
+//
+// Returns:
+//   - Snapshot: The snapshot.
+//   - error: An error if reading or decoding fails.
+func ReadSnapshot(filePath string) (Snapshot, error) {
+	data, err := FileManager.ReadFileBytes(filePath)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return Snapshot{}, fmt.Errorf("failed to decode snapshot %s: %w", filePath, err)
+	}
+	return snapshot, nil
+}
@@ -0,0 +1,157 @@
+package Analyzer
+
+import (
+	"path"
+	"path/filepath"
+	"statfiy/FileManager"
+	"strings"
+)
+
+// DefaultTestFilePatterns lists the common conventions used to recognize test files.
+// Patterns ending with "/" match a directory anywhere in the relative path,
+// all other patterns are matched against the file name.
+var DefaultTestFilePatterns = []string{
+	"*_test.go",
+	"test_*.py",
+	"*_test.py",
+	"*.spec.ts",
+	"*.test.ts",
+	"*.spec.tsx",
+	"*.test.tsx",
+	"*.spec.js",
+	"*.test.js",
+	"*_spec.rb",
+	"*Test.java",
+	"*Tests.java",
+	"*Test.kt",
+	"*Tests.cs",
+	"src/test/",
+	"__tests__/",
+	"tests/",
+	"test/",
+	"spec/",
+}
+
+// TestRatio compares test code with production code.
+type TestRatio struct {
+	TestFiles       int
+	ProductionFiles int
+	TestSize        int64
+	ProductionSize  int64
+}
+
+// Ratio returns the size of test code per unit of production code.
+func (r TestRatio) Ratio() float64 {
+	if r.ProductionSize == 0 {
+		return 0
+	}
+	return float64(r.TestSize) / float64(r.ProductionSize)
+}
+
+// TestShare returns the percentage of the total size that is test code (0-100).
+func (r TestRatio) TestShare() float64 {
+	total := r.TestSize + r.ProductionSize
+	if total == 0 {
+		return 0
+	}
+	return float64(r.TestSize) / float64(total) * 100
+}
+
+// IsTestFile reports whether a file is a test file according to the given patterns.
+//
+// Arguments:
+//   - relativePath: The file path relative to the analyzed root.
+//   - patterns: File name globs (e.g. "*_test.go") or directories ending with "/" (e.g. "tests/").
+//
+// Returns:
+//   - bool: `true` if any pattern matches, `false` otherwise.
+func IsTestFile(relativePath string, patterns []string) bool {
+	slashPath := filepath.ToSlash(relativePath)
+	dir := "/" + path.Dir(slashPath) + "/"
+	name := path.Base(slashPath)
+
+	for _, pattern := range patterns {
+		if strings.HasSuffix(pattern, "/") {
+			if strings.Contains(dir, "/"+strings.TrimPrefix(pattern, "/")) {
+				return true
+			}
+			continue
+		}
+
+		if matched, err := path.Match(pattern, name); err == nil && matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ClassifyTestFiles sets the IsTest flag of every result.
+//
+// Arguments:
+//   - rootPath: The analyzed root; patterns are matched against paths relative to it.
+//   - results: The analysis results to classify, updated in place.
+//   - patterns: The test file patterns, usually DefaultTestFilePatterns.
+func ClassifyTestFiles(rootPath string, results []AnalyzeFileResult, patterns []string) {
+	for i := range results {
+		relativePath, err := FileManager.GetRelativePath(rootPath, results[i].FileMetadata.Path)
+		if err != nil {
+			relativePath = results[i].FileMetadata.Path
+		}
+		results[i].IsTest = IsTestFile(relativePath, patterns)
+	}
+}
+
+// CalculateTestRatiosByLanguage sums test and production code for each language.
+//
+// Args:
+//   - results: A slice of classified AnalyzeFileResult.
+//   - includeComment: A boolean indicating whether to include comment size in the size calculation.
+//
+// Returns:
+//   - map[Language]TestRatio: Test and production totals per language.
+func CalculateTestRatiosByLanguage(results []AnalyzeFileResult, includeComment bool) map[Language]TestRatio {
+	ratios := make(map[Language]TestRatio)
+	for _, result := range results {
+		ratios[result.Language] = addToTestRatio(ratios[result.Language], result, includeComment)
+	}
+	return ratios
+}
+
+// CalculateTestRatiosByDirectory sums test and production code for each top-level directory of the root.
+// Files placed directly in the root are grouped under ".".
+//
+// Args:
+//   - rootPath: The analyzed root.
+//   - results: A slice of classified AnalyzeFileResult.
+//   - includeComment: A boolean indicating whether to include comment size in the size calculation.
+//
+// Returns:
+//   - map[string]TestRatio: Test and production totals per directory.
+func CalculateTestRatiosByDirectory(rootPath string, results []AnalyzeFileResult, includeComment bool) map[string]TestRatio {
+	ratios := make(map[string]TestRatio)
+	for _, result := range results {
+		directory := "."
+		if relativePath, err := FileManager.GetRelativePath(rootPath, result.FileMetadata.Path); err == nil {
+			parts := strings.SplitN(filepath.ToSlash(relativePath), "/", 2)
+			if len(parts) == 2 {
+				directory = parts[0]
+			}
+		}
+		ratios[directory] = addToTestRatio(ratios[directory], result, includeComment)
+	}
+	return ratios
+}
+
+func addToTestRatio(ratio TestRatio, result AnalyzeFileResult, includeComment bool) TestRatio {
+	size := result.Size(includeComment)
+
+	if result.IsTest {
+		ratio.TestFiles++
+		ratio.TestSize += size
+	} else {
+		ratio.ProductionFiles++
+		ratio.ProductionSize += size
+	}
+	return ratio
+}
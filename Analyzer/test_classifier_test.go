@@ -0,0 +1,34 @@
+package Analyzer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsTestFile(t *testing.T) {
+	testFiles := []string{
+		"pkg/parser_test.go",
+		"app/test_models.py",
+		"web/src/button.spec.ts",
+		"service/src/test/java/com/acme/ServiceTest.java",
+		"ui/__tests__/render.js",
+		"tests/fixtures/data.py",
+	}
+	for _, file := range testFiles {
+		require.True(t, IsTestFile(file, DefaultTestFilePatterns), file)
+	}
+
+	productionFiles := []string{
+		"pkg/parser.go",
+		"app/models.py",
+		"web/src/button.ts",
+		"service/src/main/java/com/acme/Service.java",
+		"latest/release.go",
+	}
+	for _, file := range productionFiles {
+		require.False(t, IsTestFile(file, DefaultTestFilePatterns), file)
+	}
+
+	require.True(t, IsTestFile("lua/parser_spec.lua", []string{"*_spec.lua"}))
+}
@@ -0,0 +1,157 @@
+package Analyzer
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TokenKind represents the lexical class of a token.
+type TokenKind int
+
+const (
+	TokenIdentifier TokenKind = iota
+	TokenKeyword
+	TokenNumber
+	TokenString
+	TokenOperator
+)
+
+// Token is a single lexical element of a source file.
+type Token struct {
+	Text string
+	Kind TokenKind
+	Line int // 1-based line number where the token starts
+}
+
+// multiCharOperators lists operators made of more than one character, longest first.
+var multiCharOperators = []string{
+	"<<=", ">>=", "...", "===", "!==", "**=", "//=", "<=>",
+	"==", "!=", "<=", ">=", "&&", "||", "++", "--", "->", "=>", "::", "<<", ">>",
+	"+=", "-=", "*=", "/=", "%=", "&=", "|=", "^=", ":=", "**", "..", "?.", "??",
+}
+
+// keywords holds reserved words shared by most supported languages.
+// They are kept verbatim when tokens are normalized and count as operators in Halstead metrics.
+var keywords = map[string]bool{
+	"if": true, "else": true, "elif": true, "elsif": true, "elseif": true, "then": true,
+	"for": true, "foreach": true, "while": true, "do": true, "loop": true, "until": true, "repeat": true,
+	"switch": true, "case": true, "when": true, "match": true, "default": true,
+	"break": true, "continue": true, "return": true, "goto": true, "yield": true,
+	"try": true, "catch": true, "except": true, "finally": true, "throw": true, "raise": true, "rescue": true, "ensure": true,
+	"func": true, "function": true, "fn": true, "def": true, "defp": true, "sub": true, "lambda": true,
+	"class": true, "struct": true, "interface": true, "enum": true, "trait": true, "impl": true, "type": true,
+	"module": true, "package": true, "namespace": true, "import": true, "from": true, "use": true, "using": true, "include": true, "require": true,
+	"var": true, "let": true, "const": true, "val": true, "static": true, "final": true, "mut": true,
+	"public": true, "private": true, "protected": true, "internal": true,
+	"new": true, "delete": true, "in": true, "is": true, "as": true, "not": true, "and": true, "or": true,
+	"begin": true, "end": true, "unless": true, "go": true, "defer": true, "select": true, "chan": true, "async": true, "await": true,
+}
+
+// Tokenize splits source code into identifiers, keywords, numbers, strings and operators.
+// Whitespace is dropped. Comments are not recognized, so they should be stripped first
+// with StripCommentsByLanguage.
+//
+// Arguments:
+//   - source: The source code as a string.
+//
+// Returns:
+//   - []Token: The tokens in source order.
+func Tokenize(source string) []Token {
+	var tokens []Token
+	line := 1
+
+	for i := 0; i < len(source); {
+		r, size := utf8.DecodeRuneInString(source[i:])
+
+		switch {
+		case r == '\n':
+			line++
+			i += size
+
+		case unicode.IsSpace(r):
+			i += size
+
+		case isIdentifierStart(r):
+			end := i + size
+			for end < len(source) {
+				next, nextSize := utf8.DecodeRuneInString(source[end:])
+				if !isIdentifierPart(next) {
+					break
+				}
+				end += nextSize
+			}
+			text := source[i:end]
+			kind := TokenIdentifier
+			if keywords[text] {
+				kind = TokenKeyword
+			}
+			tokens = append(tokens, Token{Text: text, Kind: kind, Line: line})
+			i = end
+
+		case unicode.IsDigit(r):
+			end := i + size
+			for end < len(source) {
+				next, nextSize := utf8.DecodeRuneInString(source[end:])
+				if !isIdentifierPart(next) && next != '.' {
+					break
+				}
+				end += nextSize
+			}
+			tokens = append(tokens, Token{Text: source[i:end], Kind: TokenNumber, Line: line})
+			i = end
+
+		case r == '"' || r == '\'' || r == '`':
+			end := scanString(source, i, r)
+			if end < 0 {
+				// No closing quote: treat it as an operator, e.g. a Rust lifetime.
+				tokens = append(tokens, Token{Text: string(r), Kind: TokenOperator, Line: line})
+				i += size
+				continue
+			}
+			text := source[i:end]
+			tokens = append(tokens, Token{Text: text, Kind: TokenString, Line: line})
+			line += strings.Count(text, "\n")
+			i = end
+
+		default:
+			text := string(r)
+			for _, operator := range multiCharOperators {
+				if strings.HasPrefix(source[i:], operator) {
+					text = operator
+					break
+				}
+			}
+			tokens = append(tokens, Token{Text: text, Kind: TokenOperator, Line: line})
+			i += len(text)
+		}
+	}
+
+	return tokens
+}
+
+// scanString returns the index right after the string literal starting at start,
+// or -1 if the literal isn't closed. Only backtick strings may span multiple lines.
+func scanString(source string, start int, quote rune) int {
+	for i := start + 1; i < len(source); i++ {
+		switch source[i] {
+		case '\\':
+			i++
+		case '\n':
+			if quote != '`' {
+				return -1
+			}
+		case byte(quote):
+			return i + 1
+		}
+	}
+	return -1
+}
+
+func isIdentifierStart(r rune) bool {
+	return r == '_' || r == '$' || unicode.IsLetter(r)
+}
+
+func isIdentifierPart(r rune) bool {
+	return isIdentifierStart(r) || unicode.IsDigit(r)
+}
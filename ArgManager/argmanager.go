@@ -1,47 +1,313 @@
 package ArgManager
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/urfave/cli/v2"
 )
 
+// Version is the version of Statify, printed by --version and stored with the runs saved to the database.
+const Version = "0.1.0"
+
+// DatabasePathEnv is the environment variable setting the database path when --db-path isn't given.
+const DatabasePathEnv = "STATIFY_DB"
+
+// CommandDiff is the name of the subcommand comparing two roots or snapshots.
+const CommandDiff = "diff"
+
+// CommandHistory is the name of the subcommand analyzing sampled commits of a git repository.
+const CommandHistory = "history"
+
+// CommandWatch is the name of the subcommand re-analyzing a root whenever its files change.
+const CommandWatch = "watch"
+
+// CommandDatabase is the name of the subcommand managing the database, see DatabaseAction.
+const CommandDatabase = "db"
+
+// DatabaseMigrate and DatabaseStatus are the actions of the db subcommand.
+const (
+	DatabaseMigrate = "migrate"
+	DatabaseStatus  = "status"
+)
+
 type OptionalArg[T any] struct {
 	Value T
 	IsSet bool
 }
 
 type Args struct {
+	Command        string // The subcommand to run, empty for the default analysis
 	RootPaths      []string
 	IncludeComment bool
 	OutputPaths    OptionalArg[[]string]
+	DetectClones   bool
+	CloneMinTokens int
+	TestPatterns   OptionalArg[[]string]
+	MaxLineLength  int
+	MIThreshold    float64
+	Projects       bool
+	Aggregate      bool
+	DiffPaths      []string      // The before and after roots or snapshot files of the diff command
+	Revision       string        // Git revision to analyze instead of the working tree, empty for the working tree
+	Ownership      bool          // Attribute lines to authors with git blame and to owners with CODEOWNERS
+	Hotspots       bool          // Rank files by churn and complexity
+	ChurnDays      int           // Length of the churn window in days, ending at the analyzed commit
+	HistoryPath    string        // The repository of the history command
+	HistoryEvery   int           // Sample every n-th commit, 0 when sampling by period
+	HistoryPeriod  string        // Sample the last commit of each "week" or "month", empty when sampling every n-th commit
+	FollowSymlinks bool          // Descend into symbolic links to directories
+	MaxDepth       int           // Deepest directory level collected, 0 for no limit
+	SkipHidden     bool          // Skip files and directories whose name starts with a dot
+	OneFileSystem  bool          // Don't descend into directories on another filesystem
+	NoCache        bool          // Analyze every file instead of reusing cached results of unchanged files
+	SaveToDB       bool          // Store the metadata and results of every root in the database
+	FromDB         bool          // Regenerate the reports from the database instead of reading the roots
+	KeepRuns       int           // Number of newest runs kept per root when saving to the database, 0 keeps all
+	DatabasePath   string        // The database of the result cache, --db and --from-db
+	DatabaseAction string        // DatabaseMigrate or DatabaseStatus for the db command
+	WatchPath      string        // The root of the watch command
+	WatchDebounce  time.Duration // Quiet time after a change before the reports are regenerated
+	WatchInterval  time.Duration // Scan interval when polling
+	WatchPoll      bool          // Poll even if inotify is available
 }
 
 // ParseArgs parses command-line arguments and returns an Args struct.
 func ParseArgs(arguments []string) (*Args, error) {
 	var args Args
 	app := &cli.App{
+		Version: Version,
 		Flags: []cli.Flag{
 			&cli.StringSliceFlag{
-				Name:     "paths",
-				Aliases:  []string{"p"},
-				Usage:    "List of root paths for analysis files",
-				Required: true,
+				Name:    "paths",
+				Aliases: []string{"p"},
+				Usage:   "List of root paths for analysis files",
 			},
 			&cli.BoolFlag{
 				Name:    "include-comment",
 				Aliases: []string{"ic"},
 				Usage:   "Include comments in the analysis",
 			},
+			newOutputPathFlag(),
+			&cli.BoolFlag{
+				Name:    "detect-clones",
+				Aliases: []string{"dc"},
+				Usage:   "Detect copy-pasted code blocks and write a duplication report",
+			},
+			&cli.IntFlag{
+				Name:  "clone-min-tokens",
+				Usage: "Minimum length of a duplicated block in tokens",
+				Value: 50,
+			},
+			&cli.IntFlag{
+				Name:    "max-line-length",
+				Aliases: []string{"mll"},
+				Usage:   "Lines longer than this are reported as too long",
+				Value:   120,
+			},
+			&cli.Float64Flag{
+				Name:    "mi-threshold",
+				Aliases: []string{"mit"},
+				Usage:   "Files with a maintainability index (0-100) below this are flagged",
+				Value:   20,
+			},
+			&cli.BoolFlag{
+				Name:    "projects",
+				Aliases: []string{"pr"},
+				Usage:   "Detect sub-projects by their manifest files (go.mod, package.json, ...) and write a report per project",
+			},
+			&cli.BoolFlag{
+				Name:    "aggregate",
+				Aliases: []string{"ag"},
+				Usage:   "Also write a combined report of all root paths, counting files shared by several roots once",
+			},
+			&cli.StringFlag{
+				Name:    "rev",
+				Aliases: []string{"r"},
+				Usage:   "Analyze a commit, tag or branch of the git repository at each path instead of the working tree",
+			},
+			&cli.BoolFlag{
+				Name:    "ownership",
+				Aliases: []string{"ow"},
+				Usage:   "Attribute lines to authors with git blame and to teams with CODEOWNERS, and estimate bus factors",
+			},
+			&cli.BoolFlag{
+				Name:    "hotspots",
+				Aliases: []string{"hs"},
+				Usage:   "Combine git churn with complexity to rank hotspot files, and report churn per language and directory",
+			},
+			&cli.IntFlag{
+				Name:  "churn-days",
+				Usage: "Number of days before the analyzed commit whose commits count as churn",
+				Value: 90,
+				Action: func(ctx *cli.Context, days int) error {
+					if days <= 0 {
+						return fmt.Errorf("--churn-days must be positive")
+					}
+					return nil
+				},
+			},
+			&cli.BoolFlag{
+				Name:    "follow-symlinks",
+				Aliases: []string{"fsl"},
+				Usage:   "Descend into symbolic links to directories, skipping link cycles and files reachable through several links",
+			},
+			&cli.IntFlag{
+				Name:    "max-depth",
+				Aliases: []string{"md"},
+				Usage:   "Deepest directory level collected, 1 for the files directly in the root path (0 for no limit)",
+			},
+			&cli.BoolFlag{
+				Name:    "skip-hidden",
+				Aliases: []string{"sh"},
+				Usage:   "Skip files and directories whose name starts with a dot",
+			},
+			&cli.BoolFlag{
+				Name:    "one-file-system",
+				Aliases: []string{"ofs"},
+				Usage:   "Don't descend into directories on another filesystem than the root path",
+			},
+			&cli.BoolFlag{
+				Name:    "no-cache",
+				Aliases: []string{"nc"},
+				Usage:   "Analyze every file instead of reusing the cached results of files unchanged since the last run",
+			},
+			&cli.BoolFlag{
+				Name:  "db",
+				Usage: "Store the metadata and results of every root path in the database",
+			},
+			&cli.BoolFlag{
+				Name:  "from-db",
+				Usage: "Regenerate the reports of the root paths from the analysis stored with --db instead of reading the files",
+			},
+			&cli.IntFlag{
+				Name:  "db-keep",
+				Usage: "With --db, delete all but the n newest runs of every root path from the database; 0 keeps all runs",
+			},
+			&cli.StringFlag{
+				Name:    "db-path",
+				Usage:   "The database file of the result cache, --db and --from-db",
+				EnvVars: []string{DatabasePathEnv},
+				Value:   "StatifyDatabase.db",
+			},
 			&cli.StringSliceFlag{
-				Name:    "output-path",
-				Aliases: []string{"op"},
-				Usage:   "Specify output path where images and markdown file are stored",
+				Name:    "test-patterns",
+				Aliases: []string{"tp"},
+				Usage:   "File name globs (e.g. \"*_test.go\") or directories ending with \"/\" that mark test files; replaces the defaults",
+			},
+		},
+		Commands: []*cli.Command{
+			{
+				Name:      CommandDiff,
+				Usage:     "Compare two roots or two snapshot.json files and report the deltas",
+				ArgsUsage: "<before> <after>",
+				Flags:     []cli.Flag{newOutputPathFlag()},
+				Action: func(ctx *cli.Context) error {
+					if ctx.NArg() != 2 {
+						return fmt.Errorf("%s expects a before and an after path, got %d paths", CommandDiff, ctx.NArg())
+					}
+					parseCommonArgs(ctx, &args)
+					args.Command = CommandDiff
+					args.DiffPaths = ctx.Args().Slice()
+					return nil
+				},
+			},
+			{
+				Name:      CommandHistory,
+				Usage:     "Analyze sampled commits of a git repository and chart the lines per language over time",
+				ArgsUsage: "<repository>",
+				Flags: []cli.Flag{
+					newOutputPathFlag(),
+					&cli.IntFlag{
+						Name:    "every",
+						Aliases: []string{"n"},
+						Usage:   "Sample every n-th commit of the first-parent history",
+					},
+					&cli.StringFlag{
+						Name:  "period",
+						Usage: "Sample the last commit of each \"week\" or \"month\" (default when --every isn't set)",
+					},
+				},
+				Action: func(ctx *cli.Context) error {
+					if ctx.NArg() != 1 {
+						return fmt.Errorf("%s expects one repository path, got %d paths", CommandHistory, ctx.NArg())
+					}
+					if ctx.IsSet("every") && ctx.IsSet("period") {
+						return fmt.Errorf("%s accepts either --every or --period, not both", CommandHistory)
+					}
+					parseCommonArgs(ctx, &args)
+					args.Command = CommandHistory
+					args.HistoryPath = ctx.Args().First()
+					args.HistoryEvery = ctx.Int("every")
+					args.HistoryPeriod = ctx.String("period")
+					if !ctx.IsSet("every") && args.HistoryPeriod == "" {
+						args.HistoryPeriod = "month"
+					}
+					return nil
+				},
+			},
+			{
+				Name:      CommandWatch,
+				Usage:     "Analyze a root, then keep re-analyzing changed files and regenerating files.md and the charts",
+				ArgsUsage: "<root>",
+				Flags: []cli.Flag{
+					newOutputPathFlag(),
+					&cli.DurationFlag{
+						Name:  "debounce",
+						Usage: "Time without further changes before the reports are regenerated",
+						Value: 500 * time.Millisecond,
+					},
+					&cli.DurationFlag{
+						Name:  "poll-interval",
+						Usage: "How often the root is scanned for changes when polling",
+						Value: time.Second,
+					},
+					&cli.BoolFlag{
+						Name:  "poll",
+						Usage: "Poll for changes even if inotify is available, e.g. on network filesystems",
+					},
+				},
+				Action: func(ctx *cli.Context) error {
+					if ctx.NArg() != 1 {
+						return fmt.Errorf("%s expects one root path, got %d paths", CommandWatch, ctx.NArg())
+					}
+					parseCommonArgs(ctx, &args)
+					args.Command = CommandWatch
+					args.WatchPath = ctx.Args().First()
+					args.WatchDebounce = ctx.Duration("debounce")
+					args.WatchInterval = ctx.Duration("poll-interval")
+					args.WatchPoll = ctx.Bool("poll")
+					return nil
+				},
+			},
+			{
+				Name:  CommandDatabase,
+				Usage: "Manage the schema of the database",
+				Subcommands: []*cli.Command{
+					{
+						Name:   DatabaseMigrate,
+						Usage:  "Apply the pending schema migrations; analyses also migrate the database on first use",
+						Action: newDatabaseAction(&args, DatabaseMigrate),
+					},
+					{
+						Name:   DatabaseStatus,
+						Usage:  "List the schema migrations and whether they are applied, without changing the database",
+						Action: newDatabaseAction(&args, DatabaseStatus),
+					},
+				},
 			},
 		},
 		Action: func(ctx *cli.Context) error {
-			args.RootPaths = ctx.StringSlice("paths")
-			args.IncludeComment = ctx.Bool("include-comment")
-			args.OutputPaths = parseOutputPath(ctx)
-
+			parseCommonArgs(ctx, &args)
+			if len(args.RootPaths) == 0 {
+				return fmt.Errorf("required flag \"paths\" not set")
+			}
+			if args.SaveToDB && args.FromDB {
+				return fmt.Errorf("--db and --from-db can't be combined")
+			}
+			if args.KeepRuns < 0 {
+				return fmt.Errorf("--db-keep can't be negative")
+			}
 			return nil
 		},
 	}
@@ -53,6 +319,54 @@ func ParseArgs(arguments []string) (*Args, error) {
 	return &args, nil
 }
 
+// newDatabaseAction returns the action of a db subcommand.
+func newDatabaseAction(args *Args, action string) cli.ActionFunc {
+	return func(ctx *cli.Context) error {
+		if ctx.NArg() != 0 {
+			return fmt.Errorf("%s %s doesn't take arguments, set the database with --db-path", CommandDatabase, action)
+		}
+		parseCommonArgs(ctx, args)
+		args.Command = CommandDatabase
+		args.DatabaseAction = action
+		return nil
+	}
+}
+
+func newOutputPathFlag() cli.Flag {
+	return &cli.StringSliceFlag{
+		Name:    "output-path",
+		Aliases: []string{"op"},
+		Usage:   "Specify output path where images and markdown file are stored",
+	}
+}
+
+// parseCommonArgs reads the flags shared by the default analysis and the subcommands.
+func parseCommonArgs(ctx *cli.Context, args *Args) {
+	args.RootPaths = ctx.StringSlice("paths")
+	args.IncludeComment = ctx.Bool("include-comment")
+	args.OutputPaths = parseOutputPath(ctx)
+	args.DetectClones = ctx.Bool("detect-clones")
+	args.CloneMinTokens = ctx.Int("clone-min-tokens")
+	args.TestPatterns = parseTestPatterns(ctx)
+	args.MaxLineLength = ctx.Int("max-line-length")
+	args.MIThreshold = ctx.Float64("mi-threshold")
+	args.Projects = ctx.Bool("projects")
+	args.Aggregate = ctx.Bool("aggregate")
+	args.Revision = ctx.String("rev")
+	args.Ownership = ctx.Bool("ownership")
+	args.Hotspots = ctx.Bool("hotspots")
+	args.ChurnDays = ctx.Int("churn-days")
+	args.FollowSymlinks = ctx.Bool("follow-symlinks")
+	args.MaxDepth = ctx.Int("max-depth")
+	args.SkipHidden = ctx.Bool("skip-hidden")
+	args.OneFileSystem = ctx.Bool("one-file-system")
+	args.NoCache = ctx.Bool("no-cache")
+	args.SaveToDB = ctx.Bool("db")
+	args.FromDB = ctx.Bool("from-db")
+	args.KeepRuns = ctx.Int("db-keep")
+	args.DatabasePath = ctx.String("db-path")
+}
+
 func parseOutputPath(ctx *cli.Context) OptionalArg[[]string] {
 	values := ctx.StringSlice("output-path")
 	return OptionalArg[[]string]{
@@ -60,3 +374,11 @@ func parseOutputPath(ctx *cli.Context) OptionalArg[[]string] {
 		Value: values,
 	}
 }
+
+func parseTestPatterns(ctx *cli.Context) OptionalArg[[]string] {
+	values := ctx.StringSlice("test-patterns")
+	return OptionalArg[[]string]{
+		IsSet: len(values) > 0,
+		Value: values,
+	}
+}
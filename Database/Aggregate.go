@@ -0,0 +1,386 @@
+package Database
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"statfiy/Analyzer"
+	"strings"
+)
+
+// Totals are the summed sizes of a group of analyzed files.
+type Totals struct {
+	Files       int
+	Size        int64 // File sizes in bytes
+	TotalSize   int64
+	CodeSize    int64
+	CommentSize int64
+	BlankLines  int
+}
+
+// CommentRatio returns the share of comments in the code and comments, from 0 to 1, or 0 without either.
+func (t Totals) CommentRatio() float64 {
+	if t.CodeSize+t.CommentSize == 0 {
+		return 0
+	}
+	return float64(t.CommentSize) / float64(t.CodeSize+t.CommentSize)
+}
+
+// LanguageTotals are the totals of the files of a language.
+type LanguageTotals struct {
+	Language Analyzer.Language
+	Totals
+}
+
+// ExtensionTotals are the totals of the files with an extension.
+type ExtensionTotals struct {
+	Extension string // Extension including the dot, empty for files without one
+	Totals
+}
+
+// DirectoryTotals are the totals of the files in a directory and its subdirectories.
+type DirectoryTotals struct {
+	Dir string
+	Totals
+}
+
+// FileSize is the size of one analyzed file, as returned by LargestFiles.
+type FileSize struct {
+	FileId    int // TblFileMetadata.id
+	ResultId  int // TblAnalyzeFileResult.id
+	Path      string
+	Language  Analyzer.Language
+	Size      int64 // File size in bytes
+	TotalSize int64
+	CodeSize  int64
+}
+
+// LanguageCommentRatio is the share of comments in the code and comments of a language.
+type LanguageCommentRatio struct {
+	Language    Analyzer.Language
+	CodeSize    int64
+	CommentSize int64
+	Ratio       float64 // From 0 to 1
+}
+
+// LanguageChange compares the totals of a language in two runs. Totals are zero in the run without the language.
+type LanguageChange struct {
+	Language Analyzer.Language
+	Old      Totals
+	New      Totals
+}
+
+// Delta returns the change from the old to the new run, e.g. to chart how the languages changed.
+func (c LanguageChange) Delta() LanguageTotals {
+	return LanguageTotals{Language: c.Language, Totals: Totals{
+		Files:       c.New.Files - c.Old.Files,
+		Size:        c.New.Size - c.Old.Size,
+		TotalSize:   c.New.TotalSize - c.Old.TotalSize,
+		CodeSize:    c.New.CodeSize - c.Old.CodeSize,
+		CommentSize: c.New.CommentSize - c.Old.CommentSize,
+		BlankLines:  c.New.BlankLines - c.Old.BlankLines,
+	}}
+}
+
+// RunComparison compares two stored runs language by language.
+type RunComparison struct {
+	Old       AnalysisRun
+	New       AnalysisRun
+	Languages []LanguageChange // Largest language of the new run first
+}
+
+// TotalsByLanguage sums the files of every language in the database.
+//
+// Arguments:
+//   - filters: The conditions on the TblFileMetadata and TblAnalyzeFileResult columns, e.g. Equal(ColumnRunId, id).
+//
+// Returns:
+//   - []LanguageTotals: The totals of every language with matching files, largest TotalSize first.
+//   - error: An error if a filter is invalid or the database can't be read.
+func (s *Store) TotalsByLanguage(filters ...Filter) ([]LanguageTotals, error) {
+	var totals []LanguageTotals
+	err := s.queryTotals(columns[ColumnLanguage], filters, func(rows *sql.Rows) error {
+		var row LanguageTotals
+		if err := rows.Scan(append([]any{&row.Language}, totalsDestinations(&row.Totals)...)...); err != nil {
+			return err
+		}
+		totals = append(totals, row)
+		return nil
+	})
+	return totals, err
+}
+
+// TotalsByExtension sums the files of every extension in the database.
+//
+// Arguments:
+//   - filters: The conditions on the TblFileMetadata and TblAnalyzeFileResult columns, e.g. Equal(ColumnRunId, id).
+//
+// Returns:
+//   - []ExtensionTotals: The totals of every extension with matching files, largest TotalSize first.
+//   - error: An error if a filter is invalid or the database can't be read.
+func (s *Store) TotalsByExtension(filters ...Filter) ([]ExtensionTotals, error) {
+	var totals []ExtensionTotals
+	err := s.queryTotals(columns[ColumnExtension], filters, func(rows *sql.Rows) error {
+		var row ExtensionTotals
+		if err := rows.Scan(append([]any{&row.Extension}, totalsDestinations(&row.Totals)...)...); err != nil {
+			return err
+		}
+		totals = append(totals, row)
+		return nil
+	})
+	return totals, err
+}
+
+// TotalsByDirectory sums the files in each of several directories, including their subdirectories. A file is
+// counted once for every directory containing it.
+//
+// Arguments:
+//   - dirs: The directories, as stored in the Dir column, e.g. the root path and its top level directories.
+//   - filters: The conditions on the TblFileMetadata and TblAnalyzeFileResult columns, e.g. Equal(ColumnRunId, id).
+//
+// Returns:
+//   - []DirectoryTotals: The totals of every directory, in the order of dirs. Directories without matching
+//     files have zero totals.
+//   - error: An error if a filter is invalid or the database can't be read.
+func (s *Store) TotalsByDirectory(dirs []string, filters ...Filter) ([]DirectoryTotals, error) {
+	if err := s.ensureSchema(); err != nil {
+		return nil, err
+	}
+
+	where, parameters, err := buildFilters(filters, func(string) bool { return true })
+	if err != nil {
+		return nil, err
+	}
+	if where == "" {
+		where = " WHERE "
+	} else {
+		where += " AND "
+	}
+	// LIKE ignores case, so subdirectories are matched by comparing the start of Dir
+	where += fmt.Sprintf("(%[1]v.Dir = ? OR substr(%[1]v.Dir, 1, length(?)) = ?)", fileMetadataTableName)
+	query := fmt.Sprintf("SELECT %v FROM %v", totalsColumnsText(), joinedTablesText()) + where
+
+	totals := make([]DirectoryTotals, len(dirs))
+	for i, dir := range dirs {
+		dir = filepath.Clean(dir)
+		subdirs := dir
+		if !strings.HasSuffix(subdirs, string(filepath.Separator)) {
+			subdirs += string(filepath.Separator)
+		}
+
+		totals[i].Dir = dir
+		row := s.db.QueryRow(query, append(parameters, dir, subdirs, subdirs)...)
+		if err := row.Scan(totalsDestinations(&totals[i].Totals)...); err != nil {
+			return nil, err
+		}
+	}
+	return totals, nil
+}
+
+// LargestFiles returns the n largest files by a column, e.g. ColumnTotalSize or ColumnCodeSize.
+//
+// Arguments:
+//   - n: The maximum number of files.
+//   - by: The column the files are sorted by, largest first.
+//   - filters: The conditions on the TblFileMetadata and TblAnalyzeFileResult columns, e.g. Equal(ColumnRunId, id).
+//
+// Returns:
+//   - []FileSize: At most n files, largest first.
+//   - error: An error if n is not positive, a column is invalid or the database can't be read.
+func (s *Store) LargestFiles(n int, by Column, filters ...Filter) ([]FileSize, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("the number of files has to be positive")
+	}
+	if err := s.ensureSchema(); err != nil {
+		return nil, err
+	}
+
+	clauses, parameters, err := Query{Filters: filters, OrderBy: []Order{{Column: by, Descending: true}}, Limit: n}.
+		build(func(string) bool { return true }, ColumnResultId)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(fmt.Sprintf(`
+		SELECT %[1]v.id, %[2]v.id, %[1]v.Path, %[2]v.Language, %[1]v.Size, %[2]v.TotalSize, %[2]v.CodeSize
+		FROM %[3]v`,
+		fileMetadataTableName,
+		analyzeFileResultTableName,
+		joinedTablesText())+clauses,
+		parameters...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var files []FileSize
+	for rows.Next() {
+		var file FileSize
+		if err := rows.Scan(&file.FileId, &file.ResultId, &file.Path, &file.Language, &file.Size, &file.TotalSize, &file.CodeSize); err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	return files, rows.Err()
+}
+
+// CommentRatios returns the share of comments in the code and comments of every language.
+//
+// Arguments:
+//   - filters: The conditions on the TblFileMetadata and TblAnalyzeFileResult columns, e.g. Equal(ColumnRunId, id).
+//
+// Returns:
+//   - []LanguageCommentRatio: The ratio of every language with matching files, highest ratio first.
+//   - error: An error if a filter is invalid or the database can't be read.
+func (s *Store) CommentRatios(filters ...Filter) ([]LanguageCommentRatio, error) {
+	if err := s.ensureSchema(); err != nil {
+		return nil, err
+	}
+
+	where, parameters, err := buildFilters(filters, func(string) bool { return true })
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(fmt.Sprintf(`
+		SELECT %[1]v.Language, SUM(%[1]v.CodeSize), SUM(%[1]v.CommentSize),
+		COALESCE(CAST(SUM(%[1]v.CommentSize) AS REAL) / NULLIF(SUM(%[1]v.CodeSize) + SUM(%[1]v.CommentSize), 0), 0) AS Ratio
+		FROM %[2]v%[3]v
+		GROUP BY %[1]v.Language
+		ORDER BY Ratio DESC, %[1]v.Language`,
+		analyzeFileResultTableName,
+		joinedTablesText(),
+		where),
+		parameters...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ratios []LanguageCommentRatio
+	for rows.Next() {
+		var ratio LanguageCommentRatio
+		if err := rows.Scan(&ratio.Language, &ratio.CodeSize, &ratio.CommentSize, &ratio.Ratio); err != nil {
+			return nil, err
+		}
+		ratios = append(ratios, ratio)
+	}
+	return ratios, rows.Err()
+}
+
+// CompareRuns compares the languages of two stored runs, e.g. two runs of a root listed by ListRuns.
+//
+// Arguments:
+//   - oldRunId: The id of the run compared against.
+//   - newRunId: The id of the compared run.
+//
+// Returns:
+//   - RunComparison: The runs and the totals of every language found in either run.
+//   - error: An error if a run doesn't exist or the database can't be read.
+func (s *Store) CompareRuns(oldRunId, newRunId int64) (RunComparison, error) {
+	if err := s.ensureSchema(); err != nil {
+		return RunComparison{}, err
+	}
+
+	var comparison RunComparison
+	changes := make(map[Analyzer.Language]*LanguageChange)
+	for i, runId := range []int64{oldRunId, newRunId} {
+		run, err := scanRun(s.db.QueryRow(runQueryText()+" WHERE id = ?", runId))
+		if err == sql.ErrNoRows {
+			return RunComparison{}, fmt.Errorf("run %d doesn't exist", runId)
+		}
+		if err != nil {
+			return RunComparison{}, err
+		}
+
+		languages, err := s.TotalsByLanguage(Equal(ColumnRunId, runId))
+		if err != nil {
+			return RunComparison{}, err
+		}
+		for _, language := range languages {
+			change := changes[language.Language]
+			if change == nil {
+				change = &LanguageChange{Language: language.Language}
+				changes[language.Language] = change
+			}
+			if i == 0 {
+				change.Old = language.Totals
+			} else {
+				change.New = language.Totals
+			}
+		}
+		if i == 0 {
+			comparison.Old = run
+		} else {
+			comparison.New = run
+		}
+	}
+
+	for _, change := range changes {
+		comparison.Languages = append(comparison.Languages, *change)
+	}
+	sort.Slice(comparison.Languages, func(i, j int) bool {
+		a, b := comparison.Languages[i], comparison.Languages[j]
+		if a.New.TotalSize != b.New.TotalSize {
+			return a.New.TotalSize > b.New.TotalSize
+		}
+		if a.Old.TotalSize != b.Old.TotalSize {
+			return a.Old.TotalSize > b.Old.TotalSize
+		}
+		return a.Language.String() < b.Language.String()
+	})
+	return comparison, nil
+}
+
+// queryTotals sums the matching files grouped by a column, largest TotalSize first, and passes the row of every
+// group to add. A row holds the group followed by the columns of totalsColumnsText.
+func (s *Store) queryTotals(group columnSource, filters []Filter, add func(rows *sql.Rows) error) error {
+	if err := s.ensureSchema(); err != nil {
+		return err
+	}
+
+	where, parameters, err := buildFilters(filters, func(string) bool { return true })
+	if err != nil {
+		return err
+	}
+	groupText := fmt.Sprintf("%v.%v", *group.table, group.name)
+
+	rows, err := s.db.Query(fmt.Sprintf(
+		"SELECT %[1]v, %[2]v FROM %[3]v%[4]v GROUP BY %[1]v ORDER BY SUM(%[5]v.TotalSize) DESC, %[1]v",
+		groupText,
+		totalsColumnsText(),
+		joinedTablesText(),
+		where,
+		analyzeFileResultTableName),
+		parameters...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := add(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// totalsColumnsText returns the aggregates read into totalsDestinations, which are zero for no rows.
+func totalsColumnsText() string {
+	return fmt.Sprintf(`COUNT(*), COALESCE(SUM(%[1]v.Size), 0), COALESCE(SUM(%[2]v.TotalSize), 0),
+		COALESCE(SUM(%[2]v.CodeSize), 0), COALESCE(SUM(%[2]v.CommentSize), 0), COALESCE(SUM(%[2]v.BlankLines), 0)`,
+		fileMetadataTableName,
+		analyzeFileResultTableName)
+}
+
+// joinedTablesText returns TblFileMetadata joined with TblAnalyzeFileResult.
+func joinedTablesText() string {
+	return fmt.Sprintf("%[1]v JOIN %[2]v ON %[2]v.FileMetadataId = %[1]v.id", fileMetadataTableName, analyzeFileResultTableName)
+}
+
+// totalsDestinations returns the Scan destinations of the columns of totalsColumnsText.
+func totalsDestinations(totals *Totals) []any {
+	return []any{&totals.Files, &totals.Size, &totals.TotalSize, &totals.CodeSize, &totals.CommentSize, &totals.BlankLines}
+}
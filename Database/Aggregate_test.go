@@ -0,0 +1,114 @@
+package Database
+
+import (
+	"path/filepath"
+	"statfiy/Analyzer"
+	"statfiy/FileManager"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregates(t *testing.T) {
+	store := openTestStore(t)
+
+	result := func(path string, language Analyzer.Language, codeSize, commentSize int64) Analyzer.AnalyzeFileResult {
+		path = filepath.FromSlash(path)
+		return Analyzer.AnalyzeFileResult{
+			FileMetadata: FileManager.FileMetadata{
+				Name:       filepath.Base(path),
+				Path:       path,
+				Dir:        filepath.Dir(path),
+				Extension:  filepath.Ext(path),
+				Size:       codeSize + commentSize,
+				ModifiedAt: time.Now(),
+			},
+			Language:    language,
+			CodeSize:    codeSize,
+			CommentSize: commentSize,
+			BlankLines:  1,
+			TotalSize:   codeSize + commentSize + 1,
+		}
+	}
+	old, _, err := store.SaveAnalysis(AnalysisRun{RootPath: "/repo"}, []Analyzer.AnalyzeFileResult{
+		result("/repo/main.go", Analyzer.Go, 100, 0),
+		result("/repo/tool.py", Analyzer.Python, 10, 10),
+	})
+	require.NoError(t, err)
+	current, _, err := store.SaveAnalysis(AnalysisRun{RootPath: "/repo"}, []Analyzer.AnalyzeFileResult{
+		result("/repo/main.go", Analyzer.Go, 120, 30),
+		result("/repo/pkg/util.go", Analyzer.Go, 40, 10),
+		result("/repo/pkg_old/a.go", Analyzer.Go, 5, 0),
+		result("/repo/build", Analyzer.Shell, 8, 0),
+	})
+	require.NoError(t, err)
+	run := Equal(ColumnRunId, current.Id)
+
+	languages, err := store.TotalsByLanguage(run)
+	require.NoError(t, err)
+	require.Len(t, languages, 2)
+	require.Equal(t, Analyzer.Go, languages[0].Language)
+	require.Equal(t, Totals{Files: 3, Size: 205, TotalSize: 208, CodeSize: 165, CommentSize: 40, BlankLines: 3}, languages[0].Totals)
+	require.InDelta(t, 40.0/205, languages[0].CommentRatio(), 1e-9)
+
+	all, err := store.TotalsByLanguage()
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	require.Equal(t, 4, all[0].Files)
+
+	extensions, err := store.TotalsByExtension(run)
+	require.NoError(t, err)
+	require.Equal(t, []ExtensionTotals{
+		{Extension: ".go", Totals: languages[0].Totals},
+		{Extension: "", Totals: Totals{Files: 1, Size: 8, TotalSize: 9, CodeSize: 8, BlankLines: 1}},
+	}, extensions)
+
+	// A directory contains its subdirectories but not siblings sharing its name as a prefix
+	dirs, err := store.TotalsByDirectory([]string{filepath.FromSlash("/repo"), filepath.FromSlash("/repo/pkg/"), filepath.FromSlash("/missing")}, run)
+	require.NoError(t, err)
+	require.Len(t, dirs, 3)
+	require.Equal(t, 4, dirs[0].Files)
+	require.Equal(t, filepath.FromSlash("/repo/pkg"), dirs[1].Dir)
+	require.Equal(t, Totals{Files: 1, Size: 50, TotalSize: 51, CodeSize: 40, CommentSize: 10, BlankLines: 1}, dirs[1].Totals)
+	require.Zero(t, dirs[2].Files)
+
+	// Directories are compared case-sensitively, like paths on Linux
+	dirs, err = store.TotalsByDirectory([]string{filepath.FromSlash("/Repo"), filepath.FromSlash("/repo/PKG")}, run)
+	require.NoError(t, err)
+	require.Zero(t, dirs[0].Files)
+	require.Zero(t, dirs[1].Files)
+
+	largest, err := store.LargestFiles(2, ColumnCodeSize, run)
+	require.NoError(t, err)
+	require.Len(t, largest, 2)
+	require.Equal(t, filepath.FromSlash("/repo/main.go"), largest[0].Path)
+	require.Equal(t, int64(120), largest[0].CodeSize)
+	require.Equal(t, filepath.FromSlash("/repo/pkg/util.go"), largest[1].Path)
+	_, err = store.LargestFiles(0, ColumnCodeSize)
+	require.Error(t, err)
+	_, err = store.LargestFiles(1, Column("Result"))
+	require.Error(t, err)
+
+	ratios, err := store.CommentRatios(Equal(ColumnRunId, old.Id))
+	require.NoError(t, err)
+	require.Equal(t, []LanguageCommentRatio{
+		{Language: Analyzer.Python, CodeSize: 10, CommentSize: 10, Ratio: 0.5},
+		{Language: Analyzer.Go, CodeSize: 100},
+	}, ratios)
+
+	comparison, err := store.CompareRuns(old.Id, current.Id)
+	require.NoError(t, err)
+	require.Equal(t, old.Id, comparison.Old.Id)
+	require.Equal(t, current.Id, comparison.New.Id)
+	require.Len(t, comparison.Languages, 3)
+	require.Equal(t, Analyzer.Go, comparison.Languages[0].Language)
+	require.Equal(t, 2, comparison.Languages[0].Delta().Files)
+	require.Equal(t, Analyzer.Python, comparison.Languages[2].Language)
+	require.Equal(t, int64(-21), comparison.Languages[2].Delta().TotalSize)
+	_, err = store.CompareRuns(old.Id, current.Id+1)
+	require.Error(t, err)
+
+	_, err = store.TotalsByLanguage(Filter{})
+	require.Error(t, err)
+}
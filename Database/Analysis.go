@@ -0,0 +1,75 @@
+package Database
+
+import (
+	"database/sql"
+	"fmt"
+	"statfiy/Analyzer"
+	"time"
+)
+
+// SaveAnalysis stores the analyzed files of a root path as a new run, in TblAnalysisRun, TblFileMetadata,
+// TblAnalyzeFileResult and TblAnalyzeFileDetail within one transaction using prepared statements. Earlier runs
+// are kept until they are deleted with DeleteRuns.
+//
+// Arguments:
+//   - run: The run to store. Id and Files are set by SaveAnalysis, a zero CreatedAt is set to the current time.
+//   - results: The analyzed files.
+//
+// Returns:
+//   - AnalysisRun: The stored run.
+//   - InsertStats: The number of stored files and the time it took.
+//   - error: An error if the database can't be written; nothing is stored then.
+func (s *Store) SaveAnalysis(run AnalysisRun, results []Analyzer.AnalyzeFileResult) (AnalysisRun, InsertStats, error) {
+	if run.CreatedAt.IsZero() {
+		run.CreatedAt = time.Now()
+	}
+	run.Files = len(results)
+
+	stats, err := s.insertBatch(len(results), func(tx *sql.Tx) error {
+		inserted, err := tx.Exec(fmt.Sprintf("INSERT INTO %v (RootPath, CreatedAt, GitCommit, Options, ToolVersion) VALUES (?, ?, ?, ?, ?)", analysisRunTableName),
+			run.RootPath, run.CreatedAt.UnixNano(), run.GitCommit, run.Options, run.ToolVersion)
+		if err != nil {
+			return fmt.Errorf("failed to insert run of %s: %w", run.RootPath, err)
+		}
+		if run.Id, err = inserted.LastInsertId(); err != nil {
+			return err
+		}
+
+		inserter, err := newBatchInserter(tx, run.Id)
+		if err != nil {
+			return err
+		}
+		defer inserter.close()
+
+		for _, result := range results {
+			if result.FileMetadata.Id, err = inserter.insertMetadata(result.FileMetadata); err != nil {
+				return err
+			}
+			if _, err := inserter.insertResult(result); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return AnalysisRun{}, InsertStats{}, err
+	}
+	return run, stats, nil
+}
+
+// LoadAnalysis reads the latest run of a root path stored by SaveAnalysis and its analyzed files.
+//
+// Arguments:
+//   - rootPath: The absolute root path the files were collected from.
+//
+// Returns:
+//   - AnalysisRun: The latest run, with a zero Id if the root wasn't stored.
+//   - []Analyzer.AnalyzeFileResult: The analyzed files of the run.
+//   - error: An error if the database can't be read.
+func (s *Store) LoadAnalysis(rootPath string) (AnalysisRun, []Analyzer.AnalyzeFileResult, error) {
+	runs, err := s.ListRuns(rootPath)
+	if err != nil || len(runs) == 0 {
+		return AnalysisRun{}, nil, err
+	}
+	return s.LoadRun(runs[0].Id)
+}
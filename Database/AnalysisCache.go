@@ -0,0 +1,113 @@
+package Database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"statfiy/Analyzer"
+	"time"
+)
+
+// AnalysisCache is an Analyzer.ResultCache storing the results in the TblAnalysisCache table of a Store.
+type AnalysisCache struct {
+	db *sql.DB
+}
+
+// AnalysisCache returns the result cache of the store. It uses the connections of the store and stays usable
+// until the store is closed.
+//
+// Returns:
+//   - *AnalysisCache: The cache.
+//   - error: An error if the tables can't be created.
+func (s *Store) AnalysisCache() (*AnalysisCache, error) {
+	if err := s.ensureSchema(); err != nil {
+		return nil, err
+	}
+	return &AnalysisCache{db: s.db}, nil
+}
+
+// Lookup returns the cached result of a path and whether one was found.
+func (c *AnalysisCache) Lookup(path string) (Analyzer.CachedResult, bool, error) {
+	entry := Analyzer.CachedResult{Path: path}
+	var modifiedAt int64
+	var result string
+
+	row := c.db.QueryRow(fmt.Sprintf("SELECT Size, ModifiedAt, ContentHash, Settings, Result FROM %v WHERE Path = ?", analysisCacheTableName), path)
+	err := row.Scan(&entry.Size, &modifiedAt, &entry.ContentHash, &entry.Settings, &result)
+	if err == sql.ErrNoRows {
+		return Analyzer.CachedResult{}, false, nil
+	}
+	if err != nil {
+		return Analyzer.CachedResult{}, false, err
+	}
+
+	if err := json.Unmarshal([]byte(result), &entry.Result); err != nil {
+		return Analyzer.CachedResult{}, false, fmt.Errorf("failed to decode cached result: %w", err)
+	}
+	entry.ModifiedAt = time.Unix(0, modifiedAt)
+	return entry, true, nil
+}
+
+// Store adds or replaces the cached results of the entries' paths within one transaction, with a statement
+// prepared once. Nothing is stored if an entry fails.
+func (c *AnalysisCache) Store(entries ...Analyzer.CachedResult) error {
+	tx, err := c.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	statement, err := tx.Prepare(fmt.Sprintf("INSERT OR REPLACE INTO %v VALUES (?, ?, ?, ?, ?, ?)", analysisCacheTableName))
+	if err != nil {
+		return err
+	}
+	defer statement.Close()
+
+	for _, entry := range entries {
+		result, err := json.Marshal(entry.Result)
+		if err != nil {
+			return fmt.Errorf("failed to encode result of %s: %w", entry.Path, err)
+		}
+		_, err = statement.Exec(
+			entry.Path,
+			entry.Size,
+			entry.ModifiedAt.UnixNano(),
+			entry.ContentHash,
+			entry.Settings,
+			string(result))
+		if err != nil {
+			return fmt.Errorf("failed to cache result of %s: %w", entry.Path, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// Remove drops the cached results of the paths within one transaction.
+func (c *AnalysisCache) Remove(paths ...string) error {
+	tx, err := c.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	statement, err := tx.Prepare(fmt.Sprintf("DELETE FROM %v WHERE Path = ?", analysisCacheTableName))
+	if err != nil {
+		return err
+	}
+	defer statement.Close()
+
+	for _, path := range paths {
+		if _, err := statement.Exec(path); err != nil {
+			return fmt.Errorf("failed to remove cached result of %s: %w", path, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
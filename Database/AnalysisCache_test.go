@@ -0,0 +1,63 @@
+package Database
+
+import (
+	"statfiy/Analyzer"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalysisCache(t *testing.T) {
+	store := openTestStore(t)
+
+	cache, err := store.AnalysisCache()
+	require.NoError(t, err)
+
+	_, found, err := cache.Lookup("/repo/main.go")
+	require.NoError(t, err)
+	require.False(t, found)
+
+	entry := Analyzer.CachedResult{
+		Path:        "/repo/main.go",
+		Size:        42,
+		ModifiedAt:  time.Date(2024, 1, 1, 12, 0, 0, 123, time.UTC),
+		ContentHash: "abc",
+		Settings:    "v1",
+		Result: Analyzer.AnalyzeFileResult{
+			Language:   Analyzer.Go,
+			TotalSize:  42,
+			CodeLines:  3,
+			Complexity: 2,
+			Imports:    []string{"fmt"},
+		},
+	}
+	require.NoError(t, cache.Store(entry))
+
+	cached, found, err := cache.Lookup("/repo/main.go")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.True(t, entry.ModifiedAt.Equal(cached.ModifiedAt))
+	cached.ModifiedAt = entry.ModifiedAt
+	cached.Result.FileMetadata.ModifiedAt = entry.Result.FileMetadata.ModifiedAt
+	require.Equal(t, entry, cached)
+
+	entry.ContentHash = "def"
+	other := entry
+	other.Path = "/repo/util.go"
+	require.NoError(t, cache.Store(entry, other))
+	cached, _, err = cache.Lookup("/repo/main.go")
+	require.NoError(t, err)
+	require.Equal(t, "def", cached.ContentHash)
+	_, found, err = cache.Lookup("/repo/util.go")
+	require.NoError(t, err)
+	require.True(t, found)
+
+	require.NoError(t, cache.Remove("/repo/util.go", "/repo/missing.go"))
+	_, found, err = cache.Lookup("/repo/util.go")
+	require.NoError(t, err)
+	require.False(t, found)
+	_, found, err = cache.Lookup("/repo/main.go")
+	require.NoError(t, err)
+	require.True(t, found)
+}
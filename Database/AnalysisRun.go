@@ -0,0 +1,170 @@
+package Database
+
+import (
+	"database/sql"
+	"fmt"
+	"statfiy/Analyzer"
+	"time"
+)
+
+// AnalysisRun is one analysis of a root path stored by SaveAnalysis. The TblFileMetadata and TblAnalyzeFileResult
+// rows of its files reference it by their RunId column.
+type AnalysisRun struct {
+	Id          int64
+	RootPath    string    // Absolute root path the files were collected from
+	CreatedAt   time.Time // Time the run was saved
+	GitCommit   string    // Analyzed commit, empty if the root isn't in a git repository
+	Options     string    // Options the files were collected and analyzed with, as encoded by the caller
+	ToolVersion string    // Version of Statify that analyzed the files
+	Files       int       // Number of analyzed files, set when reading runs
+}
+
+// RetentionPolicy selects the runs deleted by DeleteRuns. Zero fields don't delete anything.
+type RetentionPolicy struct {
+	KeepLast int           // Number of newest runs kept per root path
+	MaxAge   time.Duration // Runs older than this are deleted
+}
+
+// ListRuns returns the stored runs, newest first.
+//
+// Arguments:
+//   - rootPath: The root path whose runs are listed, or an empty string for the runs of all roots.
+//
+// Returns:
+//   - []AnalysisRun: The runs with their number of files.
+//   - error: An error if the database can't be read.
+func (s *Store) ListRuns(rootPath string) ([]AnalysisRun, error) {
+	if err := s.ensureSchema(); err != nil {
+		return nil, err
+	}
+
+	query := runQueryText()
+	var arguments []any
+	if rootPath != "" {
+		query += " WHERE RootPath = ?"
+		arguments = append(arguments, rootPath)
+	}
+	query += " ORDER BY CreatedAt DESC, id DESC"
+
+	rows, err := s.db.Query(query, arguments...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []AnalysisRun
+	for rows.Next() {
+		run, err := scanRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+
+	return runs, rows.Err()
+}
+
+// LoadRun reads a stored run and its analyzed files, in the order they were saved.
+//
+// Arguments:
+//   - runId: The id of the run, see ListRuns.
+//
+// Returns:
+//   - AnalysisRun: The run.
+//   - []Analyzer.AnalyzeFileResult: The analyzed files of the run.
+//   - error: An error if the run doesn't exist or the database can't be read.
+func (s *Store) LoadRun(runId int64) (AnalysisRun, []Analyzer.AnalyzeFileResult, error) {
+	if err := s.ensureSchema(); err != nil {
+		return AnalysisRun{}, nil, err
+	}
+
+	run, err := scanRun(s.db.QueryRow(runQueryText()+" WHERE id = ?", runId))
+	if err == sql.ErrNoRows {
+		return AnalysisRun{}, nil, fmt.Errorf("run %d doesn't exist", runId)
+	}
+	if err != nil {
+		return AnalysisRun{}, nil, err
+	}
+
+	results, err := s.QueryAnalyzeFileResults(Query{Filters: []Filter{Equal(ColumnRunId, runId)}})
+	if err != nil {
+		return AnalysisRun{}, nil, err
+	}
+	return run, results, nil
+}
+
+// DeleteRuns deletes the runs matching a retention policy together with the rows of their files. A run is deleted
+// if it isn't one of the policy.KeepLast newest runs of its root path, or if it is older than policy.MaxAge.
+//
+// Arguments:
+//   - policy: The runs to delete.
+//
+// Returns:
+//   - int: The number of deleted runs.
+//   - error: An error if the database can't be written; nothing is deleted then.
+func (s *Store) DeleteRuns(policy RetentionPolicy) (int, error) {
+	runs, err := s.ListRuns("")
+	if err != nil {
+		return 0, err
+	}
+
+	cutoff := time.Now().Add(-policy.MaxAge)
+	kept := make(map[string]int)
+	var expired []int64
+	for _, run := range runs {
+		kept[run.RootPath]++
+		if (policy.KeepLast > 0 && kept[run.RootPath] > policy.KeepLast) || (policy.MaxAge > 0 && run.CreatedAt.Before(cutoff)) {
+			expired = append(expired, run.Id)
+		}
+	}
+	if len(expired) == 0 {
+		return 0, nil
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	statements := []string{
+		fmt.Sprintf("DELETE FROM %v WHERE AnalyzeFileResultId IN (SELECT id FROM %v WHERE RunId = ?)", analyzeFileDetailTableName, analyzeFileResultTableName),
+		fmt.Sprintf("DELETE FROM %v WHERE RunId = ?", analyzeFileResultTableName),
+		fmt.Sprintf("DELETE FROM %v WHERE RunId = ?", fileMetadataTableName),
+		fmt.Sprintf("DELETE FROM %v WHERE id = ?", analysisRunTableName),
+	}
+	for _, runId := range expired {
+		for _, statement := range statements {
+			if _, err := tx.Exec(statement, runId); err != nil {
+				return 0, fmt.Errorf("failed to delete run %d: %w", runId, err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return len(expired), nil
+}
+
+func runQueryText() string {
+	return fmt.Sprintf(`SELECT id, RootPath, CreatedAt, GitCommit, Options, ToolVersion,
+		(SELECT COUNT(*) FROM %v WHERE %v.RunId = %v.id)
+		FROM %v`,
+		analyzeFileResultTableName,
+		analyzeFileResultTableName,
+		analysisRunTableName,
+		analysisRunTableName)
+}
+
+// scanRun reads a row selected by runQueryText.
+func scanRun(row interface{ Scan(...any) error }) (AnalysisRun, error) {
+	var run AnalysisRun
+	var createdAt int64
+	err := row.Scan(&run.Id, &run.RootPath, &createdAt, &run.GitCommit, &run.Options, &run.ToolVersion, &run.Files)
+	if err != nil {
+		return AnalysisRun{}, err
+	}
+	run.CreatedAt = time.Unix(0, createdAt)
+	return run, nil
+}
@@ -0,0 +1,79 @@
+package Database
+
+import (
+	"path/filepath"
+	"statfiy/Analyzer"
+	"statfiy/FileManager"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuns(t *testing.T) {
+	store := openTestStore(t)
+
+	files := func(root string, count int) []Analyzer.AnalyzeFileResult {
+		var results []Analyzer.AnalyzeFileResult
+		for i := 0; i < count; i++ {
+			path := filepath.Join(root, string(rune('a'+i))+".go")
+			results = append(results, Analyzer.AnalyzeFileResult{
+				FileMetadata: FileManager.FileMetadata{Name: filepath.Base(path), Path: path, Dir: root, Extension: ".go"},
+				Language:     Analyzer.Go,
+				CodeSize:     int64(i + 1),
+			})
+		}
+		return results
+	}
+
+	now := time.Now()
+	save := func(root string, age time.Duration, count int) AnalysisRun {
+		run, _, err := store.SaveAnalysis(AnalysisRun{RootPath: root, CreatedAt: now.Add(-age)}, files(root, count))
+		require.NoError(t, err)
+		return run
+	}
+	oldest := save("/a", 72*time.Hour, 1)
+	older := save("/a", 48*time.Hour, 2)
+	latest := save("/a", time.Hour, 3)
+	other := save("/b", 96*time.Hour, 1)
+
+	runs, err := store.ListRuns("/a")
+	require.NoError(t, err)
+	require.Len(t, runs, 3)
+	require.Equal(t, []int64{latest.Id, older.Id, oldest.Id}, []int64{runs[0].Id, runs[1].Id, runs[2].Id})
+	require.Equal(t, []int{3, 2, 1}, []int{runs[0].Files, runs[1].Files, runs[2].Files})
+
+	runs, err = store.ListRuns("")
+	require.NoError(t, err)
+	require.Len(t, runs, 4)
+
+	run, results, err := store.LoadRun(older.Id)
+	require.NoError(t, err)
+	require.Equal(t, "/a", run.RootPath)
+	require.Len(t, results, 2)
+	require.Equal(t, int64(2), results[1].CodeSize)
+
+	_, _, err = store.LoadRun(latest.Id + 100)
+	require.Error(t, err)
+
+	deleted, err := store.DeleteRuns(RetentionPolicy{})
+	require.NoError(t, err)
+	require.Zero(t, deleted)
+
+	// The oldest run of /a exceeds the count, the run of /b the age
+	deleted, err = store.DeleteRuns(RetentionPolicy{KeepLast: 2, MaxAge: 80 * time.Hour})
+	require.NoError(t, err)
+	require.Equal(t, 2, deleted)
+
+	runs, err = store.ListRuns("")
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	require.Equal(t, []int64{latest.Id, older.Id}, []int64{runs[0].Id, runs[1].Id})
+	_, _, err = store.LoadRun(other.Id)
+	require.Error(t, err)
+
+	// The rows of the deleted runs are gone
+	metadata, err := store.GetFileMetadataTableRows()
+	require.NoError(t, err)
+	require.Len(t, metadata, 5)
+}
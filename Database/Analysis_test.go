@@ -0,0 +1,83 @@
+package Database
+
+import (
+	"path/filepath"
+	"statfiy/Analyzer"
+	"statfiy/FileManager"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveAndLoadAnalysis(t *testing.T) {
+	store := openTestStore(t)
+
+	root := filepath.FromSlash("/repo/it's_100%")
+	result := func(relativePath string, language Analyzer.Language, codeSize int64) Analyzer.AnalyzeFileResult {
+		path := filepath.Join(root, filepath.FromSlash(relativePath))
+		return Analyzer.AnalyzeFileResult{
+			FileMetadata: FileManager.FileMetadata{
+				Name:       filepath.Base(path),
+				Path:       path,
+				Dir:        filepath.Dir(path),
+				Extension:  filepath.Ext(path),
+				Size:       codeSize + 10,
+				ModifiedAt: time.Date(2024, 3, 1, 8, 30, 0, 0, time.UTC),
+			},
+			Language:   language,
+			CodeSize:   codeSize,
+			TotalSize:  codeSize + 10,
+			CodeLines:  3,
+			Complexity: 4,
+			LineStats:  Analyzer.LineStats{Lines: 5, MaxLineLength: 40},
+			Imports:    []string{"fmt"},
+			IsTest:     true,
+		}
+	}
+	first := []Analyzer.AnalyzeFileResult{result("main.go", Analyzer.Go, 100), result("pkg/util.py", Analyzer.Python, 50)}
+	saved, stats, err := store.SaveAnalysis(AnalysisRun{RootPath: root, GitCommit: "abc123", Options: "defaults", ToolVersion: "1.0.0"}, first)
+	require.NoError(t, err)
+	require.NotZero(t, saved.Id)
+	require.Equal(t, 2, saved.Files)
+	require.Equal(t, 2, stats.Rows)
+	require.Positive(t, stats.Duration)
+
+	// A sibling sharing the root as a name prefix isn't part of the root
+	sibling := filepath.FromSlash("/repo/it's_100%-old")
+	_, _, err = store.SaveAnalysis(AnalysisRun{RootPath: sibling}, []Analyzer.AnalyzeFileResult{result("../it's_100%-old/a.go", Analyzer.Go, 1)})
+	require.NoError(t, err)
+
+	run, loaded, err := store.LoadAnalysis(root)
+	require.NoError(t, err)
+	require.Equal(t, saved.Id, run.Id)
+	require.Equal(t, "abc123", run.GitCommit)
+	require.Equal(t, "defaults", run.Options)
+	require.Equal(t, "1.0.0", run.ToolVersion)
+	require.True(t, saved.CreatedAt.Equal(run.CreatedAt))
+	require.Len(t, loaded, 2)
+	for i := range loaded {
+		require.NotZero(t, loaded[i].Id)
+		require.NotZero(t, loaded[i].FileMetadata.Id)
+		require.True(t, first[i].FileMetadata.ModifiedAt.Equal(loaded[i].FileMetadata.ModifiedAt))
+		loaded[i].Id, loaded[i].FileMetadata.Id = 0, 0
+		loaded[i].FileMetadata.ModifiedAt = first[i].FileMetadata.ModifiedAt
+	}
+	require.Equal(t, first, loaded)
+
+	// Saving a root again adds a run, and the latest one is loaded
+	_, _, err = store.SaveAnalysis(AnalysisRun{RootPath: root}, first[:1])
+	require.NoError(t, err)
+	_, loaded, err = store.LoadAnalysis(root)
+	require.NoError(t, err)
+	require.Len(t, loaded, 1)
+
+	_, loaded, err = store.LoadAnalysis(sibling)
+	require.NoError(t, err)
+	require.Len(t, loaded, 1)
+
+	run, loaded, err = store.LoadAnalysis(filepath.FromSlash("/elsewhere"))
+	require.NoError(t, err)
+	require.Zero(t, run.Id)
+	require.Empty(t, loaded)
+}
@@ -3,33 +3,59 @@ package Database
 import (
 	"database/sql"
 	"fmt"
+	"statfiy/Analyzer"
 )
 
-func InsertRowToAnalyzeFileResultTable(fileMetadataId int, language int, codeSize, commentSize, blankLines, total int) error {
-	db, err := sql.Open(driverName, DatabasePath)
-	if err != nil {
+func (s *Store) InsertRowToAnalyzeFileResultTable(fileMetadataId int, language int, codeSize, commentSize, blankLines, total int) error {
+	if err := s.ensureSchema(); err != nil {
 		return err
 	}
-	defer db.Close()
 
-	execText := fmt.Sprintf("INSERT INTO %v VALUES (null, %v, '%v', %v, %v, %v, %v);",
-		analyzeFileResultTableName,
-		fileMetadataId,
-		language,
-		codeSize,
-		commentSize,
-		blankLines,
-		total)
+	execText := fmt.Sprintf("INSERT INTO %v (FileMetadataId, Language, CodeSize, CommentSize, BlankLines, TotalSize) VALUES (?, ?, ?, ?, ?, ?);", analyzeFileResultTableName)
 
-	_, err = db.Exec("PRAGMA foreign_keys = ON;")
+	_, err := s.db.Exec(execText, fileMetadataId, language, codeSize, commentSize, blankLines, total)
 	if err != nil {
 		return err
 	}
 
-	_, err = db.Exec(execText)
+	return nil
+}
+
+// InsertAnalyzeFileResultRows inserts many results into TblAnalyzeFileResult, and their complete results into
+// TblAnalyzeFileDetail, within one transaction using prepared statements. The metadata of every result has to
+// be stored already, e.g. with InsertFileMetadataRows, and its id set in FileMetadata.Id. The rows aren't
+// linked to a run; SaveAnalysis stores metadata, results and a run together.
+//
+// Arguments:
+//   - results: The results to insert; their Id is ignored.
+//
+// Returns:
+//   - []int: The generated result ids, in the order of results.
+//   - InsertStats: The number of inserted results and the time it took.
+//   - error: An error if a result has no stored metadata or can't be inserted; nothing is stored then.
+func (s *Store) InsertAnalyzeFileResultRows(results []Analyzer.AnalyzeFileResult) ([]int, InsertStats, error) {
+	ids := make([]int, 0, len(results))
+	stats, err := s.insertBatch(len(results), func(tx *sql.Tx) error {
+		inserter, err := newBatchInserter(tx, 0)
+		if err != nil {
+			return err
+		}
+		defer inserter.close()
+
+		for _, result := range results {
+			if result.FileMetadata.Id == 0 {
+				return fmt.Errorf("the metadata of %s isn't stored", result.FileMetadata.Path)
+			}
+			id, err := inserter.insertResult(result)
+			if err != nil {
+				return err
+			}
+			ids = append(ids, id)
+		}
+		return nil
+	})
 	if err != nil {
-		return err
+		return nil, InsertStats{}, err
 	}
-
-	return nil
+	return ids, stats, nil
 }
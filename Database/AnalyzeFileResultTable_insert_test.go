@@ -2,28 +2,26 @@ package Database
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
 func TestInsertRowToAnalyzeFileResultTable(t *testing.T) {
-	TestCreateFileMetadataTable(t)
-	TestCreateAnalyzeFileResultTable(t)
-	TestInsertRowToFileMetadataTable(t)
+	store := openTestStore(t)
+	require.NoError(t, store.InsertRowToFileMetadataTable("main", "./statify", "/home/rezishon", ".go", 43, time.Now()))
 
-	array, err := GetFileMetadataTableRows()
+	array, err := store.GetFileMetadataTableRows()
 	assert.NoError(t, err)
 
 	t.Run("Test1", func(t *testing.T) {
-		err = InsertRowToAnalyzeFileResultTable(array[0].Id, 0, 10, 10, 10, 1)
+		err = store.InsertRowToAnalyzeFileResultTable(array[0].Id, 0, 10, 10, 10, 1)
 		require.NoError(t, err, "Something messed up")
 	})
 
 	t.Run("Test2", func(t *testing.T) {
-		err = InsertRowToAnalyzeFileResultTable(array[len(array)-1].Id+1, 0, 10, 10, 10, 1)
+		err = store.InsertRowToAnalyzeFileResultTable(array[len(array)-1].Id+1, 0, 10, 10, 10, 1)
 		require.Error(t, err, "Something messed up")
 	})
-
-	//TODO: delete added row
 }
@@ -2,40 +2,73 @@ package Database
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"statfiy/Analyzer"
 )
 
-func GetAnalyzeFileResultTableRows() ([]Analyzer.AnalyzeFileResult, error) {
-	var results []Analyzer.AnalyzeFileResult
+func (s *Store) GetAnalyzeFileResultTableRows() ([]Analyzer.AnalyzeFileResult, error) {
+	return s.QueryAnalyzeFileResults(Query{})
+}
 
-	db, err := sql.Open(driverName, DatabasePath)
+// GetAnalyzeFileResultTableRow returns the first result matching all filters, e.g. Equal(ColumnLanguage, int(Analyzer.Go)).
+//
+// Arguments:
+//   - filters: The conditions on the TblFileMetadata and TblAnalyzeFileResult columns.
+//
+// Returns:
+//   - Analyzer.AnalyzeFileResult: The first matching result by id.
+//   - error: An error if no result matches or the database can't be read.
+func (s *Store) GetAnalyzeFileResultTableRow(filters ...Filter) (Analyzer.AnalyzeFileResult, error) {
+	results, err := s.QueryAnalyzeFileResults(Query{Filters: filters, Limit: 1})
 	if err != nil {
+		return Analyzer.AnalyzeFileResult{}, err
+	}
+	if len(results) == 0 {
+		return Analyzer.AnalyzeFileResult{}, fmt.Errorf("no result matches the filters: %w", sql.ErrNoRows)
+	}
+	return results[0], nil
+}
+
+// QueryAnalyzeFileResults returns the TblAnalyzeFileResult rows joined with their TblFileMetadata rows selected
+// by a query. All columns can be used. Results stored with details, see SaveAnalysis, are complete; other rows
+// only have the sizes set.
+//
+// Arguments:
+//   - query: The filters, orders and page of the rows.
+//
+// Returns:
+//   - []Analyzer.AnalyzeFileResult: The selected results.
+//   - error: An error if the query is invalid or the database can't be read.
+func (s *Store) QueryAnalyzeFileResults(query Query) ([]Analyzer.AnalyzeFileResult, error) {
+	if err := s.ensureSchema(); err != nil {
 		return nil, err
 	}
-	defer db.Close()
 
-	rows, err := db.Query(fmt.Sprintf(`
-		SELECT %v.*,
-		%v.id, %v.Language, %v.CodeSize, %v.CommentSize, %v.BlankLines, %v.TotalSize
-		FROM %v JOIN %v ON %v.FileMetadataId = %v.id`,
-		fileMetadataTableName,
-		analyzeFileResultTableName,
-		analyzeFileResultTableName,
-		analyzeFileResultTableName,
-		analyzeFileResultTableName,
-		analyzeFileResultTableName,
-		analyzeFileResultTableName,
+	clauses, parameters, err := query.build(func(string) bool { return true }, ColumnResultId)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(fmt.Sprintf(`
+		SELECT %[1]v.id, %[1]v.Name, %[1]v.Path, %[1]v.Dir, %[1]v.Extension, %[1]v.Size, CAST(%[1]v.ModifiedAt AS INTEGER),
+		%[2]v.id, %[2]v.Language, %[2]v.CodeSize, %[2]v.CommentSize, %[2]v.BlankLines, %[2]v.TotalSize,
+		%[3]v.Result
+		FROM %[1]v JOIN %[2]v ON %[2]v.FileMetadataId = %[1]v.id
+		LEFT JOIN %[3]v ON %[3]v.AnalyzeFileResultId = %[2]v.id`,
 		fileMetadataTableName,
 		analyzeFileResultTableName,
-		analyzeFileResultTableName,
-		fileMetadataTableName))
+		analyzeFileDetailTableName)+clauses,
+		parameters...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
+
+	var results []Analyzer.AnalyzeFileResult
 	for rows.Next() {
-		row := Analyzer.AnalyzeFileResult{}
+		var row Analyzer.AnalyzeFileResult
+		var detail sql.NullString
 		err := rows.Scan(
 			&row.FileMetadata.Id,
 			&row.FileMetadata.Name,
@@ -43,73 +76,29 @@ func GetAnalyzeFileResultTableRows() ([]Analyzer.AnalyzeFileResult, error) {
 			&row.FileMetadata.Dir,
 			&row.FileMetadata.Extension,
 			&row.FileMetadata.Size,
-			&row.FileMetadata.ModifiedAt,
+			unixNanoTime{&row.FileMetadata.ModifiedAt},
 			&row.Id,
 			&row.Language,
 			&row.CodeSize,
 			&row.CommentSize,
 			&row.BlankLines,
-			&row.TotalSize)
+			&row.TotalSize,
+			&detail)
 		if err != nil {
 			return nil, err
 		}
-		results = append(results, row)
-	}
 
-	return results, nil
-}
-
-func GetAnalyzeFileResultTableRow(attributeName string, attributeValue string) (Analyzer.AnalyzeFileResult, error) {
-	var result Analyzer.AnalyzeFileResult
-
-	db, err := sql.Open(driverName, DatabasePath)
-	if err != nil {
-		return Analyzer.AnalyzeFileResult{}, err
-	}
-	defer db.Close()
-
-	row := db.QueryRow(fmt.Sprintf(`
-	SELECT %v.*,
-	%v.id, %v.Language, %v.CodeSize, %v.CommentSize, %v.BlankLines, %v.TotalSize
-	FROM %v JOIN %v ON %v.FileMetadataId = %v.id
-	WHERE %v.%v = '%v'`,
-		fileMetadataTableName,
-		analyzeFileResultTableName,
-		analyzeFileResultTableName,
-		analyzeFileResultTableName,
-		analyzeFileResultTableName,
-		analyzeFileResultTableName,
-		analyzeFileResultTableName,
-		fileMetadataTableName,
-		analyzeFileResultTableName,
-		analyzeFileResultTableName,
-		fileMetadataTableName,
-		analyzeFileResultTableName,
-		attributeName,
-		attributeValue))
-
-	err = row.Scan(
-		&result.FileMetadata.Id,
-		&result.FileMetadata.Name,
-		&result.FileMetadata.Path,
-		&result.FileMetadata.Dir,
-		&result.FileMetadata.Extension,
-		&result.FileMetadata.Size,
-		&result.FileMetadata.ModifiedAt,
-		&result.Id,
-		&result.Language,
-		&result.CodeSize,
-		&result.CommentSize,
-		&result.BlankLines,
-		&result.TotalSize)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			err = fmt.Errorf("%v wasn't valid", attributeName)
-			return Analyzer.AnalyzeFileResult{}, err
-		} else {
-			return Analyzer.AnalyzeFileResult{}, err
+		if detail.Valid {
+			var result Analyzer.AnalyzeFileResult
+			if err := json.Unmarshal([]byte(detail.String), &result); err != nil {
+				return nil, fmt.Errorf("failed to decode result of %s: %w", row.FileMetadata.Path, err)
+			}
+			result.Id = row.Id
+			result.FileMetadata = row.FileMetadata
+			row = result
 		}
+		results = append(results, row)
 	}
 
-	return result, nil
+	return results, rows.Err()
 }
@@ -1,36 +1,43 @@
 package Database
 
 import (
-	"fmt"
 	"statfiy/Analyzer"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+// insertTestResult inserts a file and its result with the legacy insert functions.
+func insertTestResult(t *testing.T, store *Store) {
+	require.NoError(t, store.InsertRowToFileMetadataTable("main", "./statify", "/home/rezishon", ".go", 43, time.Now()))
+	array, err := store.GetFileMetadataTableRows()
+	require.NoError(t, err)
+	require.NoError(t, store.InsertRowToAnalyzeFileResultTable(array[0].Id, 0, 10, 10, 10, 1))
+}
+
 func TestGetAnalyzeFileResultTableRows(t *testing.T) {
-	TestInsertRowToAnalyzeFileResultTable(t)
+	store := openTestStore(t)
+	insertTestResult(t, store)
 
-	res, err := GetAnalyzeFileResultTableRows()
+	res, err := store.GetAnalyzeFileResultTableRows()
 	assert.NoError(t, err)
 
 	assert.NotEqual(t, []Analyzer.AnalyzeFileResult{}, res)
 }
 
 func TestGetAnalyzeFileResultTableRow(t *testing.T) {
-	metadataArray, err := GetAnalyzeFileResultTableRows()
-	assert.NoError(t, err)
+	store := openTestStore(t)
+	insertTestResult(t, store)
 
-	if len(metadataArray) == 0 {
-		TestInsertRowToAnalyzeFileResultTable(t)
-		metadataArray, err = GetAnalyzeFileResultTableRows()
-		assert.NoError(t, err)
-	}
+	metadataArray, err := store.GetAnalyzeFileResultTableRows()
+	assert.NoError(t, err)
 
 	// concurrent
 	t.Run("Test1", func(t *testing.T) {
 		id := metadataArray[len(metadataArray)-1].Id
-		result, err := GetAnalyzeFileResultTableRow("id", fmt.Sprint(id))
+		result, err := store.GetAnalyzeFileResultTableRow(Equal(ColumnResultId, id))
 		assert.NoError(t, err)
 		assert.NotEmpty(t, result)
 	})
@@ -41,14 +48,14 @@ func TestGetAnalyzeFileResultTableRow(t *testing.T) {
 		// Analyzer.GetLanguage(metadataArray[len(metadataArray)-1].FileMetadata)
 		// languageS := language.String()
 		//TODO: add function to return lang index(number)
-		result, err := GetAnalyzeFileResultTableRow("language", fmt.Sprint(0))
+		result, err := store.GetAnalyzeFileResultTableRow(Equal(ColumnLanguage, 0))
 		assert.NoError(t, err)
 		assert.NotEmpty(t, result)
 	})
 
 	t.Run("Test3", func(t *testing.T) {
 		totalSize := metadataArray[len(metadataArray)-1].TotalSize
-		result, err := GetAnalyzeFileResultTableRow("totalSize", fmt.Sprint(totalSize))
+		result, err := store.GetAnalyzeFileResultTableRow(Equal(ColumnTotalSize, totalSize))
 		assert.NoError(t, err)
 		assert.NotEmpty(t, result)
 	})
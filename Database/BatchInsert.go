@@ -0,0 +1,118 @@
+package Database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"statfiy/Analyzer"
+	"statfiy/FileManager"
+	"time"
+)
+
+// InsertStats reports the throughput of a batch insert.
+type InsertStats struct {
+	Rows     int           // Number of inserted files
+	Duration time.Duration // Time from the start of the transaction to its commit
+}
+
+// RowsPerSecond returns the number of files inserted per second.
+func (s InsertStats) RowsPerSecond() float64 {
+	if s.Duration <= 0 {
+		return 0
+	}
+	return float64(s.Rows) / s.Duration.Seconds()
+}
+
+// batchInserter inserts rows of several tables with statements prepared once per transaction.
+type batchInserter struct {
+	tx       *sql.Tx
+	runId    sql.NullInt64
+	metadata *sql.Stmt
+	result   *sql.Stmt
+	detail   *sql.Stmt
+}
+
+// newBatchInserter prepares the insert statements. Rows are linked to runId unless it is 0.
+func newBatchInserter(tx *sql.Tx, runId int64) (*batchInserter, error) {
+	inserter := &batchInserter{tx: tx, runId: sql.NullInt64{Int64: runId, Valid: runId != 0}}
+
+	var err error
+	inserter.metadata, err = tx.Prepare(fmt.Sprintf("INSERT INTO %v (Name, Path, Dir, Extension, Size, ModifiedAt, RunId) VALUES (?, ?, ?, ?, ?, ?, ?)", fileMetadataTableName))
+	if err != nil {
+		return nil, err
+	}
+	inserter.result, err = tx.Prepare(fmt.Sprintf("INSERT INTO %v (FileMetadataId, Language, CodeSize, CommentSize, BlankLines, TotalSize, RunId) VALUES (?, ?, ?, ?, ?, ?, ?)", analyzeFileResultTableName))
+	if err != nil {
+		inserter.close()
+		return nil, err
+	}
+	inserter.detail, err = tx.Prepare(fmt.Sprintf("INSERT INTO %v (AnalyzeFileResultId, Result) VALUES (?, ?)", analyzeFileDetailTableName))
+	if err != nil {
+		inserter.close()
+		return nil, err
+	}
+	return inserter, nil
+}
+
+func (b *batchInserter) close() {
+	for _, statement := range []*sql.Stmt{b.metadata, b.result, b.detail} {
+		if statement != nil {
+			statement.Close()
+		}
+	}
+}
+
+// insertMetadata inserts a TblFileMetadata row and returns its id.
+func (b *batchInserter) insertMetadata(metadata FileManager.FileMetadata) (int, error) {
+	inserted, err := b.metadata.Exec(metadata.Name, metadata.Path, metadata.Dir, metadata.Extension, metadata.Size, metadata.ModifiedAt.UnixNano(), b.runId)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert metadata of %s: %w", metadata.Path, err)
+	}
+	id, err := inserted.LastInsertId()
+	return int(id), err
+}
+
+// insertResult inserts the TblAnalyzeFileResult and TblAnalyzeFileDetail rows of a result whose metadata is
+// stored with the id result.FileMetadata.Id, and returns the id of the result.
+func (b *batchInserter) insertResult(result Analyzer.AnalyzeFileResult) (int, error) {
+	path := result.FileMetadata.Path
+	inserted, err := b.result.Exec(result.FileMetadata.Id, int(result.Language), result.CodeSize, result.CommentSize, result.BlankLines, result.TotalSize, b.runId)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert result of %s: %w", path, err)
+	}
+	resultId, err := inserted.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	detail, err := json.Marshal(result)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode result of %s: %w", path, err)
+	}
+	if _, err := b.detail.Exec(resultId, string(detail)); err != nil {
+		return 0, fmt.Errorf("failed to insert details of %s: %w", path, err)
+	}
+	return int(resultId), nil
+}
+
+// insertBatch runs insert within one transaction and measures it. Nothing is stored if insert fails.
+func (s *Store) insertBatch(rows int, insert func(tx *sql.Tx) error) (InsertStats, error) {
+	if err := s.ensureSchema(); err != nil {
+		return InsertStats{}, err
+	}
+
+	start := time.Now()
+	tx, err := s.db.Begin()
+	if err != nil {
+		return InsertStats{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := insert(tx); err != nil {
+		return InsertStats{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return InsertStats{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return InsertStats{Rows: rows, Duration: time.Since(start)}, nil
+}
@@ -0,0 +1,94 @@
+package Database
+
+import (
+	"fmt"
+	"statfiy/Analyzer"
+	"statfiy/FileManager"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// batchTestFiles returns count files in /repo with distinct names and sizes.
+func batchTestFiles(count int) []FileManager.FileMetadata {
+	files := make([]FileManager.FileMetadata, count)
+	for i := range files {
+		name := fmt.Sprintf("file%d.go", i)
+		files[i] = FileManager.FileMetadata{Name: name, Path: "/repo/" + name, Dir: "/repo", Extension: ".go", Size: int64(i + 1), ModifiedAt: time.Now()}
+	}
+	return files
+}
+
+func TestInsertRows(t *testing.T) {
+	store := openTestStore(t)
+
+	files := batchTestFiles(3)
+	fileIds, stats, err := store.InsertFileMetadataRows(files)
+	require.NoError(t, err)
+	require.Len(t, fileIds, 3)
+	require.Equal(t, 3, stats.Rows)
+	for i, id := range fileIds {
+		metadata, err := store.GetFileMetadataTableRow(Equal(ColumnFileId, id))
+		require.NoError(t, err)
+		require.Equal(t, files[i].Path, metadata.Path)
+	}
+
+	results := make([]Analyzer.AnalyzeFileResult, len(files))
+	for i := range files {
+		files[i].Id = fileIds[i]
+		results[i] = Analyzer.AnalyzeFileResult{FileMetadata: files[i], Language: Analyzer.Go, TotalSize: int64(10 * (i + 1)), CodeLines: i}
+	}
+	resultIds, stats, err := store.InsertAnalyzeFileResultRows(results)
+	require.NoError(t, err)
+	require.Len(t, resultIds, 3)
+	require.Equal(t, 3, stats.Rows)
+
+	loaded, err := store.QueryAnalyzeFileResults(Query{})
+	require.NoError(t, err)
+	require.Len(t, loaded, 3)
+	for i := range loaded {
+		require.Equal(t, resultIds[i], loaded[i].Id)
+		require.Equal(t, i, loaded[i].CodeLines)
+	}
+
+	// A failing row rolls back the whole batch
+	results = append(results, Analyzer.AnalyzeFileResult{FileMetadata: FileManager.FileMetadata{Path: "/repo/missing.go"}})
+	_, _, err = store.InsertAnalyzeFileResultRows(results)
+	require.Error(t, err)
+	results[3].FileMetadata.Id = fileIds[2] + 100
+	_, _, err = store.InsertAnalyzeFileResultRows(results)
+	require.Error(t, err)
+	loaded, err = store.QueryAnalyzeFileResults(Query{})
+	require.NoError(t, err)
+	require.Len(t, loaded, 3)
+
+	ids, stats, err := store.InsertFileMetadataRows(nil)
+	require.NoError(t, err)
+	require.Empty(t, ids)
+	require.Zero(t, stats.Rows)
+}
+
+func TestInsertStats(t *testing.T) {
+	require.Equal(t, 200.0, InsertStats{Rows: 100, Duration: 500 * time.Millisecond}.RowsPerSecond())
+	require.Zero(t, InsertStats{Rows: 100}.RowsPerSecond())
+}
+
+func BenchmarkSaveAnalysis(b *testing.B) {
+	store, err := Open(b.TempDir() + "/bench.db")
+	require.NoError(b, err)
+	defer store.Close()
+
+	files := batchTestFiles(1000)
+	results := make([]Analyzer.AnalyzeFileResult, len(files))
+	for i := range files {
+		results[i] = Analyzer.AnalyzeFileResult{FileMetadata: files[i], Language: Analyzer.Go, TotalSize: files[i].Size}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, stats, err := store.SaveAnalysis(AnalysisRun{RootPath: "/repo"}, results)
+		require.NoError(b, err)
+		b.ReportMetric(stats.RowsPerSecond(), "files/s")
+	}
+}
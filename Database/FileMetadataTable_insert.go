@@ -3,29 +3,55 @@ package Database
 import (
 	"database/sql"
 	"fmt"
+	"statfiy/FileManager"
 	"time"
 )
 
-func InsertRowToFileMetadataTable(name, path, dir, extension string, size int, modifiedAt time.Time) error {
-	db, err := sql.Open(driverName, DatabasePath)
-	if err != nil {
+func (s *Store) InsertRowToFileMetadataTable(name, path, dir, extension string, size int, modifiedAt time.Time) error {
+	if err := s.ensureSchema(); err != nil {
 		return err
 	}
-	defer db.Close()
 
-	execText := fmt.Sprintf("INSERT INTO %v VALUES (null, '%v', '%v', '%v', '%v', %v, '%v');",
-		fileMetadataTableName,
-		name,
-		path,
-		dir,
-		extension,
-		size,
-		modifiedAt.Format(TimeFormat))
+	execText := fmt.Sprintf("INSERT INTO %v (Name, Path, Dir, Extension, Size, ModifiedAt) VALUES (?, ?, ?, ?, ?, ?);", fileMetadataTableName)
 
-	_, err = db.Exec(execText)
+	_, err := s.db.Exec(execText, name, path, dir, extension, size, modifiedAt.UnixNano())
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// InsertFileMetadataRows inserts many files into TblFileMetadata within one transaction, using one prepared
+// statement. The rows aren't linked to a run.
+//
+// Arguments:
+//   - files: The files to insert; their Id is ignored.
+//
+// Returns:
+//   - []int: The generated ids, in the order of files.
+//   - InsertStats: The number of inserted rows and the time it took.
+//   - error: An error if a row can't be inserted; nothing is stored then.
+func (s *Store) InsertFileMetadataRows(files []FileManager.FileMetadata) ([]int, InsertStats, error) {
+	ids := make([]int, 0, len(files))
+	stats, err := s.insertBatch(len(files), func(tx *sql.Tx) error {
+		inserter, err := newBatchInserter(tx, 0)
+		if err != nil {
+			return err
+		}
+		defer inserter.close()
+
+		for _, file := range files {
+			id, err := inserter.insertMetadata(file)
+			if err != nil {
+				return err
+			}
+			ids = append(ids, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, InsertStats{}, err
+	}
+	return ids, stats, nil
+}
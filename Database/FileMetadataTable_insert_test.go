@@ -8,9 +8,16 @@ import (
 )
 
 func TestInsertRowToFileMetadataTable(t *testing.T) {
-	TestCreateFileMetadataTable(t)
+	store := openTestStore(t)
 
 	time := time.Now()
-	err := InsertRowToFileMetadataTable("main", "./statify", "/home/rezishon", ".go", 43, time)
+	err := store.InsertRowToFileMetadataTable("main", "./statify", "/home/rezishon", ".go", 43, time)
 	require.NoError(t, err, "Something messed up")
+
+	// Quotes are stored as they are
+	err = store.InsertRowToFileMetadataTable("it's", "./it's.go", "/home/o'neil", ".go", 1, time)
+	require.NoError(t, err, "Something messed up")
+	result, err := store.GetFileMetadataTableRow(Equal(ColumnPath, "./it's.go"))
+	require.NoError(t, err)
+	require.Equal(t, "/home/o'neil", result.Dir)
 }
@@ -4,55 +4,84 @@ import (
 	"database/sql"
 	"fmt"
 	"statfiy/FileManager"
+	"time"
 )
 
-func GetFileMetadataTableRows() ([]FileManager.FileMetadata, error) {
-	var results []FileManager.FileMetadata
+func (s *Store) GetFileMetadataTableRows() ([]FileManager.FileMetadata, error) {
+	return s.QueryFileMetadata(Query{})
+}
+
+// GetFileMetadataTableRow returns the first row matching all filters, e.g. Equal(ColumnPath, path).
+//
+// Arguments:
+//   - filters: The conditions on the TblFileMetadata columns.
+//
+// Returns:
+//   - FileManager.FileMetadata: The first matching row by id.
+//   - error: An error if no row matches or the database can't be read.
+func (s *Store) GetFileMetadataTableRow(filters ...Filter) (FileManager.FileMetadata, error) {
+	results, err := s.QueryFileMetadata(Query{Filters: filters, Limit: 1})
+	if err != nil {
+		return FileManager.FileMetadata{}, err
+	}
+	if len(results) == 0 {
+		return FileManager.FileMetadata{}, fmt.Errorf("no file metadata matches the filters: %w", sql.ErrNoRows)
+	}
+	return results[0], nil
+}
+
+// QueryFileMetadata returns the TblFileMetadata rows selected by a query. Only the columns of TblFileMetadata
+// (ColumnFileId to ColumnRunId) can be used.
+//
+// Arguments:
+//   - query: The filters, orders and page of the rows.
+//
+// Returns:
+//   - []FileManager.FileMetadata: The selected rows.
+//   - error: An error if the query uses other columns or the database can't be read.
+func (s *Store) QueryFileMetadata(query Query) ([]FileManager.FileMetadata, error) {
+	if err := s.ensureSchema(); err != nil {
+		return nil, err
+	}
 
-	db, err := sql.Open(driverName, DatabasePath)
+	clauses, parameters, err := query.build(func(table string) bool { return table == fileMetadataTableName }, ColumnFileId)
 	if err != nil {
 		return nil, err
 	}
-	defer db.Close()
 
-	rows, err := db.Query(fmt.Sprintf("SELECT * FROM %v", fileMetadataTableName))
+	rows, err := s.db.Query(fmt.Sprintf("SELECT id, Name, Path, Dir, Extension, Size, CAST(ModifiedAt AS INTEGER) FROM %v", fileMetadataTableName)+clauses, parameters...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
+	var results []FileManager.FileMetadata
 	for rows.Next() {
 		row := FileManager.FileMetadata{}
-		err := rows.Scan(&row.Id, &row.Name, &row.Path, &row.Dir, &row.Extension, &row.Size, &row.ModifiedAt)
+		err := rows.Scan(&row.Id, &row.Name, &row.Path, &row.Dir, &row.Extension, &row.Size, unixNanoTime{&row.ModifiedAt})
 		if err != nil {
 			return nil, err
 		}
 		results = append(results, row)
 	}
 
-	return results, nil
+	return results, rows.Err()
 }
 
-func GetFileMetadataTableRow(attributeName string, attributeValue string) (FileManager.FileMetadata, error) {
-	var result FileManager.FileMetadata
-
-	db, err := sql.Open(driverName, DatabasePath)
-	if err != nil {
-		return FileManager.FileMetadata{}, err
-	}
-	defer db.Close()
-
-	row := db.QueryRow(fmt.Sprintf("SELECT * FROM %v WHERE %v.%v = '%v'", fileMetadataTableName, fileMetadataTableName, attributeName, attributeValue))
+// unixNanoTime scans a time stored in Unix nanoseconds into a time.Time; NULL scans as the zero time.
+// ModifiedAt is declared TIMESTAMP, so it is selected with CAST, otherwise the driver converts it first.
+type unixNanoTime struct {
+	time *time.Time
+}
 
-	err = row.Scan(&result.Id, &result.Name, &result.Path, &result.Dir, &result.Extension, &result.Size, &result.ModifiedAt)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			err = fmt.Errorf("%v wasn't valid", attributeName)
-			return FileManager.FileMetadata{}, err
-		} else {
-			return FileManager.FileMetadata{}, err
-		}
+func (t unixNanoTime) Scan(value any) error {
+	switch value := value.(type) {
+	case nil:
+		*t.time = time.Time{}
+	case int64:
+		*t.time = time.Unix(0, value)
+	default:
+		return fmt.Errorf("time in Unix nanoseconds expected, got %T", value)
 	}
-
-	return result, nil
+	return nil
 }
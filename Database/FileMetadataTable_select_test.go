@@ -1,7 +1,6 @@
 package Database
 
 import (
-	"fmt"
 	"testing"
 	"time"
 
@@ -9,45 +8,44 @@ import (
 )
 
 func TestGetFileMetadataTableRows(t *testing.T) {
-	TestInsertRowToFileMetadataTable(t)
+	store := openTestStore(t)
+	modifiedAt := time.Now()
+	assert.NoError(t, store.InsertRowToFileMetadataTable("main", "./statify", "/home/rezishon", ".go", 43, modifiedAt))
 
-	res, err := GetFileMetadataTableRows()
+	res, err := store.GetFileMetadataTableRows()
 	t.Run("Testing", func(t *testing.T) {
 
 		assert.NoError(t, err)
 
-		assert.Equal(t, time.Now().Format(TimeFormat), res[len(res)-1].ModifiedAt.Format(TimeFormat))
+		assert.True(t, modifiedAt.Equal(res[len(res)-1].ModifiedAt))
 
 	})
 }
 
 func TestGetFileMetadataTableRow(t *testing.T) {
-	metadataArray, err := GetFileMetadataTableRows()
-	assert.NoError(t, err)
+	store := openTestStore(t)
+	assert.NoError(t, store.InsertRowToFileMetadataTable("main", "./statify", "/home/rezishon", ".go", 43, time.Now()))
 
-	if len(metadataArray) == 0 {
-		TestInsertRowToFileMetadataTable(t)
-		metadataArray, err = GetFileMetadataTableRows()
-		assert.NoError(t, err)
-	}
+	metadataArray, err := store.GetFileMetadataTableRows()
+	assert.NoError(t, err)
 
 	t.Run("Test1", func(t *testing.T) {
 		id := metadataArray[len(metadataArray)-1].Id
-		result, err := GetFileMetadataTableRow("id", fmt.Sprint(id))
+		result, err := store.GetFileMetadataTableRow(Equal(ColumnFileId, id))
 		assert.NoError(t, err)
 		assert.NotEmpty(t, result)
 	})
 
 	t.Run("Test2", func(t *testing.T) {
 		extension := metadataArray[len(metadataArray)-1].Extension
-		result, err := GetFileMetadataTableRow("extension", extension)
+		result, err := store.GetFileMetadataTableRow(Equal(ColumnExtension, extension))
 		assert.NoError(t, err)
 		assert.NotEmpty(t, result)
 	})
 
 	t.Run("Test3", func(t *testing.T) {
 		size := metadataArray[len(metadataArray)-1].Size
-		result, err := GetFileMetadataTableRow("size", fmt.Sprint(size))
+		result, err := store.GetFileMetadataTableRow(Equal(ColumnSize, size))
 		assert.NoError(t, err)
 		assert.NotEmpty(t, result)
 	})
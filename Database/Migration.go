@@ -0,0 +1,262 @@
+package Database
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// migrationFiles holds the up migrations, named "<version>_<name>.sql". A migration is never changed once
+// released; schema changes are added as a new file with the next version.
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// schemaVersionTableName records one row per applied migration.
+const schemaVersionTableName = "schema_version"
+
+// Migration is a schema change of the database.
+type Migration struct {
+	Version   int
+	Name      string
+	AppliedAt time.Time // Zero if the migration is pending or Legacy
+	// Legacy is set by SchemaStatus for a migration the database got before migrations were recorded. Its
+	// time is unknown until the next migration records it.
+	Legacy    bool
+	statement string
+}
+
+// Applied reports whether the migration was applied to the database.
+func (m Migration) Applied() bool {
+	return !m.AppliedAt.IsZero() || m.Legacy
+}
+
+// Migrate applies the pending migrations in order, each within its own transaction. Stores migrate on first
+// use, so calling Migrate is only needed to upgrade a database without analyzing anything.
+//
+// Returns:
+//   - []Migration: The migrations applied by this call, empty if the database was up to date.
+//   - error: An error if a migration fails; the migrations before it stay applied.
+func (s *Store) Migrate() ([]Migration, error) {
+	s.schemaMutex.Lock()
+	defer s.schemaMutex.Unlock()
+
+	applied, err := s.migrate()
+	if err == nil {
+		s.schemaReady = true
+	}
+	return applied, err
+}
+
+// SchemaStatus lists all migrations known to this version of Statify with the time they were applied,
+// without changing the database.
+//
+// Returns:
+//   - []Migration: The migrations ordered by version.
+//   - error: An error if the database can't be read or was migrated by a newer version of Statify.
+func (s *Store) SchemaStatus() ([]Migration, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+	appliedAt, err := s.appliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+	if err := checkKnownVersions(migrations, appliedAt); err != nil {
+		return nil, err
+	}
+	legacy, err := s.legacyVersions(appliedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range migrations {
+		migrations[i].AppliedAt = appliedAt[migrations[i].Version]
+		migrations[i].Legacy = legacy[migrations[i].Version]
+	}
+	return migrations, nil
+}
+
+// ensureSchema migrates the database on the first call. It is retried by later calls if it fails.
+func (s *Store) ensureSchema() error {
+	s.schemaMutex.Lock()
+	defer s.schemaMutex.Unlock()
+
+	if s.schemaReady {
+		return nil
+	}
+	if _, err := s.migrate(); err != nil {
+		return err
+	}
+	s.schemaReady = true
+	return nil
+}
+
+func (s *Store) migrate() ([]Migration, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.db.Exec(fmt.Sprintf("CREATE TABLE IF NOT EXISTS %v (Version INTEGER NOT NULL PRIMARY KEY, Name TEXT, AppliedAt INTEGER)", schemaVersionTableName)); err != nil {
+		return nil, fmt.Errorf("failed to create the schema version table of %s: %w", s.path, err)
+	}
+	if err := s.adoptLegacySchema(migrations); err != nil {
+		return nil, fmt.Errorf("failed to read the schema of %s: %w", s.path, err)
+	}
+
+	appliedAt, err := s.appliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+	if err := checkKnownVersions(migrations, appliedAt); err != nil {
+		return nil, err
+	}
+
+	var applied []Migration
+	for _, migration := range migrations {
+		if _, found := appliedAt[migration.Version]; found {
+			continue
+		}
+		migration.AppliedAt = time.Now()
+		if err := s.applyMigration(migration); err != nil {
+			return applied, fmt.Errorf("failed to apply migration %d (%s) to %s: %w", migration.Version, migration.Name, s.path, err)
+		}
+		applied = append(applied, migration)
+	}
+	return applied, nil
+}
+
+func (s *Store) applyMigration(migration Migration) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(migration.statement); err != nil {
+		return err
+	}
+	_, err = tx.Exec(fmt.Sprintf("INSERT INTO %v (Version, Name, AppliedAt) VALUES (?, ?, ?)", schemaVersionTableName),
+		migration.Version, migration.Name, migration.AppliedAt.UnixNano())
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// adoptLegacySchema records the migrations already applied to a database created before migrations existed.
+func (s *Store) adoptLegacySchema(migrations []Migration) error {
+	appliedAt, err := s.appliedMigrations()
+	if err != nil {
+		return err
+	}
+	legacy, err := s.legacyVersions(appliedAt)
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range migrations {
+		if !legacy[migration.Version] {
+			continue
+		}
+		_, err := s.db.Exec(fmt.Sprintf("INSERT INTO %v (Version, Name, AppliedAt) VALUES (?, ?, ?)", schemaVersionTableName),
+			migration.Version, migration.Name, time.Now().UnixNano())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// legacyVersions returns the versions a database created before migrations existed already has, which is
+// none if migrations were recorded. Versions 1 to 3 only create missing tables and are simply applied again,
+// but a database with runs already has the RunId columns added by version 4.
+func (s *Store) legacyVersions(appliedAt map[int]time.Time) (map[int]bool, error) {
+	if len(appliedAt) > 0 {
+		return nil, nil
+	}
+
+	var runTables int
+	err := s.db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", analysisRunTableName).Scan(&runTables)
+	if err != nil || runTables == 0 {
+		return nil, err
+	}
+	return map[int]bool{4: true}, nil
+}
+
+// appliedMigrations returns the time each applied migration was applied at by version.
+func (s *Store) appliedMigrations() (map[int]time.Time, error) {
+	appliedAt := make(map[int]time.Time)
+
+	var tables int
+	err := s.db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", schemaVersionTableName).Scan(&tables)
+	if err != nil || tables == 0 {
+		return appliedAt, err
+	}
+
+	rows, err := s.db.Query(fmt.Sprintf("SELECT Version, AppliedAt FROM %v", schemaVersionTableName))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var version int
+		var timestamp int64
+		if err := rows.Scan(&version, &timestamp); err != nil {
+			return nil, err
+		}
+		appliedAt[version] = time.Unix(0, timestamp)
+	}
+	return appliedAt, rows.Err()
+}
+
+// checkKnownVersions fails if the database was migrated by a newer version of Statify, whose tables this
+// version may not read or write correctly.
+func checkKnownVersions(migrations []Migration, appliedAt map[int]time.Time) error {
+	latest := 0
+	if len(migrations) > 0 {
+		latest = migrations[len(migrations)-1].Version
+	}
+	for version := range appliedAt {
+		if version > latest {
+			return fmt.Errorf("the database has schema version %d, but this version of Statify only knows versions up to %d", version, latest)
+		}
+	}
+	return nil
+}
+
+// loadMigrations reads the embedded migrations ordered by version.
+func loadMigrations() ([]Migration, error) {
+	entries, err := migrationFiles.ReadDir("migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []Migration
+	for _, entry := range entries {
+		prefix, name, found := strings.Cut(strings.TrimSuffix(entry.Name(), ".sql"), "_")
+		version, err := strconv.Atoi(prefix)
+		if !found || err != nil || version <= 0 {
+			return nil, fmt.Errorf("invalid migration file name %s", entry.Name())
+		}
+		statement, err := migrationFiles.ReadFile(path.Join("migrations", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, Migration{Version: version, Name: strings.ReplaceAll(name, "_", " "), statement: string(statement)})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].Version == migrations[i-1].Version {
+			return nil, fmt.Errorf("duplicate migration version %d", migrations[i].Version)
+		}
+	}
+	return migrations, nil
+}
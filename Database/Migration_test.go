@@ -0,0 +1,106 @@
+package Database
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrate(t *testing.T) {
+	store := openTestStore(t)
+
+	status, err := store.SchemaStatus()
+	require.NoError(t, err)
+	require.NotEmpty(t, status)
+	for i, migration := range status {
+		require.Equal(t, i+1, migration.Version)
+		require.NotEmpty(t, migration.Name)
+		require.False(t, migration.Applied())
+	}
+
+	applied, err := store.Migrate()
+	require.NoError(t, err)
+	require.Len(t, applied, len(status))
+
+	applied, err = store.Migrate()
+	require.NoError(t, err)
+	require.Empty(t, applied)
+
+	status, err = store.SchemaStatus()
+	require.NoError(t, err)
+	for _, migration := range status {
+		require.True(t, migration.Applied(), migration.Name)
+	}
+
+	// A database migrated by a newer version isn't touched
+	_, err = store.db.Exec("INSERT INTO schema_version VALUES (?, 'from the future', 0)", len(status)+1)
+	require.NoError(t, err)
+	_, err = store.SchemaStatus()
+	require.Error(t, err)
+	_, err = store.Migrate()
+	require.Error(t, err)
+}
+
+func TestMigrateLegacyDatabase(t *testing.T) {
+	legacyTables := []string{
+		"CREATE TABLE TblFileMetadata (id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT, Name TEXT, Path TEXT, Dir TEXT, Extension TEXT, Size int, ModifiedAt TIMESTAMP)",
+		"CREATE TABLE TblAnalyzeFileResult (id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT, FileMetadataId INTEGER, Language INTEGER, CodeSize INTEGER, CommentSize INTEGER, BlankLines INTEGER, TotalSize INTEGER, FOREIGN KEY (FileMetadataId) REFERENCES TblFileMetadata(id))",
+		"INSERT INTO TblFileMetadata VALUES (null, 'a.go', '/a.go', '/', '.go', 1, '2024-01-01 00:00:00')",
+	}
+	runTables := []string{
+		"CREATE TABLE TblAnalysisRun (id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT, RootPath TEXT, CreatedAt INTEGER, GitCommit TEXT, Options TEXT, ToolVersion TEXT)",
+		"ALTER TABLE TblFileMetadata ADD COLUMN RunId INTEGER REFERENCES TblAnalysisRun(id)",
+		"ALTER TABLE TblAnalyzeFileResult ADD COLUMN RunId INTEGER REFERENCES TblAnalysisRun(id)",
+	}
+
+	for _, withRuns := range []bool{false, true} {
+		t.Run(fmt.Sprintf("runs=%v", withRuns), func(t *testing.T) {
+			store := openTestStore(t)
+			statements := legacyTables
+			if withRuns {
+				statements = append(append([]string(nil), legacyTables...), runTables...)
+			}
+			for _, statement := range statements {
+				_, err := store.db.Exec(statement)
+				require.NoError(t, err)
+			}
+
+			// The status shows the columns of version 4 as applied, without recording anything
+			status, err := store.SchemaStatus()
+			require.NoError(t, err)
+			for _, migration := range status {
+				require.Equal(t, withRuns && migration.Version == 4, migration.Applied(), migration.Name)
+				require.Equal(t, withRuns && migration.Version == 4, migration.Legacy, migration.Name)
+			}
+			var tables int
+			require.NoError(t, store.db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE name = 'schema_version'").Scan(&tables))
+			require.Zero(t, tables)
+
+			_, err = store.Migrate()
+			require.NoError(t, err)
+
+			status, err = store.SchemaStatus()
+			require.NoError(t, err)
+			for _, migration := range status {
+				require.True(t, migration.Applied(), migration.Name)
+				require.False(t, migration.Legacy, migration.Name)
+			}
+
+			// The stored rows survive and can be queried like new ones
+			files, err := store.GetFileMetadataTableRows()
+			require.NoError(t, err)
+			require.Len(t, files, 1)
+			require.True(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Equal(files[0].ModifiedAt))
+			var runId sql.NullInt64
+			require.NoError(t, store.db.QueryRow("SELECT RunId FROM TblFileMetadata").Scan(&runId))
+			require.False(t, runId.Valid)
+
+			runs, err := store.ListRuns("")
+			require.NoError(t, err)
+			require.Empty(t, runs)
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package Database
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Column is a column queries can filter and order by. Only the columns below are accepted, so column names
+// never reach the SQL text from outside the package.
+type Column string
+
+const (
+	ColumnFileId      Column = "FileId"     // TblFileMetadata.id
+	ColumnName        Column = "Name"       // File name with extension
+	ColumnPath        Column = "Path"       // Absolute file path
+	ColumnDir         Column = "Dir"        // Directory of the file
+	ColumnExtension   Column = "Extension"  // Extension including the dot
+	ColumnSize        Column = "Size"       // File size in bytes
+	ColumnModifiedAt  Column = "ModifiedAt" // Modification time in Unix nanoseconds, time.Time values are converted
+	ColumnRunId       Column = "RunId"      // Run the file was saved by, NULL for rows inserted without a run
+	ColumnResultId    Column = "ResultId"   // TblAnalyzeFileResult.id
+	ColumnLanguage    Column = "Language"   // Analyzer.Language as a number
+	ColumnCodeSize    Column = "CodeSize"
+	ColumnCommentSize Column = "CommentSize"
+	ColumnBlankLines  Column = "BlankLines"
+	ColumnTotalSize   Column = "TotalSize"
+)
+
+// columnSource is the table and SQL name of a Column.
+type columnSource struct {
+	table *string
+	name  string
+}
+
+// columns is the whitelist of Column values. The table names are read through pointers since they are
+// only set in init.
+var columns = map[Column]columnSource{
+	ColumnFileId:      {&fileMetadataTableName, "id"},
+	ColumnName:        {&fileMetadataTableName, "Name"},
+	ColumnPath:        {&fileMetadataTableName, "Path"},
+	ColumnDir:         {&fileMetadataTableName, "Dir"},
+	ColumnExtension:   {&fileMetadataTableName, "Extension"},
+	ColumnSize:        {&fileMetadataTableName, "Size"},
+	ColumnModifiedAt:  {&fileMetadataTableName, "ModifiedAt"},
+	ColumnRunId:       {&fileMetadataTableName, "RunId"},
+	ColumnResultId:    {&analyzeFileResultTableName, "id"},
+	ColumnLanguage:    {&analyzeFileResultTableName, "Language"},
+	ColumnCodeSize:    {&analyzeFileResultTableName, "CodeSize"},
+	ColumnCommentSize: {&analyzeFileResultTableName, "CommentSize"},
+	ColumnBlankLines:  {&analyzeFileResultTableName, "BlankLines"},
+	ColumnTotalSize:   {&analyzeFileResultTableName, "TotalSize"},
+}
+
+// ParseColumn returns the Column with a name, ignoring case, e.g. for column names given on the command line.
+//
+// Arguments:
+//   - name: The name of the column, e.g. "extension" or "TotalSize".
+//
+// Returns:
+//   - Column: The column.
+//   - error: An error if no column has that name.
+func ParseColumn(name string) (Column, error) {
+	for column := range columns {
+		if strings.EqualFold(string(column), name) {
+			return column, nil
+		}
+	}
+	return "", fmt.Errorf("unknown column %q", name)
+}
+
+// Filter is a condition on a column, created with Equal, Less, Between, Like, etc. The values are passed to
+// the database as parameters.
+type Filter struct {
+	column   Column
+	operator string
+	values   []any
+}
+
+// Equal matches rows whose column equals value.
+func Equal(column Column, value any) Filter {
+	return Filter{column, "=", []any{value}}
+}
+
+// NotEqual matches rows whose column differs from value.
+func NotEqual(column Column, value any) Filter {
+	return Filter{column, "!=", []any{value}}
+}
+
+// Less matches rows whose column is less than value.
+func Less(column Column, value any) Filter {
+	return Filter{column, "<", []any{value}}
+}
+
+// LessOrEqual matches rows whose column is less than or equal to value.
+func LessOrEqual(column Column, value any) Filter {
+	return Filter{column, "<=", []any{value}}
+}
+
+// Greater matches rows whose column is greater than value.
+func Greater(column Column, value any) Filter {
+	return Filter{column, ">", []any{value}}
+}
+
+// GreaterOrEqual matches rows whose column is greater than or equal to value.
+func GreaterOrEqual(column Column, value any) Filter {
+	return Filter{column, ">=", []any{value}}
+}
+
+// Between matches rows whose column is in the range from low to high, both included.
+func Between(column Column, low, high any) Filter {
+	return Filter{column, "BETWEEN", []any{low, high}}
+}
+
+// Like matches rows whose column matches an SQL LIKE pattern, in which "%" matches any text and "_" any
+// character. ASCII letters match regardless of case. Use EscapeLike to match text literally.
+func Like(column Column, pattern string) Filter {
+	return Filter{column, "LIKE", []any{pattern}}
+}
+
+// EscapeLike escapes the wildcards of a LIKE pattern, e.g. to match paths starting with a directory
+// with Like(ColumnPath, EscapeLike(dir)+"%").
+func EscapeLike(text string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(text)
+}
+
+// Order sorts the rows of a query by a column.
+type Order struct {
+	Column     Column
+	Descending bool
+}
+
+// Query selects rows by filters, which all have to match, and sorts and pages them.
+type Query struct {
+	Filters []Filter
+	OrderBy []Order // Rows are sorted by id after these orders
+	Limit   int     // Maximum number of rows, 0 for no limit
+	Offset  int     // Number of rows skipped
+}
+
+// build returns the WHERE, ORDER BY, LIMIT and OFFSET clauses of the query and their parameters.
+// allowed reports whether the tables joined by the caller contain a column.
+func (q Query) build(allowed func(table string) bool, idColumn Column) (string, []any, error) {
+	where, parameters, err := buildFilters(q.Filters, allowed)
+	if err != nil {
+		return "", nil, err
+	}
+	var clauses strings.Builder
+	clauses.WriteString(where)
+
+	orders := append(append([]Order(nil), q.OrderBy...), Order{Column: idColumn})
+	for i, order := range orders {
+		column, err := columnText(order.Column, allowed)
+		if err != nil {
+			return "", nil, err
+		}
+		if i == 0 {
+			clauses.WriteString(" ORDER BY ")
+		} else {
+			clauses.WriteString(", ")
+		}
+		clauses.WriteString(column)
+		if order.Descending {
+			clauses.WriteString(" DESC")
+		}
+	}
+
+	if q.Limit < 0 || q.Offset < 0 {
+		return "", nil, fmt.Errorf("limit and offset can't be negative")
+	}
+	if q.Limit > 0 || q.Offset > 0 {
+		// SQLite only accepts OFFSET after LIMIT, and -1 is no limit
+		limit := q.Limit
+		if limit == 0 {
+			limit = -1
+		}
+		clauses.WriteString(" LIMIT ? OFFSET ?")
+		parameters = append(parameters, limit, q.Offset)
+	}
+
+	return clauses.String(), parameters, nil
+}
+
+// buildFilters returns the WHERE clause matching all filters, empty if there are none, and its parameters.
+func buildFilters(filters []Filter, allowed func(table string) bool) (string, []any, error) {
+	var clauses strings.Builder
+	var parameters []any
+
+	for i, filter := range filters {
+		column, err := columnText(filter.column, allowed)
+		if err != nil {
+			return "", nil, err
+		}
+		if i == 0 {
+			clauses.WriteString(" WHERE ")
+		} else {
+			clauses.WriteString(" AND ")
+		}
+
+		switch filter.operator {
+		case "BETWEEN":
+			fmt.Fprintf(&clauses, "%v BETWEEN ? AND ?", column)
+		case "LIKE":
+			fmt.Fprintf(&clauses, `%v LIKE ? ESCAPE '\'`, column)
+		case "=", "!=", "<", "<=", ">", ">=":
+			fmt.Fprintf(&clauses, "%v %v ?", column, filter.operator)
+		default:
+			return "", nil, fmt.Errorf("invalid filter on %v, filters have to be created with Equal, Less, Like, etc", filter.column)
+		}
+		for _, value := range filter.values {
+			parameters = append(parameters, columnValue(filter.column, value))
+		}
+	}
+
+	return clauses.String(), parameters, nil
+}
+
+// columnValue returns a filter value as it is stored in a column. Modification times are stored in Unix
+// nanoseconds, so a time.Time compared with ColumnModifiedAt is converted.
+func columnValue(column Column, value any) any {
+	if modifiedAt, ok := value.(time.Time); ok && column == ColumnModifiedAt {
+		return modifiedAt.UnixNano()
+	}
+	return value
+}
+
+// columnText returns the qualified SQL name of a whitelisted column.
+func columnText(column Column, allowed func(table string) bool) (string, error) {
+	source, found := columns[column]
+	if !found || !allowed(*source.table) {
+		return "", fmt.Errorf("column %q can't be queried here", column)
+	}
+	return fmt.Sprintf("%v.%v", *source.table, source.name), nil
+}
@@ -0,0 +1,111 @@
+package Database
+
+import (
+	"path/filepath"
+	"statfiy/Analyzer"
+	"statfiy/FileManager"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuery(t *testing.T) {
+	store := openTestStore(t)
+
+	modifiedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	var results []Analyzer.AnalyzeFileResult
+	for i, path := range []string{"/repo/it's.go", "/repo/100%.py", "/repo/a_c.go", "/repo/abc.go", "/other/x.go"} {
+		language := Analyzer.Go
+		if filepath.Ext(path) == ".py" {
+			language = Analyzer.Python
+		}
+		results = append(results, Analyzer.AnalyzeFileResult{
+			FileMetadata: FileManager.FileMetadata{
+				Name:       filepath.Base(path),
+				Path:       path,
+				Dir:        filepath.Dir(path),
+				Extension:  filepath.Ext(path),
+				Size:       int64(10 * (i + 1)),
+				ModifiedAt: modifiedAt.Add(time.Duration(i) * time.Hour),
+			},
+			Language:  language,
+			TotalSize: int64(10 * (i + 1)),
+			CodeLines: i,
+		})
+	}
+	_, _, err := store.SaveAnalysis(AnalysisRun{RootPath: "/"}, results)
+	require.NoError(t, err)
+
+	paths := func(query Query) []string {
+		found, err := store.QueryAnalyzeFileResults(query)
+		require.NoError(t, err)
+		var paths []string
+		for _, result := range found {
+			paths = append(paths, result.FileMetadata.Path)
+		}
+		return paths
+	}
+
+	// Values are parameters, so quotes and SQL in them are plain text
+	require.Equal(t, []string{"/repo/it's.go"}, paths(Query{Filters: []Filter{Equal(ColumnPath, "/repo/it's.go")}}))
+	require.Empty(t, paths(Query{Filters: []Filter{Equal(ColumnPath, "x' OR '1'='1")}}))
+	metadata, err := store.GetFileMetadataTableRow(Equal(ColumnName, "it's.go"))
+	require.NoError(t, err)
+	require.Equal(t, "/repo/it's.go", metadata.Path)
+	_, err = store.GetFileMetadataTableRow(Equal(ColumnName, "missing.go"))
+	require.Error(t, err)
+
+	require.Equal(t, []string{"/repo/100%.py", "/repo/a_c.go"}, paths(Query{Filters: []Filter{Between(ColumnTotalSize, 20, 30)}}))
+	require.Equal(t, []string{"/repo/abc.go", "/other/x.go"}, paths(Query{Filters: []Filter{Greater(ColumnSize, 30)}}))
+	require.Equal(t, []string{"/repo/it's.go", "/repo/100%.py"}, paths(Query{Filters: []Filter{LessOrEqual(ColumnSize, 20)}}))
+	require.Equal(t, []string{"/repo/100%.py"}, paths(Query{Filters: []Filter{Equal(ColumnLanguage, int(Analyzer.Python))}}))
+	require.Equal(t, []string{"/repo/it's.go", "/repo/a_c.go", "/repo/abc.go"},
+		paths(Query{Filters: []Filter{Like(ColumnPath, EscapeLike("/repo/")+"%"), NotEqual(ColumnExtension, ".py")}}))
+
+	// "_" matches any character unless escaped
+	require.Equal(t, []string{"/repo/a_c.go", "/repo/abc.go"}, paths(Query{Filters: []Filter{Like(ColumnName, "a_c%")}}))
+	require.Equal(t, []string{"/repo/a_c.go"}, paths(Query{Filters: []Filter{Like(ColumnName, EscapeLike("a_c")+"%")}}))
+	require.Equal(t, []string{"/repo/100%.py"}, paths(Query{Filters: []Filter{Like(ColumnName, "%"+EscapeLike("%")+"%")}}))
+
+	ordered := Query{OrderBy: []Order{{Column: ColumnDir}, {Column: ColumnSize, Descending: true}}, Limit: 2, Offset: 1}
+	require.Equal(t, []string{"/repo/abc.go", "/repo/a_c.go"}, paths(ordered))
+	require.Equal(t, []string{"/repo/abc.go", "/repo/a_c.go", "/repo/100%.py", "/repo/it's.go"}, paths(Query{OrderBy: ordered.OrderBy, Offset: 1}))
+
+	files, err := store.QueryFileMetadata(Query{Filters: []Filter{Less(ColumnSize, 30)}, OrderBy: []Order{{Column: ColumnName}}})
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	require.Equal(t, "100%.py", files[0].Name)
+	require.True(t, modifiedAt.Add(time.Hour).Equal(files[0].ModifiedAt))
+
+	// Modification times are compared as instants, whatever time zone the filter value is in, and rows
+	// inserted one by one are stored like saved analyses
+	require.NoError(t, store.InsertRowToFileMetadataTable("single.go", "/single.go", "/", ".go", 1, modifiedAt.Add(5*time.Hour)))
+	eastern := time.FixedZone("UTC+3", 3*60*60)
+	files, err = store.QueryFileMetadata(Query{Filters: []Filter{GreaterOrEqual(ColumnModifiedAt, modifiedAt.Add(4*time.Hour).In(eastern))}})
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	require.Equal(t, "x.go", files[0].Name)
+	require.Equal(t, "single.go", files[1].Name)
+	require.True(t, modifiedAt.Add(5*time.Hour).Equal(files[1].ModifiedAt))
+	require.Equal(t, []string{"/repo/100%.py", "/repo/a_c.go"},
+		paths(Query{Filters: []Filter{Between(ColumnModifiedAt, modifiedAt.Add(time.Hour), modifiedAt.Add(2*time.Hour))}}))
+
+	// Columns outside the whitelist or the queried tables are rejected
+	_, err = store.QueryFileMetadata(Query{Filters: []Filter{Equal(ColumnTotalSize, 10)}})
+	require.Error(t, err)
+	_, err = store.QueryAnalyzeFileResults(Query{Filters: []Filter{Equal(Column("1=1 OR Path"), 1)}})
+	require.Error(t, err)
+	_, err = store.QueryAnalyzeFileResults(Query{OrderBy: []Order{{Column: Column("Path; DROP TABLE TblFileMetadata")}}})
+	require.Error(t, err)
+	_, err = store.QueryAnalyzeFileResults(Query{Filters: []Filter{{}}})
+	require.Error(t, err)
+	_, err = store.QueryAnalyzeFileResults(Query{Limit: -1})
+	require.Error(t, err)
+
+	column, err := ParseColumn("totalsize")
+	require.NoError(t, err)
+	require.Equal(t, ColumnTotalSize, column)
+	_, err = ParseColumn("Result")
+	require.Error(t, err)
+}
@@ -0,0 +1,83 @@
+package Database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Store is an open database. All its methods share one connection pool and may be called concurrently.
+// The database is migrated to the current schema when the store is first used, not by Open.
+type Store struct {
+	db   *sql.DB
+	path string
+
+	schemaMutex sync.Mutex
+	schemaReady bool
+}
+
+// Open opens the SQLite database at a path, creating the file if it doesn't exist. The database is switched to
+// write-ahead logging, so reports can be read while another run writes, and foreign keys are enforced.
+//
+// Arguments:
+//   - path: The path of the database file.
+//
+// Returns:
+//   - *Store: The store, to be closed with Close.
+//   - error: An error if the path is invalid or the database can't be opened.
+func Open(path string) (*Store, error) {
+	// Everything after a "?" would be taken for connection options
+	if path == "" || strings.Contains(path, "?") {
+		return nil, fmt.Errorf("invalid database path %q", path)
+	}
+
+	db, err := sql.Open(driverName, path+"?_journal_mode=WAL&_foreign_keys=on&_busy_timeout=5000")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database %s: %w", path, err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open database %s: %w", path, err)
+	}
+
+	return &Store{db: db, path: path}, nil
+}
+
+// OpenReadOnly opens an existing SQLite database at a path without changing it, e.g. to show its schema status.
+// Unlike Open it doesn't create a missing file, and the store can't be migrated or written to.
+//
+// Arguments:
+//   - path: The path of the database file.
+//
+// Returns:
+//   - *Store: The store, to be closed with Close.
+//   - error: An error if the path is invalid or the database doesn't exist or can't be opened.
+func OpenReadOnly(path string) (*Store, error) {
+	if path == "" || strings.Contains(path, "?") {
+		return nil, fmt.Errorf("invalid database path %q", path)
+	}
+
+	// Read-only mode is only accepted in a URI, where "%" and "#" have to be escaped
+	uri := "file:" + strings.NewReplacer("%", "%25", "#", "%23").Replace(path)
+	db, err := sql.Open(driverName, uri+"?mode=ro&_busy_timeout=5000")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database %s: %w", path, err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open database %s: %w", path, err)
+	}
+
+	return &Store{db: db, path: path}, nil
+}
+
+// Path returns the path the store was opened with.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Close closes all connections of the store.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,68 @@
+package Database
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// openTestStore opens a store in a temporary directory, closed when the test ends.
+func openTestStore(t *testing.T) *Store {
+	store, err := Open(filepath.Join(t.TempDir(), "statify.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestOpen(t *testing.T) {
+	store := openTestStore(t)
+
+	var journalMode string
+	require.NoError(t, store.db.QueryRow("PRAGMA journal_mode").Scan(&journalMode))
+	require.Equal(t, "wal", journalMode)
+
+	// The tables are created on first use
+	var tables int
+	require.NoError(t, store.db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name LIKE 'Tbl%'").Scan(&tables))
+	require.Zero(t, tables)
+
+	runs, err := store.ListRuns("")
+	require.NoError(t, err)
+	require.Empty(t, runs)
+	require.NoError(t, store.db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name LIKE 'Tbl%'").Scan(&tables))
+	require.Equal(t, 5, tables)
+
+	_, err = Open(filepath.Join(t.TempDir(), "missing", "statify.db"))
+	require.Error(t, err)
+	_, err = Open("statify.db?mode=ro")
+	require.Error(t, err)
+	_, err = Open("")
+	require.Error(t, err)
+}
+
+func TestOpenReadOnly(t *testing.T) {
+	// A missing database isn't created
+	missing := filepath.Join(t.TempDir(), "statify.db")
+	_, err := OpenReadOnly(missing)
+	require.Error(t, err)
+	require.NoFileExists(t, missing)
+
+	path := filepath.Join(t.TempDir(), "100% #1.db")
+	store, err := Open(path)
+	require.NoError(t, err)
+	_, err = store.Migrate()
+	require.NoError(t, err)
+	require.NoError(t, store.Close())
+
+	store, err = OpenReadOnly(path)
+	require.NoError(t, err)
+	defer store.Close()
+	status, err := store.SchemaStatus()
+	require.NoError(t, err)
+	for _, migration := range status {
+		require.True(t, migration.Applied(), migration.Name)
+	}
+	_, err = store.db.Exec("DELETE FROM schema_version")
+	require.Error(t, err)
+}
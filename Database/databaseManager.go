@@ -4,29 +4,23 @@ import (
 	_ "github.com/mattn/go-sqlite3"
 )
 
+// The table names have to match the tables created by the migrations.
 var (
 	driverName                 string
-	DatabasePath               string
 	analyzeFileResultTableName string
 	fileMetadataTableName      string
-	TimeFormat                 string
+	analysisCacheTableName     string
+	analyzeFileDetailTableName string
+	analysisRunTableName       string
 )
 
-type primaryKeyAttribute struct {
-	AttributeName string
-	Type          string
-}
-
 func init() {
 	driverName = "sqlite3"
-	DatabasePath = "./StatifyDatabase.db"
 	fileMetadataTableName = "TblFileMetadata"
 	analyzeFileResultTableName = "TblAnalyzeFileResult"
-	TimeFormat = "2006-01-02 15:04:05"
-
-	createFileMetadataTable()
-	createAnalyzeFileResultTable()
+	analysisCacheTableName = "TblAnalysisCache"
+	analyzeFileDetailTableName = "TblAnalyzeFileDetail"
+	analysisRunTableName = "TblAnalysisRun"
 }
 
 // TODO seperate functions in files
-// TODO add lazyLoad
@@ -0,0 +1,19 @@
+-- The file metadata and size tables. IF NOT EXISTS adopts databases created before migrations existed.
+CREATE TABLE IF NOT EXISTS TblFileMetadata (
+	id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+	Name TEXT,
+	Path TEXT,
+	Dir TEXT,
+	Extension TEXT,
+	Size int,
+	ModifiedAt TIMESTAMP);
+
+CREATE TABLE IF NOT EXISTS TblAnalyzeFileResult (
+	id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+	FileMetadataId INTEGER,
+	Language INTEGER,
+	CodeSize INTEGER,
+	CommentSize INTEGER,
+	BlankLines INTEGER,
+	TotalSize INTEGER,
+	FOREIGN KEY (FileMetadataId) REFERENCES TblFileMetadata(id));
@@ -0,0 +1,9 @@
+-- Results of analyzed files by path, reused while the size, modification time or content hash match.
+-- ModifiedAt is in Unix nanoseconds.
+CREATE TABLE IF NOT EXISTS TblAnalysisCache (
+	Path TEXT NOT NULL PRIMARY KEY,
+	Size INTEGER,
+	ModifiedAt INTEGER,
+	ContentHash TEXT,
+	Settings TEXT,
+	Result TEXT);
@@ -0,0 +1,6 @@
+-- The complete result of a TblAnalyzeFileResult row as JSON, including the line stats and metrics
+-- the result table has no columns for.
+CREATE TABLE IF NOT EXISTS TblAnalyzeFileDetail (
+	AnalyzeFileResultId INTEGER NOT NULL PRIMARY KEY,
+	Result TEXT,
+	FOREIGN KEY (AnalyzeFileResultId) REFERENCES TblAnalyzeFileResult(id));
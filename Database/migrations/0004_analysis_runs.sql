@@ -0,0 +1,12 @@
+-- One row per saved analysis. CreatedAt is in Unix nanoseconds so runs can be ordered and compared
+-- without depending on the time zone they were saved in. Rows stored before runs existed keep a NULL RunId.
+CREATE TABLE TblAnalysisRun (
+	id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+	RootPath TEXT,
+	CreatedAt INTEGER,
+	GitCommit TEXT,
+	Options TEXT,
+	ToolVersion TEXT);
+
+ALTER TABLE TblFileMetadata ADD COLUMN RunId INTEGER REFERENCES TblAnalysisRun(id);
+ALTER TABLE TblAnalyzeFileResult ADD COLUMN RunId INTEGER REFERENCES TblAnalysisRun(id);
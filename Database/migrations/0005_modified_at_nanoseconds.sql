@@ -0,0 +1,5 @@
+-- TblFileMetadata.ModifiedAt is stored in Unix nanoseconds, like the times of the cache and the runs, so
+-- filters compare it as a number. Times stored as text before are converted with second precision; text
+-- without a time zone is read as UTC.
+UPDATE TblFileMetadata SET ModifiedAt = CAST(strftime('%s', ModifiedAt) AS INTEGER) * 1000000000
+WHERE typeof(ModifiedAt) = 'text';
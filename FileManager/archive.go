@@ -0,0 +1,199 @@
+package FileManager
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// StdinArchive is the path that reads a tar stream, optionally gzip compressed, from standard input.
+const StdinArchive = "-"
+
+// ArchiveLimits protects against archives that expand to more than can be analyzed, e.g. zip bombs.
+type ArchiveLimits struct {
+	MaxFiles     int   // Maximum number of files
+	MaxFileSize  int64 // Maximum uncompressed size of a single file in bytes
+	MaxTotalSize int64 // Maximum uncompressed size of all files in bytes
+}
+
+// DefaultArchiveLimits are the limits used when reading archives given as root paths.
+var DefaultArchiveLimits = ArchiveLimits{
+	MaxFiles:     100_000,
+	MaxFileSize:  64 << 20,
+	MaxTotalSize: 1 << 30,
+}
+
+// ErrArchiveLimit is returned when an archive exceeds its ArchiveLimits.
+var ErrArchiveLimit = errors.New("archive exceeds the size limits")
+
+// IsArchivePath reports whether a path is read as an archive: a .zip, .tar, .tar.gz or .tgz file, or StdinArchive.
+func IsArchivePath(filePath string) bool {
+	lower := strings.ToLower(filePath)
+	return filePath == StdinArchive ||
+		strings.HasSuffix(lower, ".zip") ||
+		strings.HasSuffix(lower, ".tar") ||
+		strings.HasSuffix(lower, ".tar.gz") ||
+		strings.HasSuffix(lower, ".tgz")
+}
+
+// OpenArchive reads the files of an archive into memory as a read-only fs.FS. The tree is mounted at
+// mountPath, so the files can be collected and read with native paths below it as if the archive was unpacked there.
+// Directories, symbolic links and other special entries are skipped.
+//
+// Arguments:
+//   - archivePath: The path of a .zip, .tar, .tar.gz or .tgz file, or StdinArchive for a tar stream on standard input.
+//   - mountPath: The absolute path the archive root appears at, usually the absolute archive path.
+//   - limits: The limits the uncompressed content must stay within.
+//
+// Returns:
+//   - fs.FS: The files of the archive.
+//   - error: An error if the archive can't be read, exceeds the limits or has an entry outside the archive root.
+func OpenArchive(archivePath, mountPath string, limits ArchiveLimits) (fs.FS, error) {
+	tree := newTreeFS(mountPath, time.Now())
+	reader := &archiveReader{tree: tree, limits: limits}
+
+	if archivePath == StdinArchive {
+		if err := reader.readTar(os.Stdin); err != nil {
+			return nil, fmt.Errorf("failed to read archive from standard input: %w", err)
+		}
+		return tree, nil
+	}
+
+	var err error
+	if strings.HasSuffix(strings.ToLower(archivePath), ".zip") {
+		err = reader.readZip(archivePath)
+	} else {
+		var file *os.File
+		if file, err = os.Open(archivePath); err == nil {
+			defer file.Close()
+			err = reader.readTar(file)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive %s: %w", archivePath, err)
+	}
+	return tree, nil
+}
+
+// archiveReader adds archive entries to a treeFS while enforcing the limits.
+type archiveReader struct {
+	tree      *treeFS
+	limits    ArchiveLimits
+	files     int
+	totalSize int64
+}
+
+func (a *archiveReader) readZip(archivePath string) error {
+	archive, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+
+	for _, entry := range archive.File {
+		if !entry.Mode().IsRegular() {
+			continue
+		}
+		// The sizes in the zip headers can't be trusted, add enforces the limits on the decompressed data
+		content, err := entry.Open()
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", entry.Name, err)
+		}
+		err = a.add(entry.Name, entry.Modified, content)
+		content.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readTar reads a tar stream, which is gunzipped first if it starts with the gzip magic number.
+func (a *archiveReader) readTar(stream io.Reader) error {
+	buffered := bufio.NewReader(stream)
+	if magic, err := buffered.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gzipReader, err := gzip.NewReader(buffered)
+		if err != nil {
+			return err
+		}
+		defer gzipReader.Close()
+		stream = gzipReader
+	} else {
+		stream = buffered
+	}
+
+	archive := tar.NewReader(stream)
+	for {
+		header, err := archive.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := a.add(header.Name, header.ModTime, archive); err != nil {
+			return err
+		}
+	}
+}
+
+// add validates the name of an entry, reads its content within the limits and adds it to the tree.
+func (a *archiveReader) add(name string, modTime time.Time, content io.Reader) error {
+	cleanName, err := cleanArchiveName(name)
+	if err != nil {
+		return err
+	}
+
+	a.files++
+	if a.files > a.limits.MaxFiles {
+		return fmt.Errorf("%w: more than %d files", ErrArchiveLimit, a.limits.MaxFiles)
+	}
+
+	maxSize := min(a.limits.MaxFileSize, a.limits.MaxTotalSize-a.totalSize)
+	data, err := io.ReadAll(io.LimitReader(content, maxSize+1))
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	if int64(len(data)) > maxSize {
+		return fmt.Errorf("%w: %s expands to more than %d bytes", ErrArchiveLimit, name, maxSize)
+	}
+	a.totalSize += int64(len(data))
+
+	a.tree.addFile(cleanName, int64(len(data)), modTime, func() ([]byte, error) {
+		return data, nil
+	})
+	return nil
+}
+
+// cleanArchiveName turns an entry name into a path relative to the archive root. Names that are absolute
+// or climb out of the archive root with ".." are rejected, so a crafted archive can't make files appear
+// outside the directory it stands in for.
+func cleanArchiveName(name string) (string, error) {
+	slashed := strings.ReplaceAll(name, "\\", "/")
+	if strings.HasPrefix(slashed, "/") || (len(slashed) > 1 && slashed[1] == ':') {
+		return "", fmt.Errorf("archive entry %q has an absolute path", name)
+	}
+	for _, segment := range strings.Split(slashed, "/") {
+		if segment == ".." {
+			return "", fmt.Errorf("archive entry %q points outside the archive", name)
+		}
+	}
+
+	cleanName := path.Clean(slashed)
+	if cleanName == "." || !fs.ValidPath(cleanName) {
+		return "", fmt.Errorf("archive entry %q has an invalid path", name)
+	}
+	return cleanName, nil
+}
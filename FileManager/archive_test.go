@@ -0,0 +1,119 @@
+package FileManager
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenArchive(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"./project/main.go":     "package main\n",
+		"project/pkg/util.go":   "package pkg\n",
+		"project/scripts/a.py":  "print(1)\n",
+		"project/scripts/b.txt": strings.Repeat("x", 100),
+	}
+
+	zipPath := filepath.Join(dir, "drop.zip")
+	writeZip(t, zipPath, files)
+	tgzPath := filepath.Join(dir, "drop.tgz")
+	writeTar(t, tgzPath, files, true)
+	tarPath := filepath.Join(dir, "drop.tar")
+	writeTar(t, tarPath, files, false)
+
+	for _, archivePath := range []string{zipPath, tgzPath, tarPath} {
+		require.True(t, IsArchivePath(archivePath))
+
+		fsys, err := OpenArchive(archivePath, archivePath, DefaultArchiveLimits)
+		require.NoError(t, err, archivePath)
+
+		collected, err := CollectFilesMetadataFS(fsys, archivePath)
+		require.NoError(t, err)
+		require.Len(t, collected, 4)
+		require.Equal(t, filepath.Join(archivePath, "project", "main.go"), collected[0].Path)
+
+		content, err := ReadFileStringFS(fsys, filepath.Join(archivePath, "project", "pkg", "util.go"))
+		require.NoError(t, err)
+		require.Equal(t, "package pkg\n", content)
+	}
+	require.False(t, IsArchivePath(filepath.Join(dir, "main.go")))
+}
+
+func TestOpenArchiveRejectsUnsafeArchives(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, name := range []string{"../evil.go", "project/../../evil.go", "/etc/passwd", "C:\\evil.go", "..\\evil.go"} {
+		archivePath := filepath.Join(dir, "traversal.zip")
+		writeZip(t, archivePath, map[string]string{name: "package evil\n"})
+
+		_, err := OpenArchive(archivePath, archivePath, DefaultArchiveLimits)
+		require.Error(t, err, name)
+	}
+
+	// Highly compressible content expanding far beyond its compressed size
+	bombPath := filepath.Join(dir, "bomb.tar.gz")
+	writeTar(t, bombPath, map[string]string{"a.txt": strings.Repeat("0", 1<<20)}, true)
+	info, err := os.Stat(bombPath)
+	require.NoError(t, err)
+	require.Less(t, info.Size(), int64(10<<10))
+
+	_, err = OpenArchive(bombPath, bombPath, ArchiveLimits{MaxFiles: 10, MaxFileSize: 1 << 19, MaxTotalSize: 1 << 30})
+	require.ErrorIs(t, err, ErrArchiveLimit)
+
+	manyPath := filepath.Join(dir, "many.zip")
+	writeZip(t, manyPath, map[string]string{"a.go": "", "b.go": "", "c.go": ""})
+	_, err = OpenArchive(manyPath, manyPath, ArchiveLimits{MaxFiles: 2, MaxFileSize: 1 << 20, MaxTotalSize: 1 << 20})
+	require.ErrorIs(t, err, ErrArchiveLimit)
+
+	totalPath := filepath.Join(dir, "total.zip")
+	writeZip(t, totalPath, map[string]string{"a.go": strings.Repeat("a", 600), "b.go": strings.Repeat("b", 600)})
+	_, err = OpenArchive(totalPath, totalPath, ArchiveLimits{MaxFiles: 10, MaxFileSize: 1000, MaxTotalSize: 1000})
+	require.ErrorIs(t, err, ErrArchiveLimit)
+}
+
+func writeZip(t *testing.T, archivePath string, files map[string]string) {
+	var buffer bytes.Buffer
+	writer := zip.NewWriter(&buffer)
+	for name, content := range files {
+		entry, err := writer.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: time.Now()})
+		require.NoError(t, err)
+		_, err = entry.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	require.NoError(t, os.WriteFile(archivePath, buffer.Bytes(), 0644))
+}
+
+func writeTar(t *testing.T, archivePath string, files map[string]string, compress bool) {
+	var buffer bytes.Buffer
+	var gzipWriter *gzip.Writer
+	var writer *tar.Writer
+	if compress {
+		gzipWriter = gzip.NewWriter(&buffer)
+		writer = tar.NewWriter(gzipWriter)
+	} else {
+		writer = tar.NewWriter(&buffer)
+	}
+
+	require.NoError(t, writer.WriteHeader(&tar.Header{Name: "project/", Typeflag: tar.TypeDir, Mode: 0755}))
+	require.NoError(t, writer.WriteHeader(&tar.Header{Name: "project/link", Typeflag: tar.TypeSymlink, Linkname: "/etc/passwd"}))
+	for name, content := range files {
+		require.NoError(t, writer.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(content)), ModTime: time.Now()}))
+		_, err := writer.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	if compress {
+		require.NoError(t, gzipWriter.Close())
+	}
+	require.NoError(t, os.WriteFile(archivePath, buffer.Bytes(), 0644))
+}
@@ -0,0 +1,146 @@
+package FileManager
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// CodeOwnersLocations are the paths relative to the repository root where a CODEOWNERS file
+// is looked for, in the order GitHub uses them.
+var CodeOwnersLocations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// CodeOwnersRule is a line of a CODEOWNERS file.
+type CodeOwnersRule struct {
+	Pattern string
+	Owners  []string // Users, teams or emails; empty if the rule removes the owners of matching files
+	regex   *regexp.Regexp
+}
+
+// CodeOwners holds the rules of a CODEOWNERS file.
+type CodeOwners struct {
+	Rules []CodeOwnersRule
+}
+
+// ParseCodeOwners parses the content of a CODEOWNERS file.
+//
+// Arguments:
+//   - content: The content of the file.
+//
+// Returns:
+//   - CodeOwners: The rules in file order.
+//   - error: An error if a pattern is invalid.
+func ParseCodeOwners(content string) (CodeOwners, error) {
+	var codeOwners CodeOwners
+	for number, line := range strings.Split(content, "\n") {
+		if index := strings.Index(line, "#"); index >= 0 {
+			line = line[:index]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		regex, err := compileCodeOwnersPattern(fields[0])
+		if err != nil {
+			return CodeOwners{}, fmt.Errorf("invalid CODEOWNERS pattern on line %d: %w", number+1, err)
+		}
+		codeOwners.Rules = append(codeOwners.Rules, CodeOwnersRule{Pattern: fields[0], Owners: fields[1:], regex: regex})
+	}
+	return codeOwners, nil
+}
+
+// ReadCodeOwners looks for a CODEOWNERS file in the CodeOwnersLocations of a repository and parses it.
+//
+// Arguments:
+//   - fsys: The filesystem to read from.
+//   - repoRoot: The root of the repository.
+//
+// Returns:
+//   - CodeOwners: The rules, empty if there is no CODEOWNERS file.
+//   - string: The path of the CODEOWNERS file, empty if there is none.
+//   - error: An error if the file can't be read or parsed.
+func ReadCodeOwners(fsys fs.FS, repoRoot string) (CodeOwners, string, error) {
+	for _, location := range CodeOwnersLocations {
+		filePath := filepath.Join(repoRoot, filepath.FromSlash(location))
+		content, err := ReadFileStringFS(fsys, filePath)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return CodeOwners{}, "", err
+		}
+
+		codeOwners, err := ParseCodeOwners(content)
+		return codeOwners, filePath, err
+	}
+	return CodeOwners{}, "", nil
+}
+
+// Owners returns the owners of a file. As in GitHub, the last matching rule wins.
+//
+// Arguments:
+//   - relativePath: The slash separated path of the file relative to the repository root.
+//
+// Returns:
+//   - []string: The owners, empty if the file has no owner.
+func (c CodeOwners) Owners(relativePath string) []string {
+	for i := len(c.Rules) - 1; i >= 0; i-- {
+		if c.Rules[i].regex.MatchString(relativePath) {
+			return c.Rules[i].Owners
+		}
+	}
+	return nil
+}
+
+// compileCodeOwnersPattern translates a gitignore style CODEOWNERS pattern to a regular expression.
+//
+// A pattern starting with "/" or containing a "/" before its end is relative to the repository root,
+// otherwise it matches at any depth. A pattern matches the contents of matching directories, except
+// that a trailing "/*" only matches the files directly in the directory. "*" and "?" don't match "/",
+// while "**" does.
+func compileCodeOwnersPattern(pattern string) (*regexp.Regexp, error) {
+	trimmed := strings.TrimSuffix(strings.TrimPrefix(pattern, "/"), "/")
+	anchored := strings.HasPrefix(pattern, "/") || strings.Contains(trimmed, "/")
+
+	var builder strings.Builder
+	if anchored {
+		builder.WriteString("^")
+	} else {
+		builder.WriteString("^(?:.*/)?")
+	}
+
+	for i := 0; i < len(trimmed); {
+		switch {
+		case strings.HasPrefix(trimmed[i:], "**/"):
+			builder.WriteString("(?:.*/)?")
+			i += 3
+		case strings.HasPrefix(trimmed[i:], "**"):
+			builder.WriteString(".*")
+			i += 2
+		case trimmed[i] == '*':
+			builder.WriteString("[^/]*")
+			i++
+		case trimmed[i] == '?':
+			builder.WriteString("[^/]")
+			i++
+		default:
+			builder.WriteString(regexp.QuoteMeta(trimmed[i : i+1]))
+			i++
+		}
+	}
+
+	switch {
+	case strings.HasSuffix(pattern, "/*"):
+		builder.WriteString("$")
+	case strings.HasSuffix(pattern, "/"):
+		builder.WriteString("/.*$")
+	default:
+		builder.WriteString("(?:/.*)?$")
+	}
+
+	return regexp.Compile(builder.String())
+}
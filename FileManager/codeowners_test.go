@@ -0,0 +1,61 @@
+package FileManager
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodeOwners(t *testing.T) {
+	codeOwners, err := ParseCodeOwners(`
+# Default owners
+*       @org/everyone
+*.go    @org/backend   # Go files
+/docs/  @org/writers
+apps/*  @org/apps
+build/** @org/build
+**/logs ops@example.com
+/vendor/
+`)
+	require.NoError(t, err)
+	require.Len(t, codeOwners.Rules, 7)
+
+	for path, owners := range map[string][]string{
+		"README.md":                   {"@org/everyone"},
+		"main.go":                     {"@org/backend"},
+		"pkg/util/util.go":            {"@org/backend"},
+		"docs/guide/intro.md":         {"@org/writers"},
+		"docs/main.go":                {"@org/writers"},
+		"src/docs/notes.md":           {"@org/everyone"},
+		"apps/index.js":               {"@org/apps"},
+		"apps/web/index.js":           {"@org/everyone"},
+		"build/scripts/release.sh":    {"@org/build"},
+		"service/logs/today.txt":      {"ops@example.com"},
+		"logs/today.txt":              {"ops@example.com"},
+		"vendor/github.com/x/file.go": {},
+	} {
+		require.Equal(t, owners, codeOwners.Owners(path), path)
+	}
+
+	_, err = ParseCodeOwners("[unclosed @org/team")
+	require.NoError(t, err, "brackets are literal characters")
+}
+
+func TestReadCodeOwners(t *testing.T) {
+	fsys := fstest.MapFS{
+		"repo/docs/CODEOWNERS":    {Data: []byte("* @docs\n")},
+		"repo/.github/CODEOWNERS": {Data: []byte("* @github\n")},
+		"other/main.go":           {Data: []byte("package main\n")},
+	}
+
+	codeOwners, location, err := ReadCodeOwners(fsys, "repo")
+	require.NoError(t, err)
+	require.Equal(t, "repo/.github/CODEOWNERS", location)
+	require.Equal(t, []string{"@github"}, codeOwners.Owners("main.go"))
+
+	codeOwners, location, err = ReadCodeOwners(fsys, "other")
+	require.NoError(t, err)
+	require.Empty(t, location)
+	require.Empty(t, codeOwners.Rules)
+}
@@ -0,0 +1,267 @@
+package FileManager
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+)
+
+// maxSymlinkChain is the number of directory links followed below each other before the walk gives up on a
+// branch. It ends cycles that can't be detected by file identity, e.g. in filesystems without inodes.
+const maxSymlinkChain = 40
+
+// CollectOptions controls which files and directories CollectFilesMetadataWithOptions visits.
+// The zero value walks the whole tree without following directory links.
+type CollectOptions struct {
+	FollowSymlinks bool // Descend into symbolic links to directories, skipping cycles and trees already visited
+	MaxDepth       int  // Deepest level collected, 1 for the files directly in the root, 0 for no limit
+	SkipHidden     bool // Skip files and directories whose name starts with a dot
+	OneFileSystem  bool // Don't descend into directories on another device than the root (unix only)
+}
+
+// String describes the options for report headers, e.g. "follow symlinks, max depth 3".
+func (o CollectOptions) String() string {
+	var parts []string
+	if o.FollowSymlinks {
+		parts = append(parts, "follow symlinks")
+	}
+	if o.MaxDepth > 0 {
+		parts = append(parts, fmt.Sprintf("max depth %d", o.MaxDepth))
+	}
+	if o.SkipHidden {
+		parts = append(parts, "skip hidden")
+	}
+	if o.OneFileSystem {
+		parts = append(parts, "one file system")
+	}
+	if len(parts) == 0 {
+		return "defaults"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// CollectStats counts what a walk with CollectOptions left out.
+type CollectStats struct {
+	SymlinkCycles    int // Directory links leading back to a directory being walked
+	Duplicates       int // Files and directories already reached through another link
+	DepthLimited     int // Directories not entered because of MaxDepth
+	Hidden           int // Hidden files and directories skipped
+	OtherFileSystems int // Directories skipped because they are on another device
+	Unreadable       int // Entries that couldn't be read, e.g. dangling symlinks or directories without permission
+}
+
+// CollectFilesMetadataWithOptions walks through a directory and collects metadata for the files selected by options.
+//
+// Arguments:
+//   - rootDir: The directory path to scan for files.
+//   - options: Whether to follow directory links, how deep to descend and what to skip.
+//
+// Returns:
+//   - []FileMetadata: A slice containing metadata of the collected files, with absolute paths.
+//   - CollectStats: What the options left out.
+//   - error: An error if directory traversal fails.
+func CollectFilesMetadataWithOptions(rootDir string, options CollectOptions) ([]FileMetadata, CollectStats, error) {
+	absoluteRoot, err := GetAbsolutePath(rootDir)
+	if err != nil {
+		return nil, CollectStats{}, err
+	}
+
+	return CollectFilesMetadataWithOptionsFS(OSFS, absoluteRoot, options)
+}
+
+// CollectFilesMetadataWithOptionsFS is like CollectFilesMetadataWithOptions but walks a directory of a filesystem.
+// Cycles and files reachable through several links are detected by device and inode where fsys reports them
+// (OSFS on unix), and otherwise by comparing the directories being walked with os.SameFile.
+//
+// Arguments:
+//   - fsys: The filesystem to walk.
+//   - rootDir: The directory path to scan for files, as understood by fsys.
+//   - options: Whether to follow directory links, how deep to descend and what to skip.
+//
+// Returns:
+//   - []FileMetadata: A slice containing metadata of the collected files. Paths are rootDir joined with the file's path.
+//   - CollectStats: What the options left out.
+//   - error: An error if directory traversal fails.
+func CollectFilesMetadataWithOptionsFS(fsys fs.FS, rootDir string, options CollectOptions) ([]FileMetadata, CollectStats, error) {
+	walker := newCollectWalker(fsys, options, func(string) bool { return true })
+	if err := walker.walkRoot(rootDir); err != nil {
+		return nil, walker.stats, err
+	}
+	return walker.files, walker.stats, nil
+}
+
+// collectFilesMetadataFS walks rootDir with the default options and collects the metadata of the files whose
+// path passes include.
+func collectFilesMetadataFS(fsys fs.FS, rootDir string, include func(filePath string) bool) ([]FileMetadata, error) {
+	walker := newCollectWalker(fsys, CollectOptions{}, include)
+	if err := walker.walkRoot(rootDir); err != nil {
+		return nil, err
+	}
+	return walker.files, nil
+}
+
+// collectWalker holds the state of a walk: the files found, the identities of the files and directories
+// visited and the directories on the current branch.
+type collectWalker struct {
+	fsys       fs.FS
+	options    CollectOptions
+	include    func(filePath string) bool
+	files      []FileMetadata
+	stats      CollectStats
+	rootDevice fileID
+	hasDevice  bool
+	seenFiles  map[fileID]bool
+	seenDirs   map[fileID]bool
+	ancestorOf map[fileID]bool // Directories of the current branch with a known identity
+	ancestors  []fs.FileInfo   // Directories of the current branch, for filesystems without identities
+}
+
+func newCollectWalker(fsys fs.FS, options CollectOptions, include func(filePath string) bool) *collectWalker {
+	return &collectWalker{
+		fsys:       fsys,
+		options:    options,
+		include:    include,
+		seenFiles:  make(map[fileID]bool),
+		seenDirs:   make(map[fileID]bool),
+		ancestorOf: make(map[fileID]bool),
+	}
+}
+
+func (w *collectWalker) walkRoot(rootDir string) error {
+	info, err := fs.Stat(w.fsys, rootDir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		if w.include(rootDir) {
+			w.files = append(w.files, newFileMetadata(rootDir, info))
+		}
+		return nil
+	}
+
+	if id, ok := getFileID(info); ok {
+		w.rootDevice, w.hasDevice = id, true
+	}
+	return w.walkDir(rootDir, info, 0, 0)
+}
+
+// walkDir collects the files below dir. depth is the number of levels dir is below the root and links the
+// number of directory links followed on the way to it. Only failing to read the root is an error; entries
+// below it that can't be read are skipped and counted.
+func (w *collectWalker) walkDir(dir string, info fs.FileInfo, depth, links int) error {
+	id, hasID := getFileID(info)
+	if hasID {
+		w.seenDirs[id] = true
+		w.ancestorOf[id] = true
+		defer delete(w.ancestorOf, id)
+	} else {
+		w.ancestors = append(w.ancestors, info)
+		defer func() { w.ancestors = w.ancestors[:len(w.ancestors)-1] }()
+	}
+
+	entries, err := fs.ReadDir(w.fsys, dir)
+	if err != nil {
+		if depth == 0 {
+			return err
+		}
+		w.stats.Unreadable++
+		return nil
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		entryPath := path.Join(dir, name)
+		if w.options.SkipHidden && strings.HasPrefix(name, ".") {
+			w.stats.Hidden++
+			continue
+		}
+
+		// Stat instead of entry.Info() so symbolic links report their target
+		entryInfo, err := fs.Stat(w.fsys, entryPath)
+		if err != nil {
+			w.stats.Unreadable++
+			continue
+		}
+		isLink := entry.Type()&fs.ModeSymlink != 0
+
+		if !entryInfo.IsDir() {
+			w.collectFile(entryPath, entryInfo)
+			continue
+		}
+
+		if isLink && !w.options.FollowSymlinks {
+			continue
+		}
+		if w.options.MaxDepth > 0 && depth+1 >= w.options.MaxDepth {
+			w.stats.DepthLimited++
+			continue
+		}
+		if w.skipDir(entryInfo, isLink, links) {
+			continue
+		}
+
+		nextLinks := links
+		if isLink {
+			nextLinks++
+		}
+		if err := w.walkDir(entryPath, entryInfo, depth+1, nextLinks); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// skipDir reports whether a directory is left out because it is on another device, closes a cycle or was
+// already walked through another link, and counts it.
+func (w *collectWalker) skipDir(info fs.FileInfo, isLink bool, links int) bool {
+	id, hasID := getFileID(info)
+	if w.options.OneFileSystem && hasID && w.hasDevice && id.Device != w.rootDevice.Device {
+		w.stats.OtherFileSystems++
+		return true
+	}
+
+	if hasID {
+		if w.ancestorOf[id] {
+			w.stats.SymlinkCycles++
+			return true
+		}
+		if w.seenDirs[id] {
+			w.stats.Duplicates++
+			return true
+		}
+		return false
+	}
+
+	if isLink {
+		for _, ancestor := range w.ancestors {
+			if os.SameFile(ancestor, info) {
+				w.stats.SymlinkCycles++
+				return true
+			}
+		}
+		if links+1 > maxSymlinkChain {
+			w.stats.SymlinkCycles++
+			return true
+		}
+	}
+	return false
+}
+
+// collectFile adds a file unless it was already collected through another link.
+func (w *collectWalker) collectFile(filePath string, info fs.FileInfo) {
+	if !w.include(filePath) {
+		return
+	}
+	if w.options.FollowSymlinks {
+		if id, ok := getFileID(info); ok {
+			if w.seenFiles[id] {
+				w.stats.Duplicates++
+				return
+			}
+			w.seenFiles[id] = true
+		}
+	}
+	w.files = append(w.files, newFileMetadata(filePath, info))
+}
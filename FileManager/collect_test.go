@@ -0,0 +1,142 @@
+package FileManager
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectFilesMetadataWithOptionsSymlinks(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "root")
+	shared := filepath.Join(dir, "shared")
+	for _, subDir := range []string{filepath.Join(root, "pkg"), shared} {
+		require.NoError(t, os.MkdirAll(subDir, 0755))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "pkg", "util.go"), []byte("package pkg\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(shared, "lib.go"), []byte("package shared\n"), 0644))
+
+	// Two links to the same tree outside the root, a link back to the root and a link to a file inside it
+	require.NoError(t, os.Symlink(shared, filepath.Join(root, "a_shared")))
+	require.NoError(t, os.Symlink(shared, filepath.Join(root, "b_shared")))
+	require.NoError(t, os.Symlink(root, filepath.Join(root, "pkg", "loop")))
+	require.NoError(t, os.Symlink(filepath.Join(root, "main.go"), filepath.Join(root, "z_main.go")))
+
+	relativePaths := func(files []FileMetadata) []string {
+		var paths []string
+		for _, file := range files {
+			relativePath, err := filepath.Rel(root, file.Path)
+			require.NoError(t, err)
+			paths = append(paths, filepath.ToSlash(relativePath))
+		}
+		return paths
+	}
+
+	// By default directory links are skipped and file links are collected like the files
+	files, stats, err := CollectFilesMetadataWithOptions(root, CollectOptions{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"main.go", "pkg/util.go", "z_main.go"}, relativePaths(files))
+	require.Equal(t, CollectStats{}, stats)
+
+	files, stats, err = CollectFilesMetadataWithOptions(root, CollectOptions{FollowSymlinks: true})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a_shared/lib.go", "main.go", "pkg/util.go"}, relativePaths(files))
+	require.Equal(t, CollectStats{SymlinkCycles: 1, Duplicates: 2}, stats)
+
+	files, stats, err = CollectFilesMetadataWithOptions(root, CollectOptions{FollowSymlinks: true, MaxDepth: 1})
+	require.NoError(t, err)
+	require.Equal(t, []string{"main.go"}, relativePaths(files))
+	require.Equal(t, CollectStats{DepthLimited: 3, Duplicates: 1}, stats)
+
+	files, _, err = CollectFilesMetadataWithOptions(root, CollectOptions{OneFileSystem: true})
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+
+	// Dangling links are skipped instead of failing the walk
+	require.NoError(t, os.Symlink(filepath.Join(dir, "missing"), filepath.Join(root, "pkg", "dangling")))
+	files, stats, err = CollectFilesMetadataWithOptions(root, CollectOptions{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"main.go", "pkg/util.go", "z_main.go"}, relativePaths(files))
+	require.Equal(t, CollectStats{Unreadable: 1}, stats)
+}
+
+func TestCollectFilesMetadataWithOptionsFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"main.go":             {Data: []byte("package main\n")},
+		".env":                {Data: []byte("TOKEN=1\n")},
+		".github/ci.yml":      {Data: []byte("on: push\n")},
+		"pkg/util.go":         {Data: []byte("package pkg\n")},
+		"pkg/internal/a.go":   {Data: []byte("package internal\n")},
+		"pkg/internal/b/b.go": {Data: []byte("package b\n")},
+	}
+
+	files, stats, err := CollectFilesMetadataWithOptionsFS(fsys, ".", CollectOptions{SkipHidden: true, MaxDepth: 3})
+	require.NoError(t, err)
+	var paths []string
+	for _, file := range files {
+		paths = append(paths, filepath.ToSlash(file.Path))
+	}
+	require.Equal(t, []string{"main.go", "pkg/internal/a.go", "pkg/util.go"}, paths)
+	require.Equal(t, CollectStats{Hidden: 2, DepthLimited: 1}, stats)
+}
+
+// loopFS is a filesystem without file identities in which the symbolic link pkg/loop points to pkg.
+type loopFS struct {
+	fstest.MapFS
+}
+
+// resolve follows the loop links of a path.
+func (l loopFS) resolve(name string) string {
+	var parts []string
+	for _, part := range strings.Split(name, "/") {
+		if part != "loop" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+func (l loopFS) Open(name string) (fs.File, error) { return l.MapFS.Open(l.resolve(name)) }
+
+func (l loopFS) Stat(name string) (fs.FileInfo, error) { return l.MapFS.Stat(l.resolve(name)) }
+
+func (l loopFS) ReadDir(name string) ([]fs.DirEntry, error) { return l.MapFS.ReadDir(l.resolve(name)) }
+
+func TestCollectFilesMetadataWithOptionsFSLinkChain(t *testing.T) {
+	fsys := loopFS{fstest.MapFS{
+		"main.go":     {Data: []byte("package main\n")},
+		"pkg/util.go": {Data: []byte("package pkg\n")},
+		"pkg/loop":    {Mode: fs.ModeSymlink},
+	}}
+
+	// Without identities, a link cycle ends once maxSymlinkChain links were followed
+	files, stats, err := CollectFilesMetadataWithOptionsFS(fsys, ".", CollectOptions{FollowSymlinks: true})
+	require.NoError(t, err)
+	require.Equal(t, 1, stats.SymlinkCycles)
+	require.Len(t, files, maxSymlinkChain+2)
+	var paths []string
+	for _, file := range files {
+		paths = append(paths, file.Path)
+	}
+	require.Contains(t, paths, path.Join("pkg", strings.Repeat("loop/", maxSymlinkChain)+"util.go"))
+	require.NotContains(t, paths, path.Join("pkg", strings.Repeat("loop/", maxSymlinkChain+1)+"util.go"))
+
+	// Without following links, the loop isn't entered
+	files, stats, err = CollectFilesMetadataWithOptionsFS(fsys, ".", CollectOptions{})
+	require.NoError(t, err)
+	require.Equal(t, CollectStats{}, stats)
+	require.Len(t, files, 2)
+}
+
+func TestCollectOptionsString(t *testing.T) {
+	require.Equal(t, "defaults", CollectOptions{}.String())
+	require.Equal(t, "follow symlinks, max depth 3, skip hidden, one file system",
+		CollectOptions{FollowSymlinks: true, MaxDepth: 3, SkipHidden: true, OneFileSystem: true}.String())
+}
@@ -0,0 +1,16 @@
+//go:build !unix
+
+package FileManager
+
+import "io/fs"
+
+// fileID identifies a file or directory independently of the path it was reached through.
+type fileID struct {
+	Device uint64
+	Inode  uint64
+}
+
+// getFileID reports no identity; without inodes, cycles are found by comparing the directories being walked.
+func getFileID(info fs.FileInfo) (fileID, bool) {
+	return fileID{}, false
+}
@@ -0,0 +1,23 @@
+//go:build unix
+
+package FileManager
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// fileID identifies a file or directory independently of the path it was reached through.
+type fileID struct {
+	Device uint64
+	Inode  uint64
+}
+
+// getFileID returns the device and inode of a file, if info comes from the operating system.
+func getFileID(info fs.FileInfo) (fileID, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fileID{}, false
+	}
+	return fileID{Device: uint64(stat.Dev), Inode: uint64(stat.Ino)}, true
+}
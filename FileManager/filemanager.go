@@ -2,7 +2,6 @@ package FileManager
 
 import (
 	"io/fs"
-	"os"
 	"path/filepath"
 	"slices"
 )
@@ -16,24 +15,43 @@ import (
 //   - FileMetadata: Contains file name, absolute path, extension, size, and last modified time.
 //   - error: An error if the file cannot be accessed.
 func GetFileMetadata(filePath string) (FileMetadata, error) {
-	info, err := os.Stat(filePath)
+	absolutePath, err := GetAbsolutePath(filePath)
 	if err != nil {
 		return FileMetadata{}, err
 	}
 
-	absolutePath, err := GetAbsolutePath(filePath)
+	return GetFileMetadataFS(OSFS, absolutePath)
+}
+
+// GetFileMetadataFS retrieves metadata for a file of a filesystem.
+//
+// Arguments:
+//   - fsys: The filesystem the file belongs to, e.g. OSFS or an fstest.MapFS.
+//   - filePath: The path to the file, as understood by fsys.
+//
+// Returns:
+//   - FileMetadata: Contains file name, path, extension, size, and last modified time. The path is filePath in native form.
+//   - error: An error if the file cannot be accessed.
+func GetFileMetadataFS(fsys fs.FS, filePath string) (FileMetadata, error) {
+	info, err := fs.Stat(fsys, filePath)
 	if err != nil {
 		return FileMetadata{}, err
 	}
 
+	return newFileMetadata(filePath, info), nil
+}
+
+// newFileMetadata builds the metadata of a file from its path and file info.
+func newFileMetadata(filePath string, info fs.FileInfo) FileMetadata {
+	nativePath := filepath.FromSlash(filePath)
 	return FileMetadata{
 		Name:       info.Name(),
-		Path:       absolutePath,
-		Dir:        filepath.Dir(absolutePath),
-		Extension:  filepath.Ext(absolutePath),
+		Path:       nativePath,
+		Dir:        filepath.Dir(nativePath),
+		Extension:  filepath.Ext(nativePath),
 		Size:       info.Size(),
 		ModifiedAt: info.ModTime(),
-	}, nil
+	}
 }
 
 // CollectFilesMetadata walks through a directory and collects metadata for all files.
@@ -42,35 +60,28 @@ func GetFileMetadata(filePath string) (FileMetadata, error) {
 //   - rootDir: The directory path to scan for files.
 //
 // Returns:
-//   - []FileMetadata: A slice containing metadata of all discovered files.
+//   - []FileMetadata: A slice containing metadata of all discovered files, with absolute paths.
 //   - error: An error if directory traversal fails.
 func CollectFilesMetadata(rootDir string) ([]FileMetadata, error) {
-	var fileList []FileMetadata
-
-	err := filepath.Walk(rootDir,
-		func(filePath string, info fs.FileInfo, err error) error {
-			if err != nil {
-				return err
-			}
-
-			if info.IsDir() {
-				return nil
-			}
-
-			fileMeta, err := GetFileMetadata(filePath)
-			if err != nil {
-				return err
-			}
-
-			fileList = append(fileList, fileMeta)
-			return nil
-		})
-
+	absoluteRoot, err := GetAbsolutePath(rootDir)
 	if err != nil {
 		return nil, err
 	}
 
-	return fileList, nil
+	return CollectFilesMetadataFS(OSFS, absoluteRoot)
+}
+
+// CollectFilesMetadataFS walks through a directory of a filesystem and collects metadata for all files.
+//
+// Arguments:
+//   - fsys: The filesystem to walk, e.g. OSFS or a git revision opened with OpenGitRevision.
+//   - rootDir: The directory path to scan for files, as understood by fsys.
+//
+// Returns:
+//   - []FileMetadata: A slice containing metadata of all discovered files. Paths are rootDir joined with the file's path.
+//   - error: An error if directory traversal fails.
+func CollectFilesMetadataFS(fsys fs.FS, rootDir string) ([]FileMetadata, error) {
+	return collectFilesMetadataFS(fsys, rootDir, func(string) bool { return true })
 }
 
 // CollectFileMetadataByExtension scans a directory and collects metadata for files with specific extensions.
@@ -80,31 +91,29 @@ func CollectFilesMetadata(rootDir string) ([]FileMetadata, error) {
 //   - extensions: A list of file extensions to filter.
 //
 // Returns:
-//   - []FileMetadata: A slice containing metadata of filtered files.
+//   - []FileMetadata: A slice containing metadata of filtered files, with absolute paths.
 //   - error: An error if directory traversal fails.
 func CollectFileMetadataByExtension(rootDir string, extensions []string) ([]FileMetadata, error) {
-	var fileList []FileMetadata
-
-	err := filepath.Walk(rootDir,
-		func(filePath string, info fs.FileInfo, err error) error {
-			if err != nil {
-				return err
-			}
-			if info.IsDir() {
-				return nil
-			}
-
-			ext := filepath.Ext(filePath)
-			if slices.Contains(extensions, ext) {
-				if fileMeta, err := GetFileMetadata(filePath); err == nil {
-					fileList = append(fileList, fileMeta)
-				}
-			}
-			return nil
-		})
-
+	absoluteRoot, err := GetAbsolutePath(rootDir)
 	if err != nil {
 		return nil, err
 	}
-	return fileList, nil
+
+	return CollectFileMetadataByExtensionFS(OSFS, absoluteRoot, extensions)
+}
+
+// CollectFileMetadataByExtensionFS is like CollectFileMetadataByExtension but scans a directory of a filesystem.
+//
+// Arguments:
+//   - fsys: The filesystem to walk.
+//   - rootDir: The directory path to scan for files, as understood by fsys.
+//   - extensions: A list of file extensions to filter.
+//
+// Returns:
+//   - []FileMetadata: A slice containing metadata of filtered files. Paths are rootDir joined with the file's path.
+//   - error: An error if directory traversal fails.
+func CollectFileMetadataByExtensionFS(fsys fs.FS, rootDir string, extensions []string) ([]FileMetadata, error) {
+	return collectFilesMetadataFS(fsys, rootDir, func(filePath string) bool {
+		return slices.Contains(extensions, filepath.Ext(filePath))
+	})
 }
@@ -0,0 +1,77 @@
+package FileManager
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectFilesMetadataFS(t *testing.T) {
+	modTime := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	fsys := fstest.MapFS{
+		"project/main.go":       {Data: []byte("package main\n"), ModTime: modTime},
+		"project/pkg/util.go":   {Data: []byte("package pkg\n")},
+		"project/scripts/a.py":  {Data: []byte("print(1)\n")},
+		"project/docs":          {Mode: fs.ModeDir},
+		"other/ignored_file.go": {Data: []byte("package other\n")},
+	}
+
+	files, err := CollectFilesMetadataFS(fsys, "project")
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+	require.Equal(t, FileMetadata{
+		Name:       "main.go",
+		Path:       filepath.Join("project", "main.go"),
+		Dir:        "project",
+		Extension:  ".go",
+		Size:       13,
+		ModifiedAt: modTime,
+	}, files[0])
+
+	goFiles, err := CollectFileMetadataByExtensionFS(fsys, ".", []string{".go"})
+	require.NoError(t, err)
+	require.Len(t, goFiles, 3)
+	for _, file := range goFiles {
+		require.Equal(t, ".go", file.Extension)
+	}
+
+	_, err = CollectFilesMetadataFS(fsys, "missing")
+	require.Error(t, err)
+}
+
+func TestGetFileMetadataFS(t *testing.T) {
+	fsys := fstest.MapFS{"src/lib.rs": {Data: []byte("fn main() {}\n")}}
+
+	metadata, err := GetFileMetadataFS(fsys, "src/lib.rs")
+	require.NoError(t, err)
+	require.Equal(t, "lib.rs", metadata.Name)
+	require.Equal(t, filepath.Join("src", "lib.rs"), metadata.Path)
+	require.Equal(t, ".rs", metadata.Extension)
+	require.Equal(t, int64(13), metadata.Size)
+
+	_, err = GetFileMetadataFS(fsys, "src/missing.rs")
+	require.Error(t, err)
+
+	require.True(t, IsFileExistsFS(fsys, "src/lib.rs"))
+	require.False(t, IsFileExistsFS(fsys, "src"))
+	require.True(t, IsDirExistsFS(fsys, "src"))
+	require.False(t, IsDirExistsFS(fsys, "src/lib.rs"))
+}
+
+func TestCollectFilesMetadata(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "pkg"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pkg", "util.go"), []byte("package pkg\n"), 0644))
+
+	files, err := CollectFilesMetadata(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	require.Equal(t, filepath.Join(dir, "main.go"), files[0].Path)
+	require.Equal(t, filepath.Join(dir, "pkg"), files[1].Dir)
+}
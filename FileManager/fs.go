@@ -0,0 +1,28 @@
+package FileManager
+
+import (
+	"io/fs"
+	"os"
+)
+
+// OSFS is the operating system's filesystem as an fs.FS. Unlike os.DirFS it accepts
+// native absolute and relative paths, so FileMetadata paths can be passed to it unchanged.
+var OSFS fs.FS = osFS{}
+
+type osFS struct{}
+
+func (osFS) Open(name string) (fs.File, error) {
+	return os.Open(name)
+}
+
+func (osFS) Stat(name string) (fs.FileInfo, error) {
+	return os.Stat(name)
+}
+
+func (osFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	return os.ReadDir(name)
+}
+
+func (osFS) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(name)
+}
@@ -0,0 +1,228 @@
+package FileManager
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GitTree is a read-only view of a git revision. It implements fs.FS and accepts the native
+// paths of the working tree, so files can be collected and read with CollectFilesMetadataFS
+// and ReadFileStringFS as if the revision was checked out, without touching the checkout.
+type GitTree struct {
+	*treeFS
+	Commit     string    // Full hash of the commit
+	CommitTime time.Time // Committer date, used as the modification time of all files
+
+	catFile *gitCatFile
+}
+
+// OpenGitRevision lists the files of a commit, tag or branch of a local git repository.
+// File contents are read from the object database on demand.
+//
+// Arguments:
+//   - repoPath: A directory inside the working tree of the repository.
+//   - revision: The revision to read, e.g. "HEAD", "v1.2.0" or "main".
+//
+// Returns:
+//   - *GitTree: The revision, to be closed with Close.
+//   - error: An error if git fails or the revision doesn't exist.
+func OpenGitRevision(repoPath, revision string) (*GitTree, error) {
+	absolutePath, err := GetAbsolutePath(repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	commit, err := runGit(absolutePath, "rev-parse", "--verify", "--end-of-options", revision+"^{commit}")
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve revision %q: %w", revision, err)
+	}
+	commit = strings.TrimSpace(commit)
+
+	// Mount the tree at the working tree root
+	topLevel, err := GitRepositoryRoot(absolutePath)
+	if err != nil {
+		return nil, err
+	}
+
+	timestamp, err := runGit(absolutePath, "show", "-s", "--format=%ct", commit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the commit time: %w", err)
+	}
+	seconds, err := strconv.ParseInt(strings.TrimSpace(timestamp), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the commit time: %w", err)
+	}
+
+	listing, err := runGit(absolutePath, "ls-tree", "-r", "-l", "-z", "--full-tree", commit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list revision %q: %w", revision, err)
+	}
+
+	catFile, err := startGitCatFile(absolutePath)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := &GitTree{
+		treeFS:     newTreeFS(topLevel, time.Unix(seconds, 0)),
+		Commit:     commit,
+		CommitTime: time.Unix(seconds, 0),
+		catFile:    catFile,
+	}
+
+	for _, record := range strings.Split(listing, "\x00") {
+		// <mode> SP <type> SP <object> SP <size> TAB <path>
+		meta, filePath, found := strings.Cut(record, "\t")
+		fields := strings.Fields(meta)
+		if !found || len(fields) != 4 || fields[1] != "blob" || fields[0] == "120000" {
+			continue // Submodules, symbolic links and the trailing empty record
+		}
+
+		size, err := strconv.ParseInt(fields[3], 10, 64)
+		if err != nil {
+			continue
+		}
+		object := fields[2]
+		tree.addFile(filePath, size, tree.CommitTime, func() ([]byte, error) {
+			return catFile.read(object)
+		})
+	}
+
+	return tree, nil
+}
+
+// GitRepositoryRoot returns the root of the working tree containing a path, as reached through
+// that path. It may differ from `git rev-parse --show-toplevel` when the path goes through a symbolic link,
+// so paths below the returned root can be made relative to it.
+//
+// Arguments:
+//   - repoPath: A directory inside the working tree of the repository.
+//
+// Returns:
+//   - string: The absolute path of the working tree root.
+//   - error: An error if the path isn't inside a git working tree.
+func GitRepositoryRoot(repoPath string) (string, error) {
+	absolutePath, err := GetAbsolutePath(repoPath)
+	if err != nil {
+		return "", err
+	}
+
+	prefix, err := runGit(absolutePath, "rev-parse", "--show-prefix")
+	if err != nil {
+		return "", fmt.Errorf("failed to locate the repository root: %w", err)
+	}
+	if prefix = strings.TrimSpace(prefix); prefix == "" {
+		return absolutePath, nil
+	}
+	return filepath.Clean(strings.TrimSuffix(absolutePath, filepath.Clean(filepath.FromSlash(prefix)))), nil
+}
+
+// GitHeadCommit returns the commit checked out in the working tree containing a path.
+//
+// Arguments:
+//   - repoPath: A directory inside the working tree of the repository.
+//
+// Returns:
+//   - string: The full hash of the commit.
+//   - error: An error if the path isn't inside a git working tree or nothing is committed yet.
+func GitHeadCommit(repoPath string) (string, error) {
+	absolutePath, err := GetAbsolutePath(repoPath)
+	if err != nil {
+		return "", err
+	}
+
+	commit, err := runGit(absolutePath, "rev-parse", "--verify", "HEAD^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve HEAD: %w", err)
+	}
+	return strings.TrimSpace(commit), nil
+}
+
+// Close stops the git process used to read file contents.
+func (tree *GitTree) Close() error {
+	return tree.catFile.close()
+}
+
+// runGit runs a git command in dir and returns its standard output.
+func runGit(dir string, arguments ...string) (string, error) {
+	command := exec.Command("git", append([]string{"-C", dir}, arguments...)...)
+	var stderr bytes.Buffer
+	command.Stderr = &stderr
+
+	output, err := command.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", arguments[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return string(output), nil
+}
+
+// gitCatFile reads objects through a single long-running `git cat-file --batch` process.
+type gitCatFile struct {
+	mutex   sync.Mutex
+	command *exec.Cmd
+	stdin   io.WriteCloser
+	stdout  *bufio.Reader
+}
+
+func startGitCatFile(dir string) (*gitCatFile, error) {
+	command := exec.Command("git", "-C", dir, "cat-file", "--batch")
+	stdin, err := command.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start git cat-file: %w", err)
+	}
+	stdout, err := command.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start git cat-file: %w", err)
+	}
+	if err := command.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start git cat-file: %w", err)
+	}
+
+	return &gitCatFile{command: command, stdin: stdin, stdout: bufio.NewReader(stdout)}, nil
+}
+
+// read returns the content of an object.
+func (c *gitCatFile) read(object string) ([]byte, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, err := io.WriteString(c.stdin, object+"\n"); err != nil {
+		return nil, fmt.Errorf("failed to request object %s: %w", object, err)
+	}
+
+	// <object> SP <type> SP <size> LF <content> LF
+	header, err := c.stdout.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("failed to read object %s: %w", object, err)
+	}
+	fields := strings.Fields(header)
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("failed to read object %s: %s", object, strings.TrimSpace(header))
+	}
+	size, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return nil, fmt.Errorf("failed to read object %s: %w", object, err)
+	}
+
+	content := make([]byte, size+1)
+	if _, err := io.ReadFull(c.stdout, content); err != nil {
+		return nil, fmt.Errorf("failed to read object %s: %w", object, err)
+	}
+	return content[:size], nil
+}
+
+func (c *gitCatFile) close() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.stdin.Close()
+	return c.command.Wait()
+}
@@ -0,0 +1,80 @@
+package FileManager
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// NotCommittedAuthor is the author git blame reports for lines that aren't committed yet.
+const NotCommittedAuthor = "Not Committed Yet"
+
+// BlameGitFile attributes the lines of a file to the authors of the commits that last changed them.
+//
+// Arguments:
+//   - repoRoot: The root of the working tree, see GitRepositoryRoot.
+//   - filePath: The path of the file, absolute or relative to repoRoot.
+//   - revision: The revision to blame, or an empty string for the working tree.
+//
+// Returns:
+//   - map[string]int: The number of lines per author name.
+//   - error: An error if git fails, e.g. because the file isn't tracked.
+func BlameGitFile(repoRoot, filePath, revision string) (map[string]int, error) {
+	relativePath := filePath
+	if filepath.IsAbs(filePath) {
+		var err error
+		if relativePath, err = GetRelativePath(repoRoot, filePath); err != nil {
+			return nil, err
+		}
+	}
+
+	// git blame doesn't support --end-of-options, so revisions that look like options are rejected
+	arguments := []string{"blame", "--porcelain"}
+	if strings.HasPrefix(revision, "-") {
+		return nil, fmt.Errorf("invalid revision %q", revision)
+	}
+	if revision != "" {
+		arguments = append(arguments, revision)
+	}
+	output, err := runGit(repoRoot, append(arguments, "--", filepath.ToSlash(relativePath))...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to blame %s: %w", relativePath, err)
+	}
+
+	return parseBlamePorcelain(output), nil
+}
+
+// parseBlamePorcelain counts the lines per author of `git blame --porcelain` output.
+//
+// Every line of the file is preceded by a header "<commit> <original line> <final line> [<group size>]".
+// The first header of a commit is followed by its details, including "author <name>"; the line
+// itself follows the header and details, prefixed with a tab.
+func parseBlamePorcelain(output string) map[string]int {
+	authors := make(map[string]string) // Commit to author
+	lines := make(map[string]int)
+	commit := ""
+
+	for _, line := range strings.Split(output, "\n") {
+		switch {
+		case strings.HasPrefix(line, "\t"):
+			lines[authors[commit]]++
+		case strings.HasPrefix(line, "author "):
+			authors[commit] = strings.TrimPrefix(line, "author ")
+		default:
+			if fields := strings.Fields(line); len(fields) >= 3 && len(fields[0]) >= 40 && isHex(fields[0]) {
+				commit = fields[0]
+			}
+		}
+	}
+
+	return lines
+}
+
+func isHex(value string) bool {
+	for _, char := range value {
+		if !strings.ContainsRune("0123456789abcdef", char) {
+			return false
+		}
+	}
+	return true
+}
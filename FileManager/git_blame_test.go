@@ -0,0 +1,41 @@
+package FileManager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlameGitFile(t *testing.T) {
+	repo, run := newTestRepo(t)
+	git := func(author string, arguments ...string) {
+		run(append([]string{"-c", "user.name=" + author, "-c", "user.email=" + author + "@example.com"}, arguments...)...)
+	}
+	filePath := filepath.Join(repo, "src", "main.go")
+	require.NoError(t, os.MkdirAll(filepath.Dir(filePath), os.ModePerm))
+
+	require.NoError(t, os.WriteFile(filePath, []byte("package main\n\nfunc main() {\n}\n"), 0644))
+	git("alice", "add", ".")
+	git("alice", "commit", "-q", "-m", "first")
+
+	require.NoError(t, os.WriteFile(filePath, []byte("package main\n\nfunc main() {\n\tprintln(1)\n\tprintln(2)\n}\n"), 0644))
+	git("bob", "commit", "-q", "-am", "second")
+	require.NoError(t, os.WriteFile(filePath, []byte("package main\n\nfunc main() {\n\tprintln(1)\n\tprintln(2)\n}\n// todo\n"), 0644))
+
+	root, err := GitRepositoryRoot(filepath.Join(repo, "src"))
+	require.NoError(t, err)
+	require.Equal(t, repo, root)
+
+	lines, err := BlameGitFile(root, filePath, "")
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"alice": 4, "bob": 2, NotCommittedAuthor: 1}, lines)
+
+	lines, err = BlameGitFile(root, "src/main.go", "HEAD~1")
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"alice": 4}, lines)
+
+	_, err = BlameGitFile(root, "missing.go", "")
+	require.Error(t, err)
+}
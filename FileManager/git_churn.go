@@ -0,0 +1,92 @@
+package FileManager
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GitFileChurn holds how often and how much a file changed.
+type GitFileChurn struct {
+	Commits      int      // Number of commits changing the file
+	CommitHashes []string // Hashes of the commits changing the file, newest first
+	LinesAdded   int
+	LinesDeleted int
+}
+
+// GitChurn holds the churn of the files of a repository over a time window.
+type GitChurn struct {
+	Since time.Time
+	Until time.Time
+	Files map[string]GitFileChurn // Keyed by slash separated path relative to the repository root
+}
+
+// ReadGitChurn reads the churn of every file from `git log --numstat` over the days before a revision.
+// Renames aren't followed, so a renamed file starts over with the churn of its new path.
+// Binary files are skipped.
+//
+// Arguments:
+//   - repoRoot: The root of the working tree, see GitRepositoryRoot.
+//   - revision: The revision the window ends at, or an empty string for HEAD.
+//   - days: The length of the window in days.
+//
+// Returns:
+//   - GitChurn: The churn per file of the first-parent history within the window.
+//   - error: An error if git fails or the revision doesn't exist.
+func ReadGitChurn(repoRoot, revision string, days int) (GitChurn, error) {
+	if revision == "" {
+		revision = "HEAD"
+	}
+
+	timestamp, err := runGit(repoRoot, "show", "-s", "--format=%ct", "--end-of-options", revision, "--")
+	if err != nil {
+		return GitChurn{}, fmt.Errorf("failed to read the time of %q: %w", revision, err)
+	}
+	seconds, err := strconv.ParseInt(strings.TrimSpace(timestamp), 10, 64)
+	if err != nil {
+		return GitChurn{}, fmt.Errorf("failed to parse the time of %q: %w", revision, err)
+	}
+
+	churn := GitChurn{Until: time.Unix(seconds, 0)}
+	churn.Since = churn.Until.AddDate(0, 0, -days)
+
+	output, err := runGit(repoRoot, "log", "--first-parent", "--no-renames", "--numstat", "-z", "--format=%H",
+		fmt.Sprintf("--since=%d", churn.Since.Unix()), "--end-of-options", revision, "--")
+	if err != nil {
+		return GitChurn{}, fmt.Errorf("failed to read the churn of %q: %w", revision, err)
+	}
+
+	churn.Files = parseNumstat(output)
+	return churn, nil
+}
+
+// parseNumstat sums `git log --numstat -z --format=%H` records of the form "<added> TAB <deleted> TAB <path> NUL"
+// per path. Every commit starts with a "<hash> NUL" record. Binary files report "-" instead of line counts and are skipped.
+func parseNumstat(output string) map[string]GitFileChurn {
+	files := make(map[string]GitFileChurn)
+	commit := ""
+	for _, record := range strings.Split(output, "\x00") {
+		record = strings.TrimLeft(record, "\n")
+		fields := strings.SplitN(record, "\t", 3)
+		if len(fields) != 3 {
+			if len(record) >= 40 && isHex(record) {
+				commit = record
+			}
+			continue
+		}
+		added, addedErr := strconv.Atoi(fields[0])
+		deleted, deletedErr := strconv.Atoi(fields[1])
+		if addedErr != nil || deletedErr != nil {
+			continue
+		}
+
+		file := files[fields[2]]
+		file.Commits++
+		file.CommitHashes = append(file.CommitHashes, commit)
+		file.LinesAdded += added
+		file.LinesDeleted += deleted
+		files[fields[2]] = file
+	}
+	return files
+}
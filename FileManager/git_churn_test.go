@@ -0,0 +1,59 @@
+package FileManager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadGitChurn(t *testing.T) {
+	repo, git := newTestRepo(t)
+	commit := func(date string, files map[string]string) {
+		for name, content := range files {
+			path := filepath.Join(repo, name)
+			require.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+			require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		}
+		setGitDate(t, date)
+		git("add", ".")
+		git("commit", "-q", "-m", "change")
+	}
+
+	commit("2024-01-01T00:00:00Z", map[string]string{"main.go": "a\n", "old.go": "a\n"})
+	commit("2024-05-01T00:00:00Z", map[string]string{"main.go": "a\nb\nc\n", "pkg/util.go": "a\n"})
+	commit("2024-05-20T00:00:00Z", map[string]string{"main.go": "c\n", "logo.png": "\x00\x01\x02"})
+	commit("2024-06-01T00:00:00Z", map[string]string{"pkg/util.go": "a\nb\n"})
+
+	churn, err := ReadGitChurn(repo, "", 60)
+	require.NoError(t, err)
+	require.Equal(t, "2024-04-02", churn.Since.UTC().Format("2006-01-02"))
+	// Both files changed in the commit of 2024-05-01
+	require.Equal(t, churn.Files["main.go"].CommitHashes[1], churn.Files["pkg/util.go"].CommitHashes[1])
+	require.Equal(t, map[string]GitFileChurn{
+		"main.go":     {Commits: 2, LinesAdded: 2, LinesDeleted: 2},
+		"pkg/util.go": {Commits: 2, LinesAdded: 2},
+	}, withoutCommitHashes(t, churn.Files))
+
+	churn, err = ReadGitChurn(repo, "HEAD~1", 365)
+	require.NoError(t, err)
+	files := withoutCommitHashes(t, churn.Files)
+	require.Equal(t, GitFileChurn{Commits: 3, LinesAdded: 3, LinesDeleted: 2}, files["main.go"])
+	require.Equal(t, GitFileChurn{Commits: 1, LinesAdded: 1}, files["old.go"])
+	require.Equal(t, GitFileChurn{Commits: 1, LinesAdded: 1}, files["pkg/util.go"])
+}
+
+// withoutCommitHashes checks that every file has a hash per commit and drops the hashes, which differ between runs.
+func withoutCommitHashes(t *testing.T, files map[string]GitFileChurn) map[string]GitFileChurn {
+	stripped := make(map[string]GitFileChurn, len(files))
+	for name, file := range files {
+		require.Len(t, file.CommitHashes, file.Commits, name)
+		for _, hash := range file.CommitHashes {
+			require.Len(t, hash, 40, name)
+		}
+		file.CommitHashes = nil
+		stripped[name] = file
+	}
+	return stripped
+}
@@ -0,0 +1,121 @@
+package FileManager
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GitCommit is a commit of a git history.
+type GitCommit struct {
+	Hash    string    // Full hash of the commit
+	Time    time.Time // Committer date
+	Subject string    // First line of the commit message
+}
+
+// SamplePeriod is a calendar period used to sample commits, see SampleCommitsByPeriod.
+type SamplePeriod string
+
+const (
+	SampleWeek  SamplePeriod = "week"
+	SampleMonth SamplePeriod = "month"
+)
+
+// ListGitCommits lists the first-parent history of a revision, oldest commit first.
+// Following first parents only keeps the commits of merged branches out, so the history
+// is the sequence of states the revision was actually in.
+//
+// Arguments:
+//   - repoPath: A directory inside the working tree of the repository.
+//   - revision: The revision whose history is listed, e.g. "HEAD" or "main".
+//
+// Returns:
+//   - []GitCommit: The commits, oldest first.
+//   - error: An error if git fails or the revision doesn't exist.
+func ListGitCommits(repoPath, revision string) ([]GitCommit, error) {
+	absolutePath, err := GetAbsolutePath(repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := runGit(absolutePath, "log", "--first-parent", "--reverse", "-z", "--format=%H %ct %s", "--end-of-options", revision, "--")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the history of %q: %w", revision, err)
+	}
+
+	var commits []GitCommit
+	for _, record := range strings.Split(output, "\x00") {
+		fields := strings.SplitN(strings.TrimSpace(record), " ", 3)
+		if len(fields) < 2 {
+			continue // The trailing empty record
+		}
+		seconds, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the time of commit %s: %w", fields[0], err)
+		}
+
+		commit := GitCommit{Hash: fields[0], Time: time.Unix(seconds, 0)}
+		if len(fields) == 3 {
+			commit.Subject = fields[2]
+		}
+		commits = append(commits, commit)
+	}
+
+	return commits, nil
+}
+
+// SampleCommitsEvery keeps every n-th commit of a history. The latest commit is always kept,
+// and the samples are counted back from it so the spacing ends at the present state.
+//
+// Arguments:
+//   - commits: The commits, oldest first.
+//   - n: The distance between two samples; values below 2 keep all commits.
+//
+// Returns:
+//   - []GitCommit: The sampled commits, oldest first.
+func SampleCommitsEvery(commits []GitCommit, n int) []GitCommit {
+	if n < 2 {
+		return commits
+	}
+
+	var sampled []GitCommit
+	for i := (len(commits) - 1) % n; i < len(commits); i += n {
+		sampled = append(sampled, commits[i])
+	}
+	return sampled
+}
+
+// SampleCommitsByPeriod keeps the last commit of each week or month of a history, i.e. the
+// state the repository was in at the end of the period. Periods are in UTC and weeks are ISO weeks.
+//
+// Arguments:
+//   - commits: The commits, oldest first.
+//   - period: SampleWeek or SampleMonth.
+//
+// Returns:
+//   - []GitCommit: The sampled commits, oldest first.
+//   - error: An error if the period is unknown.
+func SampleCommitsByPeriod(commits []GitCommit, period SamplePeriod) ([]GitCommit, error) {
+	var periodOf func(time.Time) string
+	switch period {
+	case SampleWeek:
+		periodOf = func(t time.Time) string {
+			year, week := t.UTC().ISOWeek()
+			return fmt.Sprintf("%d-W%02d", year, week)
+		}
+	case SampleMonth:
+		periodOf = func(t time.Time) string { return t.UTC().Format("2006-01") }
+	default:
+		return nil, fmt.Errorf("unknown sample period %q, expected %q or %q", period, SampleWeek, SampleMonth)
+	}
+
+	var sampled []GitCommit
+	for i, commit := range commits {
+		// Committer dates aren't guaranteed to increase, so a period ends where the next commit's period differs
+		if i == len(commits)-1 || periodOf(commits[i+1].Time) != periodOf(commit.Time) {
+			sampled = append(sampled, commit)
+		}
+	}
+	return sampled, nil
+}
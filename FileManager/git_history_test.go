@@ -0,0 +1,67 @@
+package FileManager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListGitCommits(t *testing.T) {
+	repo, git := newTestRepo(t)
+	for i, date := range []string{"2024-01-05T10:00:00Z", "2024-02-01T10:00:00Z"} {
+		require.NoError(t, os.WriteFile(filepath.Join(repo, "main.go"), []byte{byte('a' + i)}, 0644))
+		setGitDate(t, date)
+		git("add", ".")
+		git("commit", "-q", "-m", "change number "+string(rune('1'+i)))
+	}
+
+	commits, err := ListGitCommits(repo, "HEAD")
+	require.NoError(t, err)
+	require.Len(t, commits, 2)
+	require.Equal(t, "change number 1", commits[0].Subject)
+	require.Equal(t, "change number 2", commits[1].Subject)
+	require.Len(t, commits[0].Hash, 40)
+	require.True(t, commits[0].Time.Equal(time.Date(2024, 1, 5, 10, 0, 0, 0, time.UTC)))
+
+	head, err := GitHeadCommit(repo)
+	require.NoError(t, err)
+	require.Equal(t, commits[1].Hash, head)
+	_, err = GitHeadCommit(t.TempDir())
+	require.Error(t, err)
+
+	_, err = ListGitCommits(repo, "does-not-exist")
+	require.Error(t, err)
+}
+
+func TestSampleCommits(t *testing.T) {
+	day := func(month time.Month, day int) GitCommit {
+		return GitCommit{Hash: time.Date(2024, month, day, 0, 0, 0, 0, time.UTC).Format("0102"), Time: time.Date(2024, month, day, 12, 0, 0, 0, time.UTC)}
+	}
+	commits := []GitCommit{day(1, 1), day(1, 2), day(1, 9), day(1, 31), day(2, 1), day(3, 15)}
+	hashes := func(commits []GitCommit) []string {
+		var result []string
+		for _, commit := range commits {
+			result = append(result, commit.Hash)
+		}
+		return result
+	}
+
+	require.Equal(t, hashes(commits), hashes(SampleCommitsEvery(commits, 1)))
+	require.Equal(t, []string{"0102", "0131", "0315"}, hashes(SampleCommitsEvery(commits, 2)))
+	require.Equal(t, []string{"0109", "0315"}, hashes(SampleCommitsEvery(commits, 3)))
+
+	monthly, err := SampleCommitsByPeriod(commits, SampleMonth)
+	require.NoError(t, err)
+	require.Equal(t, []string{"0131", "0201", "0315"}, hashes(monthly))
+
+	// 2024-01-01 is a Monday, so Jan 1 and 2 share a week and Jan 31 and Feb 1 do too
+	weekly, err := SampleCommitsByPeriod(commits, SampleWeek)
+	require.NoError(t, err)
+	require.Equal(t, []string{"0102", "0109", "0201", "0315"}, hashes(weekly))
+
+	_, err = SampleCommitsByPeriod(commits, "year")
+	require.Error(t, err)
+}
@@ -0,0 +1,81 @@
+package FileManager
+
+import (
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRepo creates an empty git repository in a temporary directory and skips the test if git isn't
+// installed. The returned function runs a git command in the repository as the user "test"; a command can
+// commit as someone else by starting with "-c", "user.name=...".
+func newTestRepo(t *testing.T) (string, func(arguments ...string)) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repo := t.TempDir()
+	git := func(arguments ...string) {
+		t.Helper()
+		command := exec.Command("git", append([]string{"-C", repo, "-c", "user.name=test", "-c", "user.email=test@example.com"}, arguments...)...)
+		output, err := command.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+	git("init", "-q")
+	return repo, git
+}
+
+// setGitDate makes the following commits of a test authored and committed at a date, e.g. "2024-01-01T00:00:00Z".
+func setGitDate(t *testing.T, date string) {
+	t.Setenv("GIT_AUTHOR_DATE", date)
+	t.Setenv("GIT_COMMITTER_DATE", date)
+}
+
+func TestOpenGitRevision(t *testing.T) {
+	repo, git := newTestRepo(t)
+	write := func(name, content string) {
+		path := filepath.Join(repo, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	write("main.go", "package main\n")
+	write("pkg/util/util.go", "package util\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "first")
+	git("tag", "v1")
+
+	write("main.go", "package main\n\nfunc main() {}\n")
+	write("extra.py", "print(1)\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "second")
+	write("main.go", "uncommitted")
+
+	tree, err := OpenGitRevision(filepath.Join(repo, "pkg"), "v1")
+	require.NoError(t, err)
+	defer tree.Close()
+
+	require.NoError(t, fstest.TestFS(tree, "main.go", "pkg/util/util.go"))
+
+	files, err := CollectFilesMetadataFS(tree, repo)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	require.Equal(t, filepath.Join(repo, "main.go"), files[0].Path)
+	require.Equal(t, int64(len("package main\n")), files[0].Size)
+
+	content, err := ReadFileStringFS(tree, filepath.Join(repo, "main.go"))
+	require.NoError(t, err)
+	require.Equal(t, "package main\n", content)
+
+	_, err = ReadFileStringFS(tree, filepath.Join(repo, "extra.py"))
+	require.ErrorIs(t, err, fs.ErrNotExist)
+
+	_, err = OpenGitRevision(repo, "does-not-exist")
+	require.Error(t, err)
+}
@@ -0,0 +1,103 @@
+package FileManager
+
+import (
+	"io/fs"
+	"path"
+	"path/filepath"
+	"slices"
+)
+
+// ProjectManifests lists the files marking the root directory of a project, in order of precedence.
+var ProjectManifests = []string{
+	"go.mod",
+	"package.json",
+	"Cargo.toml",
+	"pom.xml",
+	"pyproject.toml",
+	"build.gradle",
+	"build.gradle.kts",
+}
+
+// ProjectSkipDirs lists directory names that are never searched for projects,
+// since they hold dependencies or version control data.
+var ProjectSkipDirs = []string{"node_modules", "vendor", ".git", "target"}
+
+// Project represents a project found inside an analyzed root.
+type Project struct {
+	Name      string   // Path relative to the root, or the root's base name for the root itself
+	Path      string   // Absolute path of the project directory
+	Manifests []string // Manifest files found in the project directory
+}
+
+// DetectProjects walks through a directory and returns every directory containing a project manifest.
+// Nested projects are returned as well, parents first.
+//
+// Arguments:
+//   - rootDir: The directory path to scan for projects.
+//
+// Returns:
+//   - []Project: The projects found, sorted by path.
+//   - error: An error if directory traversal fails.
+func DetectProjects(rootDir string) ([]Project, error) {
+	rootDir, err := GetAbsolutePath(rootDir)
+	if err != nil {
+		return nil, err
+	}
+	return DetectProjectsFS(OSFS, rootDir)
+}
+
+// DetectProjectsFS is like DetectProjects but searches a filesystem.
+//
+// Arguments:
+//   - fsys: The filesystem to search.
+//   - rootDir: The directory path to scan for projects, as understood by fsys.
+//
+// Returns:
+//   - []Project: The projects found, sorted by path.
+//   - error: An error if directory traversal fails.
+func DetectProjectsFS(fsys fs.FS, rootDir string) ([]Project, error) {
+	var projects []Project
+	err := fs.WalkDir(fsys, rootDir,
+		func(filePath string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !entry.IsDir() {
+				return nil
+			}
+			if filePath != rootDir && slices.Contains(ProjectSkipDirs, entry.Name()) {
+				return fs.SkipDir
+			}
+
+			var manifests []string
+			for _, manifest := range ProjectManifests {
+				if info, err := fs.Stat(fsys, path.Join(filePath, manifest)); err == nil && !info.IsDir() {
+					manifests = append(manifests, manifest)
+				}
+			}
+			if len(manifests) == 0 {
+				return nil
+			}
+
+			filePath = filepath.FromSlash(filePath)
+			name, err := GetRelativePath(filepath.FromSlash(rootDir), filePath)
+			if err != nil {
+				return err
+			}
+			if name == "." {
+				name = filepath.Base(filePath)
+			}
+
+			projects = append(projects, Project{
+				Name:      filepath.ToSlash(name),
+				Path:      filePath,
+				Manifests: manifests,
+			})
+			return nil
+		})
+
+	if err != nil {
+		return nil, err
+	}
+	return projects, nil
+}
@@ -0,0 +1,35 @@
+package FileManager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectProjects(t *testing.T) {
+	root := t.TempDir()
+	for _, manifest := range []string{
+		"go.mod",
+		"services/api/go.mod",
+		"web/package.json",
+		"web/node_modules/react/package.json",
+		"tools/cli/Cargo.toml",
+		"tools/cli/pyproject.toml",
+	} {
+		path := filepath.Join(root, manifest)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+		require.NoError(t, os.WriteFile(path, nil, 0644))
+	}
+
+	projects, err := DetectProjects(root)
+	require.NoError(t, err)
+
+	names := make([]string, len(projects))
+	for i, project := range projects {
+		names[i] = project.Name
+	}
+	require.Equal(t, []string{filepath.Base(root), "services/api", "tools/cli", "web"}, names)
+	require.Equal(t, []string{"Cargo.toml", "pyproject.toml"}, projects[2].Manifests)
+}
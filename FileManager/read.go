@@ -4,7 +4,7 @@ import (
 	"bufio"
 	"errors"
 	"fmt"
-	"os"
+	"io/fs"
 )
 
 // LineHandler is a callback function type for processing each line of text.
@@ -19,7 +19,20 @@ type LineHandler func(line string) error
 // Returns:
 //   - error: If reading or processing fails, an error is returned. Otherwise, nil.
 func ReadLines(filePath string, handler LineHandler) error {
-	file, err := os.Open(filePath)
+	return ReadLinesFS(OSFS, filePath, handler)
+}
+
+// ReadLinesFS is like ReadLines but reads the file from a filesystem.
+//
+// Arguments:
+//   - fsys: The filesystem to read from.
+//   - filePath: The path to the file to be read, as understood by fsys.
+//   - handler: A callback function to process each line.
+//
+// Returns:
+//   - error: If reading or processing fails, an error is returned. Otherwise, nil.
+func ReadLinesFS(fsys fs.FS, filePath string, handler LineHandler) error {
+	file, err := fsys.Open(filePath)
 	if err != nil {
 		return fmt.Errorf("failed to open file: %w", err)
 	}
@@ -53,8 +66,22 @@ var MaxLinesReachedError = errors.New("maximum lines reached")
 // Returns:
 //   - error: If reading or processing fails, an error is returned. Otherwise, nil.
 func ReadLinesLimit(filePath string, maxLines int, handler LineHandler) error {
+	return ReadLinesLimitFS(OSFS, filePath, maxLines, handler)
+}
+
+// ReadLinesLimitFS is like ReadLinesLimit but reads the file from a filesystem.
+//
+// Arguments:
+//   - fsys: The filesystem to read from.
+//   - filePath: The path to the file to be read, as understood by fsys.
+//   - maxLines: The maximum number of lines to read.
+//   - handler: A callback function to process each line.
+//
+// Returns:
+//   - error: If reading or processing fails, an error is returned. Otherwise, nil.
+func ReadLinesLimitFS(fsys fs.FS, filePath string, maxLines int, handler LineHandler) error {
 	linesRead := 0
-	err := ReadLines(filePath, func(line string) error {
+	err := ReadLinesFS(fsys, filePath, func(line string) error {
 		if linesRead >= maxLines {
 			return MaxLinesReachedError
 		}
@@ -81,7 +108,20 @@ func ReadLinesLimit(filePath string, maxLines int, handler LineHandler) error {
 //   - []byte: The full content of the file.
 //   - error: An error if reading the file fails.
 func ReadFileBytes(filePath string) ([]byte, error) {
-	return os.ReadFile(filePath)
+	return ReadFileBytesFS(OSFS, filePath)
+}
+
+// ReadFileBytesFS reads the entire contents of a file of a filesystem and returns it as a []byte.
+//
+// Arguments:
+//   - fsys: The filesystem to read from.
+//   - filePath: The path to the file, as understood by fsys.
+//
+// Returns:
+//   - []byte: The full content of the file.
+//   - error: An error if reading the file fails.
+func ReadFileBytesFS(fsys fs.FS, filePath string) ([]byte, error) {
+	return fs.ReadFile(fsys, filePath)
 }
 
 // ReadFileString reads the entire contents of a file and returns it as a string.
@@ -93,9 +133,22 @@ func ReadFileBytes(filePath string) ([]byte, error) {
 //   - string: The full content of the file.
 //   - error: An error if reading the file fails.
 func ReadFileString(filePath string) (string, error) {
-	content, err := ReadFileBytes(filePath)
+	return ReadFileStringFS(OSFS, filePath)
+}
+
+// ReadFileStringFS reads the entire content of a file of a filesystem and returns it as a string.
+//
+// Arguments:
+//   - fsys: The filesystem to read from.
+//   - filePath: The path to the file, as understood by fsys.
+//
+// Returns:
+//   - string: The content of the file as a string.
+//   - error: If reading the file fails, an error is returned.
+func ReadFileStringFS(fsys fs.FS, filePath string) (string, error) {
+	data, err := ReadFileBytesFS(fsys, filePath)
 	if err != nil {
 		return "", err
 	}
-	return string(content), nil
+	return string(data), nil
 }
@@ -0,0 +1,38 @@
+package FileManager
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadFS(t *testing.T) {
+	fsys := fstest.MapFS{"notes.txt": {Data: []byte("first\nsecond\nthird\n")}}
+
+	content, err := ReadFileStringFS(fsys, "notes.txt")
+	require.NoError(t, err)
+	require.Equal(t, "first\nsecond\nthird\n", content)
+
+	data, err := ReadFileBytesFS(fsys, "notes.txt")
+	require.NoError(t, err)
+	require.Equal(t, []byte(content), data)
+
+	var lines []string
+	require.NoError(t, ReadLinesFS(fsys, "notes.txt", func(line string) error {
+		lines = append(lines, line)
+		return nil
+	}))
+	require.Equal(t, []string{"first", "second", "third"}, lines)
+
+	lines = nil
+	require.NoError(t, ReadLinesLimitFS(fsys, "notes.txt", 2, func(line string) error {
+		lines = append(lines, line)
+		return nil
+	}))
+	require.Equal(t, []string{"first", "second"}, lines)
+
+	_, err = ReadFileStringFS(fsys, "missing.txt")
+	require.Error(t, err)
+	require.Error(t, ReadLinesFS(fsys, "missing.txt", func(string) error { return nil }))
+}
@@ -0,0 +1,208 @@
+package FileManager
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// treeFS is a read-only in-memory directory tree whose file contents are loaded on demand.
+// It backs sources that aren't a directory on disk, such as git revisions.
+//
+// Paths are slash separated and relative to the tree root. If mount is set, native paths
+// below mount are accepted as well, so the tree can stand in for the directory it was read from.
+type treeFS struct {
+	mount    string
+	modTime  time.Time
+	entries  map[string]*treeEntry // Keyed by path relative to the tree root, "." being the root
+	children map[string][]string   // Sorted child names of each directory
+}
+
+type treeEntry struct {
+	name    string
+	size    int64
+	modTime time.Time
+	isDir   bool
+	read    func() ([]byte, error)
+}
+
+func newTreeFS(mount string, modTime time.Time) *treeFS {
+	return &treeFS{
+		mount:    mount,
+		modTime:  modTime,
+		entries:  map[string]*treeEntry{".": {name: ".", isDir: true, modTime: modTime}},
+		children: make(map[string][]string),
+	}
+}
+
+// addFile adds a file and its missing parent directories to the tree.
+func (t *treeFS) addFile(name string, size int64, modTime time.Time, read func() ([]byte, error)) {
+	name = path.Clean(strings.TrimPrefix(name, "/"))
+	if _, exists := t.entries[name]; exists {
+		return
+	}
+	t.entries[name] = &treeEntry{name: path.Base(name), size: size, modTime: modTime, read: read}
+
+	for child, dir := name, path.Dir(name); ; child, dir = dir, path.Dir(dir) {
+		t.insertChild(dir, path.Base(child))
+		if _, exists := t.entries[dir]; exists {
+			break
+		}
+		t.entries[dir] = &treeEntry{name: path.Base(dir), isDir: true, modTime: t.modTime}
+	}
+}
+
+func (t *treeFS) insertChild(dir, name string) {
+	names := t.children[dir]
+	index := sort.SearchStrings(names, name)
+	if index < len(names) && names[index] == name {
+		return
+	}
+	names = append(names, "")
+	copy(names[index+1:], names[index:])
+	names[index] = name
+	t.children[dir] = names
+}
+
+// resolve maps a path given to the fs.FS methods to the key of its entry.
+func (t *treeFS) resolve(op, name string) (string, *treeEntry, error) {
+	key := name
+	if t.mount != "" && filepath.IsAbs(filepath.FromSlash(name)) {
+		relativePath, err := filepath.Rel(t.mount, filepath.Clean(filepath.FromSlash(name)))
+		if err != nil || relativePath == ".." || strings.HasPrefix(relativePath, ".."+string(filepath.Separator)) {
+			return "", nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+		}
+		key = filepath.ToSlash(relativePath)
+	} else if !fs.ValidPath(name) {
+		return "", nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+
+	entry, found := t.entries[key]
+	if !found {
+		return "", nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+	return key, entry, nil
+}
+
+func (t *treeFS) Open(name string) (fs.File, error) {
+	key, entry, err := t.resolve("open", name)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry.isDir {
+		entries, _ := t.readDir(key)
+		return &treeDir{info: treeFileInfo{entry}, entries: entries}, nil
+	}
+
+	data, err := entry.read()
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	return &treeFile{info: treeFileInfo{entry}, reader: bytes.NewReader(data)}, nil
+}
+
+func (t *treeFS) Stat(name string) (fs.FileInfo, error) {
+	_, entry, err := t.resolve("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	return treeFileInfo{entry}, nil
+}
+
+func (t *treeFS) ReadFile(name string) ([]byte, error) {
+	_, entry, err := t.resolve("read", name)
+	if err != nil {
+		return nil, err
+	}
+	if entry.isDir {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrInvalid}
+	}
+
+	data, err := entry.read()
+	if err != nil {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: err}
+	}
+	return data, nil
+}
+
+func (t *treeFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	key, entry, err := t.resolve("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	if !entry.isDir {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	return t.readDir(key)
+}
+
+func (t *treeFS) readDir(key string) ([]fs.DirEntry, error) {
+	names := t.children[key]
+	entries := make([]fs.DirEntry, len(names))
+	for i, name := range names {
+		entries[i] = fs.FileInfoToDirEntry(treeFileInfo{t.entries[path.Join(key, name)]})
+	}
+	return entries, nil
+}
+
+// treeFileInfo describes an entry of a treeFS.
+type treeFileInfo struct {
+	entry *treeEntry
+}
+
+func (info treeFileInfo) Name() string       { return info.entry.name }
+func (info treeFileInfo) Size() int64        { return info.entry.size }
+func (info treeFileInfo) ModTime() time.Time { return info.entry.modTime }
+func (info treeFileInfo) IsDir() bool        { return info.entry.isDir }
+func (info treeFileInfo) Sys() any           { return nil }
+
+func (info treeFileInfo) Mode() fs.FileMode {
+	if info.entry.isDir {
+		return fs.ModeDir | 0555
+	}
+	return 0444
+}
+
+// treeFile is an open file of a treeFS.
+type treeFile struct {
+	info   treeFileInfo
+	reader *bytes.Reader
+}
+
+func (f *treeFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *treeFile) Read(p []byte) (int, error) { return f.reader.Read(p) }
+func (f *treeFile) Close() error               { return nil }
+
+// treeDir is an open directory of a treeFS.
+type treeDir struct {
+	info    treeFileInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *treeDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *treeDir) Close() error               { return nil }
+
+func (d *treeDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.Name(), Err: fs.ErrInvalid}
+}
+
+func (d *treeDir) ReadDir(count int) ([]fs.DirEntry, error) {
+	remaining := d.entries[d.offset:]
+	if count <= 0 {
+		d.offset = len(d.entries)
+		return remaining, nil
+	}
+	if len(remaining) == 0 {
+		return nil, io.EOF
+	}
+	count = min(count, len(remaining))
+	d.offset += count
+	return remaining[:count], nil
+}
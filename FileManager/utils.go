@@ -1,7 +1,7 @@
 package FileManager
 
 import (
-	"os"
+	"io/fs"
 	"path/filepath"
 	"runtime"
 )
@@ -39,7 +39,19 @@ func GetRelativePath(root string, path string) (string, error) {
 // Returns:
 //   - bool: `true` if the file exists, `false` otherwise.
 func IsFileExists(filename string) bool {
-	info, err := os.Stat(filename)
+	return IsFileExistsFS(OSFS, filename)
+}
+
+// IsFileExistsFS checks whether a file exists at the specified path of a filesystem.
+//
+// Arguments:
+//   - fsys: The filesystem to look in.
+//   - filename: The file path to check for existence, as understood by fsys.
+//
+// Returns:
+//   - bool: `true` if the file exists, `false` otherwise.
+func IsFileExistsFS(fsys fs.FS, filename string) bool {
+	info, err := fs.Stat(fsys, filename)
 	return err == nil && !info.IsDir()
 }
 
@@ -51,7 +63,19 @@ func IsFileExists(filename string) bool {
 // Returns:
 //   - bool: `true` if the dir exists, `false` otherwise.
 func IsDirExists(dirname string) bool {
-	info, err := os.Stat(dirname)
+	return IsDirExistsFS(OSFS, dirname)
+}
+
+// IsDirExistsFS checks whether a directory exists at the specified path of a filesystem.
+//
+// Arguments:
+//   - fsys: The filesystem to look in.
+//   - dirname: The directory path to check for existence, as understood by fsys.
+//
+// Returns:
+//   - bool: `true` if the dir exists, `false` otherwise.
+func IsDirExistsFS(fsys fs.FS, dirname string) bool {
+	info, err := fs.Stat(fsys, dirname)
 	return err == nil && info.IsDir()
 }
 
@@ -0,0 +1,70 @@
+package FileManager
+
+import (
+	"sync"
+	"time"
+)
+
+// Watcher reports changes below a directory. Events carries the paths of created, changed and removed
+// files and directories; a burst of changes to one file may be reported several times.
+type Watcher struct {
+	Events      <-chan string // Closed when the watcher stops
+	Errors      <-chan error
+	Polling     bool  // Whether the tree is polled instead of watched with inotify
+	NativeError error // Why inotify isn't used, nil when it is or polling was requested
+
+	done      chan struct{}
+	closeOnce sync.Once
+	closeFunc func() error
+}
+
+// WatchDirectory watches a directory tree for changes, with inotify on Linux and by polling elsewhere or
+// when inotify fails, e.g. because the limit of inotify watches is reached.
+//
+// Arguments:
+//   - root: The directory to watch, including all subdirectories.
+//   - pollInterval: How often the tree is scanned when polling.
+//   - forcePolling: Poll even if inotify is available, e.g. for network filesystems.
+//
+// Returns:
+//   - *Watcher: The watcher. Stop it with Close.
+//   - error: An error if the directory can't be read.
+func WatchDirectory(root string, pollInterval time.Duration, forcePolling bool) (*Watcher, error) {
+	var nativeError error
+	if !forcePolling {
+		watcher, err := watchNative(root)
+		if err == nil {
+			return watcher, nil
+		}
+		nativeError = err
+	}
+
+	watcher, err := watchPolling(root, pollInterval)
+	if err != nil {
+		return nil, err
+	}
+	watcher.NativeError = nativeError
+	return watcher, nil
+}
+
+// Close stops the watcher and closes Events.
+func (w *Watcher) Close() error {
+	var err error
+	w.closeOnce.Do(func() {
+		close(w.done)
+		if w.closeFunc != nil {
+			err = w.closeFunc()
+		}
+	})
+	return err
+}
+
+// send reports a changed path, unless the watcher was closed.
+func send[T any](w *Watcher, channel chan<- T, value T) bool {
+	select {
+	case channel <- value:
+		return true
+	case <-w.done:
+		return false
+	}
+}
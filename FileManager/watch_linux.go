@@ -0,0 +1,155 @@
+//go:build linux
+
+package FileManager
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+const inotifyMask = syscall.IN_CREATE | syscall.IN_DELETE | syscall.IN_MODIFY | syscall.IN_CLOSE_WRITE |
+	syscall.IN_MOVED_FROM | syscall.IN_MOVED_TO
+
+// inotifyWatcher watches every directory of a tree with inotify. The watches are only touched by the
+// goroutine reading the events once it runs.
+type inotifyWatcher struct {
+	watcher *Watcher
+	root    string
+	fd      int
+	file    *os.File
+	watches map[int32]string // Watched directory per watch descriptor
+	events  chan string
+	errors  chan error
+}
+
+func watchNative(root string) (*Watcher, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize inotify: %w", err)
+	}
+
+	// A non-blocking descriptor makes reads interruptible by Close
+	watcher := &inotifyWatcher{
+		root:    root,
+		fd:      fd,
+		file:    os.NewFile(uintptr(fd), "inotify"),
+		watches: make(map[int32]string),
+		events:  make(chan string, 64),
+		errors:  make(chan error, 1),
+	}
+	watcher.watcher = &Watcher{
+		Events:    watcher.events,
+		Errors:    watcher.errors,
+		done:      make(chan struct{}),
+		closeFunc: watcher.file.Close,
+	}
+
+	if _, err := watcher.addTree(root); err != nil {
+		watcher.file.Close()
+		return nil, err
+	}
+
+	go watcher.run()
+	return watcher.watcher, nil
+}
+
+// addTree watches a directory and all its subdirectories, and returns the paths found below it.
+func (w *inotifyWatcher) addTree(dir string) ([]string, error) {
+	var found []string
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if path == dir {
+				return err
+			}
+			return nil
+		}
+		if path != dir {
+			found = append(found, path)
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+
+		wd, err := syscall.InotifyAddWatch(w.fd, path, inotifyMask)
+		if err != nil {
+			return fmt.Errorf("failed to watch %s: %w", path, err)
+		}
+		w.watches[int32(wd)] = path
+		return nil
+	})
+	return found, err
+}
+
+func (w *inotifyWatcher) run() {
+	defer close(w.events)
+
+	buffer := make([]byte, 64*1024)
+	for {
+		n, err := w.file.Read(buffer)
+		if err != nil {
+			if !errors.Is(err, os.ErrClosed) {
+				send(w.watcher, w.errors, fmt.Errorf("failed to read inotify events: %w", err))
+			}
+			return
+		}
+
+		for offset := 0; offset+syscall.SizeofInotifyEvent <= n; {
+			wd := int32(binary.NativeEndian.Uint32(buffer[offset:]))
+			mask := binary.NativeEndian.Uint32(buffer[offset+4:])
+			nameLength := int(binary.NativeEndian.Uint32(buffer[offset+12:]))
+			nameStart := offset + syscall.SizeofInotifyEvent
+			name := strings.TrimRight(string(buffer[nameStart:nameStart+nameLength]), "\x00")
+			offset = nameStart + nameLength
+
+			if !w.handle(wd, mask, name) {
+				return
+			}
+		}
+	}
+}
+
+// handle reports one event and watches directories created or moved into the tree.
+func (w *inotifyWatcher) handle(wd int32, mask uint32, name string) bool {
+	if mask&syscall.IN_Q_OVERFLOW != 0 {
+		// Events were dropped, so anything may have changed
+		return send(w.watcher, w.events, w.root)
+	}
+	if mask&syscall.IN_IGNORED != 0 {
+		delete(w.watches, wd)
+		return true
+	}
+
+	dir, found := w.watches[wd]
+	if !found {
+		return true
+	}
+	path := dir
+	if name != "" {
+		path = filepath.Join(dir, name)
+	}
+
+	if !send(w.watcher, w.events, path) {
+		return false
+	}
+	if mask&syscall.IN_ISDIR == 0 || mask&(syscall.IN_CREATE|syscall.IN_MOVED_TO) == 0 {
+		return true
+	}
+
+	// Entries created before the new directory was watched have no events of their own
+	entries, err := w.addTree(path)
+	if err != nil && !send(w.watcher, w.errors, err) {
+		return false
+	}
+	for _, entryPath := range entries {
+		if !send(w.watcher, w.events, entryPath) {
+			return false
+		}
+	}
+	return true
+}
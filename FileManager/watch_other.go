@@ -0,0 +1,10 @@
+//go:build !linux
+
+package FileManager
+
+import "errors"
+
+// watchNative is only implemented with inotify on Linux, other systems are polled.
+func watchNative(root string) (*Watcher, error) {
+	return nil, errors.ErrUnsupported
+}
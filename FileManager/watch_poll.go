@@ -0,0 +1,90 @@
+package FileManager
+
+import (
+	"io/fs"
+	"path/filepath"
+	"time"
+)
+
+// pollState is what polling compares to find changed files and directories.
+type pollState struct {
+	Size    int64
+	ModTime int64
+	IsDir   bool
+}
+
+// watchPolling scans the tree every interval and reports every path that was added, removed or
+// changed its size or modification time since the previous scan.
+func watchPolling(root string, interval time.Duration) (*Watcher, error) {
+	state, err := scanTree(root)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan string, 64)
+	errs := make(chan error, 1)
+	watcher := &Watcher{Events: events, Errors: errs, Polling: true, done: make(chan struct{})}
+
+	go func() {
+		defer close(events)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-watcher.done:
+				return
+			case <-ticker.C:
+			}
+
+			next, err := scanTree(root)
+			if err != nil {
+				if !send(watcher, errs, err) {
+					return
+				}
+				continue
+			}
+			for path, current := range next {
+				if previous, found := state[path]; !found || previous != current {
+					if !send(watcher, events, path) {
+						return
+					}
+				}
+			}
+			for path := range state {
+				if _, found := next[path]; !found {
+					if !send(watcher, events, path) {
+						return
+					}
+				}
+			}
+			state = next
+		}
+	}()
+
+	return watcher, nil
+}
+
+// scanTree returns the state of every file and directory below root. Entries vanishing during the scan are skipped.
+func scanTree(root string) (map[string]pollState, error) {
+	state := make(map[string]pollState)
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			return nil
+		}
+		if path == root {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil
+		}
+		state[path] = pollState{Size: info.Size(), ModTime: info.ModTime().UnixNano(), IsDir: info.IsDir()}
+		return nil
+	})
+	return state, err
+}
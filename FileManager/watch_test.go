@@ -0,0 +1,55 @@
+package FileManager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchDirectory(t *testing.T) {
+	for _, forcePolling := range []bool{false, true} {
+		root := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n"), 0644))
+
+		watcher, err := WatchDirectory(root, 20*time.Millisecond, forcePolling)
+		require.NoError(t, err)
+		if forcePolling {
+			require.True(t, watcher.Polling)
+		}
+
+		// Files in directories created after the watch started are reported too
+		newDir := filepath.Join(root, "pkg", "util")
+		require.NoError(t, os.MkdirAll(newDir, 0755))
+		waitForEvent(t, watcher, newDir)
+		require.NoError(t, os.WriteFile(filepath.Join(newDir, "util.go"), []byte("package util\n"), 0644))
+		waitForEvent(t, watcher, filepath.Join(newDir, "util.go"))
+
+		require.NoError(t, os.Remove(filepath.Join(root, "main.go")))
+		waitForEvent(t, watcher, filepath.Join(root, "main.go"))
+
+		require.NoError(t, watcher.Close())
+		for range watcher.Events {
+		}
+	}
+}
+
+// waitForEvent reads events until path is reported.
+func waitForEvent(t *testing.T, watcher *Watcher, path string) {
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			require.True(t, ok, "watcher stopped")
+			if event == path {
+				return
+			}
+		case err := <-watcher.Errors:
+			require.NoError(t, err)
+		case <-timeout:
+			t.Fatalf("%s wasn't reported", path)
+		}
+	}
+}
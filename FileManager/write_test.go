@@ -2,43 +2,47 @@ package FileManager
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 )
 
-var samplePath string = "../test/SampleCodes.txt"
 var sampleMessage string = "this is a sample data"
 
 func TestOverwriteFile(t *testing.T) {
+	samplePath := filepath.Join(t.TempDir(), "SampleCodes.txt")
 	err := OverwriteFile(samplePath, []byte(sampleMessage))
 	require.NoError(t, err, "Something messed up")
 
-	existenceChecker(t)
+	existenceChecker(t, samplePath)
 }
 
 func TestOverwriteFileString(t *testing.T) {
+	samplePath := filepath.Join(t.TempDir(), "SampleCodes.txt")
 	err := OverwriteFileString(samplePath, sampleMessage)
 	require.NoError(t, err, "Something messed up")
 
-	existenceChecker(t)
+	existenceChecker(t, samplePath)
 }
 
 func TestAppendFile(t *testing.T) {
+	samplePath := filepath.Join(t.TempDir(), "SampleCodes.txt")
 	err := AppendFile(samplePath, []byte(sampleMessage))
 	require.NoError(t, err, "Something messed up")
 
-	existenceChecker(t)
+	existenceChecker(t, samplePath)
 }
 
 func TestAppendFileString(t *testing.T) {
+	samplePath := filepath.Join(t.TempDir(), "SampleCodes.txt")
 	err := AppendFileString(samplePath, sampleMessage)
 	require.NoError(t, err, "Something messed up")
 
-	existenceChecker(t)
+	existenceChecker(t, samplePath)
 }
 
-func existenceChecker(t *testing.T) {
+func existenceChecker(t *testing.T, samplePath string) {
 	existence := IsFileExists(samplePath)
 
 	t.Log("IsFileExists: ", existence)
@@ -0,0 +1,62 @@
+package Visualizer
+
+import "fmt"
+
+// PieChartDataByShare converts values to pie chart slices labeled with their share of the sum, e.g. the
+// totals of the languages returned by the Database aggregates.
+//
+// Arguments:
+//   - labels: The label of every value, e.g. the language names.
+//   - values: The values, in the order of labels.
+//   - colors: The color of every value in hex format, or nil for the default colors.
+//
+// Returns:
+//   - []PieChartData: One slice per positive value, labeled like "Go 75.0%", with the percentage as value.
+func PieChartDataByShare(labels []string, values []int64, colors []string) []PieChartData {
+	var sum int64
+	for _, value := range values {
+		if value > 0 {
+			sum += value
+		}
+	}
+
+	var chartData []PieChartData
+	for i, value := range values {
+		if value <= 0 {
+			continue
+		}
+		percent := float64(value) * 100 / float64(sum)
+		chartData = append(chartData, PieChartData{
+			Label:    fmt.Sprintf("%s %.1f%%", labels[i], percent),
+			Value:    percent,
+			ColorHex: colorAt(colors, i),
+		})
+	}
+	return chartData
+}
+
+// BarChartDataOf converts values to bars, e.g. the totals of the extensions or directories returned by the
+// Database aggregates.
+//
+// Arguments:
+//   - labels: The label of every bar.
+//   - values: The values, in the order of labels.
+//   - colors: The color of every bar in hex format, or nil for the default colors.
+//
+// Returns:
+//   - []BarChartData: One bar per value.
+func BarChartDataOf(labels []string, values []int64, colors []string) []BarChartData {
+	chartData := make([]BarChartData, 0, len(values))
+	for i, value := range values {
+		chartData = append(chartData, BarChartData{Label: labels[i], Value: float64(value), ColorHex: colorAt(colors, i)})
+	}
+	return chartData
+}
+
+// colorAt returns the color of the value at index i, empty for the default color.
+func colorAt(colors []string, i int) string {
+	if i < len(colors) {
+		return colors[i]
+	}
+	return ""
+}
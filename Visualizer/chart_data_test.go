@@ -0,0 +1,31 @@
+package Visualizer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChartData(t *testing.T) {
+	labels := []string{"Go", "Python", "Shell"}
+	values := []int64{75, 25, 0}
+	colors := []string{"#00ADD8", "#3572A5", "#89E051"}
+
+	pie := PieChartDataByShare(labels, values, colors)
+	require.Len(t, pie, 2)
+	require.Equal(t, "Go 75.0%", pie[0].Label)
+	require.Equal(t, 75.0, pie[0].Value)
+	require.Equal(t, "#00ADD8", pie[0].ColorHex)
+
+	bars := BarChartDataOf(labels, values, colors)
+	require.Len(t, bars, 3)
+	require.Equal(t, "Python", bars[1].Label)
+	require.Equal(t, 25.0, bars[1].Value)
+	require.Equal(t, "#3572A5", bars[1].ColorHex)
+
+	// Without colors the default ones are used
+	bars = BarChartDataOf([]string{".go", "(none)"}, []int64{2, 0}, nil)
+	require.Equal(t, ".go", bars[0].Label)
+	require.Equal(t, 2.0, bars[0].Value)
+	require.Empty(t, bars[0].ColorHex)
+}
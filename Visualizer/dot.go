@@ -0,0 +1,45 @@
+package Visualizer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"statfiy/FileManager"
+)
+
+// CreateDotGraph generates a Graphviz DOT directed graph and writes it to a file.
+//
+// Args:
+//   - config: A DotGraphConfig struct containing the graph configuration.
+//
+// Returns:
+//   - None (writes the DOT source to the specified file).
+func CreateDotGraph(config DotGraphConfig) error {
+	rankDir := config.RankDir
+	if rankDir == "" {
+		rankDir = defaultDotGraph.RankDir
+	}
+
+	builder := strings.Builder{}
+	builder.WriteString(fmt.Sprintf("digraph %s {\n", strconv.Quote(config.Title)))
+	builder.WriteString(fmt.Sprintf("  rankdir=%s;\n", rankDir))
+	builder.WriteString("  node [shape=box, style=\"rounded,filled\"];\n")
+
+	for _, node := range config.Nodes {
+		color := getColorOrDefault(node.ColorHex, defaultDotGraph.NodeColor)
+		builder.WriteString(fmt.Sprintf("  %s [label=%s, fillcolor=%s];\n",
+			strconv.Quote(node.Id), strconv.Quote(node.Label), strconv.Quote(color)))
+	}
+
+	for _, edge := range config.Edges {
+		builder.WriteString(fmt.Sprintf("  %s -> %s", strconv.Quote(edge.From), strconv.Quote(edge.To)))
+		if edge.Weight > 0 {
+			builder.WriteString(fmt.Sprintf(" [label=\"%d\"]", edge.Weight))
+		}
+		builder.WriteString(";\n")
+	}
+
+	builder.WriteString("}\n")
+	return FileManager.OverwriteFileString(config.OutputPath, builder.String())
+}
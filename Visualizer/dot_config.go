@@ -0,0 +1,35 @@
+package Visualizer
+
+// DotGraphConfig defines the structure for configuring a Graphviz DOT graph.
+type DotGraphConfig struct {
+	Title      string
+	RankDir    string // LR, RL, TB or BT
+	Nodes      []GraphNode
+	Edges      []GraphEdge
+	OutputPath string
+}
+
+// BuildDotGraphConfig creates a Graphviz DOT graph configuration.
+//
+// Args:
+//   - title: The name of the graph.
+//   - nodes: The nodes of the graph.
+//   - edges: The edges between the nodes, referencing them by Id.
+//   - outputPath: The file path where the DOT source will be written.
+//
+// Returns:
+//   - DotGraphConfig: A configuration struct for a DOT graph.
+func BuildDotGraphConfig(
+	title string,
+	nodes []GraphNode,
+	edges []GraphEdge,
+	outputPath string,
+) DotGraphConfig {
+	return DotGraphConfig{
+		Title:      title,
+		RankDir:    defaultDotGraph.RankDir,
+		Nodes:      nodes,
+		Edges:      edges,
+		OutputPath: outputPath,
+	}
+}
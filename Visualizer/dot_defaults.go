@@ -0,0 +1,13 @@
+package Visualizer
+
+// dotGraphDefaults holds default configuration values for Graphviz DOT graphs.
+type dotGraphDefaults struct {
+	RankDir   string
+	NodeColor string
+}
+
+// defaultDotGraph provides a default instance of dotGraphDefaults.
+var defaultDotGraph = dotGraphDefaults{
+	RankDir:   "LR",
+	NodeColor: "#ECECFF",
+}
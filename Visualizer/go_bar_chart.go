@@ -0,0 +1,106 @@
+package Visualizer
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/wcharczuk/go-chart/v2"
+	"github.com/wcharczuk/go-chart/v2/drawing"
+)
+
+// CreateGoBarChart creates a bar chart based on the provided configuration and saves it to a file.
+// Bars grow up from zero for positive values and down for negative values.
+//
+// Args:
+//
+//	config: A GoBarChartConfig struct containing all necessary chart settings and data.
+//
+// Returns:
+//
+//	An error if there is no data or if any step of the chart creation or file writing process fails.
+func CreateGoBarChart(config GoBarChartConfig) error {
+	if len(config.Data) == 0 {
+		return fmt.Errorf("no data to render in bar chart '%s'", config.ChartTitle.Text)
+	}
+
+	barChart := chart.BarChart{
+		Title: config.ChartTitle.Text,
+		TitleStyle: chart.Style{
+			FontSize:  config.ChartTitle.FontSize,
+			FontColor: drawing.ColorFromHex(config.ChartTitle.ColorHex),
+			Padding:   chart.Box{Top: config.ChartTitle.Margin - int(config.ChartTitle.FontSize)},
+		},
+		Width:      config.ChartAppearance.Width,
+		Height:     config.ChartAppearance.Height,
+		BarWidth:   config.BarWidth,
+		BarSpacing: config.BarSpacing,
+		Background: chart.Style{
+			FillColor: drawing.ColorFromHex(config.ChartAppearance.BackgroundColorHex),
+			Padding: chart.Box{
+				Top:    config.ChartAppearance.Padding.Top,
+				Bottom: config.ChartAppearance.Padding.Bottom,
+				Left:   config.ChartAppearance.Padding.Left,
+				Right:  config.ChartAppearance.Padding.Right,
+			},
+		},
+		XAxis: createAxisStyle(config.LabelConfig),
+		YAxis: chart.YAxis{
+			Style: createAxisStyle(config.LabelConfig),
+			Range: calculateBarRange(config.Data),
+			ValueFormatter: func(v interface{}) string {
+				return fmt.Sprintf("%s%s", chart.FloatValueFormatter(v), config.ValueSuffix)
+			},
+		},
+		UseBaseValue: true,
+		BaseValue:    0,
+		Bars:         createBarValues(config.Data, config.ChartAppearance),
+	}
+
+	file, err := os.Create(config.OutputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create output file '%s': %w", config.OutputPath, err)
+	}
+	defer file.Close()
+
+	return barChart.Render(chart.SVG, file)
+}
+
+// createBarValues transforms the input BarChartData into a slice of chart.Value.
+func createBarValues(data []BarChartData, chartAppearance GoChartAppearance) []chart.Value {
+	values := make([]chart.Value, len(data))
+	for i, d := range data {
+		values[i] = chart.Value{
+			Value: d.Value,
+			Label: d.Label,
+			Style: chart.Style{
+				FillColor:   drawing.ColorFromHex(getColorOrDefault(d.ColorHex, defaultColors[i%len(defaultColors)])),
+				StrokeWidth: 1,
+				StrokeColor: drawing.ColorFromHex(chartAppearance.BorderColorHex),
+			},
+		}
+	}
+	return values
+}
+
+// calculateBarRange returns a Y axis range that always includes zero,
+// so bars of negative values are drawn below the axis.
+func calculateBarRange(data []BarChartData) *chart.ContinuousRange {
+	minValue, maxValue := 0.0, 0.0
+	for _, d := range data {
+		minValue = min(minValue, d.Value)
+		maxValue = max(maxValue, d.Value)
+	}
+	if minValue == maxValue {
+		maxValue = minValue + 1
+	}
+	return &chart.ContinuousRange{Min: minValue, Max: maxValue}
+}
+
+// createAxisStyle returns the style shared by the X and Y axis.
+func createAxisStyle(labelConfig GoChartLabelConfig) chart.Style {
+	return chart.Style{
+		FontSize:    labelConfig.FontSize,
+		FontColor:   drawing.ColorFromHex(labelConfig.ColorHex),
+		StrokeColor: drawing.ColorFromHex(defaultGoChart.AxisColor),
+	}
+}
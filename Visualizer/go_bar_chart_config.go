@@ -0,0 +1,72 @@
+package Visualizer
+
+import "strings"
+
+// GoBarChartConfig holds all the configuration settings for a bar chart.
+type GoBarChartConfig struct {
+	ChartAppearance GoChartAppearance  // Overall appearance settings
+	ChartTitle      GoChartTitle       // Title configuration
+	LabelConfig     GoChartLabelConfig // Axis label appearance
+	BarWidth        int                // Width of a single bar
+	BarSpacing      int                // Space between two bars
+	ValueSuffix     string             // Appended to the values on the Y axis, e.g. "%"
+	Data            []BarChartData
+	OutputPath      string
+}
+
+// BuildGoBarChartConfig is a constructor function that creates a GoBarChartConfig based on the provided parameters.
+// The chart grows wider than the requested width when there are too many bars to fit.
+//
+// Args:
+//
+//	title: The title of the chart.
+//	data: A slice of BarChartData, one entry per bar. Values may be negative.
+//	width: The desired width of the chart.
+//	height: The desired height of the chart.
+//	valueSuffix: A suffix for the values on the Y axis, e.g. "%", or an empty string.
+//	outputPath: The path where the generated chart image will be saved.
+//
+// Returns:
+//
+//	A configured GoBarChartConfig struct.
+func BuildGoBarChartConfig(
+	title string,
+	data []BarChartData,
+	width int,
+	height int,
+	valueSuffix string,
+	outputPath string,
+) GoBarChartConfig {
+	titleHeight := defaultGoChart.TitleHeight
+	titleMargin := defaultGoChart.TitleMargin
+
+	if strings.TrimSpace(title) == "" {
+		titleHeight = 0
+		titleMargin = 0
+	}
+
+	totalWidth := max(width, len(data)*defaultGoChart.BarSlotWidth+2*defaultGoChart.Padding+defaultGoChart.LegendPadding)
+	plotWidth := totalWidth - 2*defaultGoChart.Padding - defaultGoChart.LegendPadding
+	barWidth := defaultGoChart.BarSlotWidth - defaultGoChart.BarSpacing
+	if len(data) > 0 {
+		barWidth = max(plotWidth/len(data)-defaultGoChart.BarSpacing, 1)
+	}
+
+	chartAppearance := createDefaultChartAppearance(totalWidth, height+titleHeight, Padding{
+		Left:   defaultGoChart.Padding,
+		Top:    titleHeight,
+		Right:  defaultGoChart.Padding,
+		Bottom: defaultGoChart.LegendPadding,
+	})
+
+	return GoBarChartConfig{
+		ChartAppearance: chartAppearance,
+		ChartTitle:      createDefaultChartTitle(title, titleMargin),
+		LabelConfig:     createDefaultLabelConfig(),
+		BarWidth:        barWidth,
+		BarSpacing:      defaultGoChart.BarSpacing,
+		ValueSuffix:     valueSuffix,
+		Data:            data,
+		OutputPath:      outputPath,
+	}
+}
@@ -29,6 +29,9 @@ const (
 //	An error if any step of the chart creation or file writing process fails.
 func CreateGoPieChart(config GoPieChartConfig) error {
 	config.Data = sortPieChartDataByValue(config.Data)
+	for i := range config.Data {
+		config.Data[i].ColorHex = getColorOrDefault(config.Data[i].ColorHex, defaultColors[i%len(defaultColors)])
+	}
 	chartValues := createChartValues(config.Data, config.ChartAppearance)
 	pieChart := createPieChart(chartValues, config.ChartAppearance)
 
@@ -18,6 +18,10 @@ type goChartDefaults struct {
 	LabelColor       string
 	LabelMarkerSize  int
 	IndicatorSize    int
+	BarSpacing       int
+	BarSlotWidth     int
+	AxisColor        string
+	DotSize          float64
 }
 
 // defaultGoChart provides a default instance of GoChartConfig.
@@ -38,6 +42,10 @@ var defaultGoChart = goChartDefaults{
 	LabelColor:       "#000000",
 	LabelMarkerSize:  15,
 	IndicatorSize:    20,
+	BarSpacing:       10,
+	BarSlotWidth:     90,
+	AxisColor:        "#666666",
+	DotSize:          5,
 }
 
 // createDefaultChartAppearance creates and initializes a GoChartAppearance struct with the provided dimensions and padding,
@@ -0,0 +1,120 @@
+package Visualizer
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/wcharczuk/go-chart/v2"
+	"github.com/wcharczuk/go-chart/v2/drawing"
+)
+
+// CreateGoLineChart creates a line chart over time based on the provided configuration and saves it to a file.
+// Stacked charts draw every series as a filled area on top of the previous ones, so the top edge is the total.
+//
+// Args:
+//
+//	config: A GoLineChartConfig struct containing all necessary chart settings and data.
+//
+// Returns:
+//
+//	An error if there are fewer than two points in time, a series doesn't match the X values,
+//	or if any step of the chart creation or file writing process fails.
+func CreateGoLineChart(config GoLineChartConfig) error {
+	if len(config.XValues) < 2 || len(config.Series) == 0 {
+		return fmt.Errorf("line chart '%s' needs at least two points in time and one series", config.ChartTitle.Text)
+	}
+	for _, s := range config.Series {
+		if len(s.Values) != len(config.XValues) {
+			return fmt.Errorf("series '%s' has %d values for %d points in time", s.Label, len(s.Values), len(config.XValues))
+		}
+	}
+
+	series, maxValue := createLineSeries(config)
+	legendData := make([]PieChartData, len(config.Series))
+	for i, s := range config.Series {
+		legendData[i] = PieChartData{Label: s.Label, ColorHex: seriesColor(s, i)}
+	}
+
+	lineChart := chart.Chart{
+		Title: config.ChartTitle.Text,
+		TitleStyle: chart.Style{
+			FontSize:  config.ChartTitle.FontSize,
+			FontColor: drawing.ColorFromHex(config.ChartTitle.ColorHex),
+			Padding:   chart.Box{Top: config.ChartTitle.Margin - int(config.ChartTitle.FontSize)},
+		},
+		Width:  config.ChartAppearance.Width,
+		Height: config.ChartAppearance.Height,
+		Background: chart.Style{
+			FillColor: drawing.ColorFromHex(config.ChartAppearance.BackgroundColorHex),
+			Padding: chart.Box{
+				Top:    config.ChartAppearance.Padding.Top,
+				Bottom: config.ChartAppearance.Padding.Bottom,
+				Left:   config.ChartAppearance.Padding.Left,
+				Right:  config.ChartAppearance.Padding.Right,
+			},
+		},
+		XAxis: chart.XAxis{
+			Style:          createAxisStyle(config.LabelConfig),
+			ValueFormatter: chart.TimeValueFormatterWithFormat("2006-01-02"),
+		},
+		YAxis: chart.YAxis{
+			Style: createAxisStyle(config.LabelConfig),
+			Range: &chart.ContinuousRange{Min: 0, Max: max(maxValue, 1)},
+		},
+		Series: series,
+		Elements: []chart.Renderable{
+			func(r chart.Renderer, _ chart.Box, _ chart.Style) {
+				renderLegend(r, legendData, config.LabelConfig, config.LegendConfig)
+			},
+		},
+	}
+
+	file, err := os.Create(config.OutputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create output file '%s': %w", config.OutputPath, err)
+	}
+	defer file.Close()
+
+	return lineChart.Render(chart.SVG, file)
+}
+
+// createLineSeries transforms the input series into chart series and returns the largest value to plot.
+// Stacked series hold running totals and are returned top layer first, so each area
+// is drawn before the smaller ones that cover its lower part.
+func createLineSeries(config GoLineChartConfig) ([]chart.Series, float64) {
+	series := make([]chart.Series, len(config.Series))
+	totals := make([]float64, len(config.XValues))
+	maxValue := 0.0
+
+	for i, s := range config.Series {
+		color := drawing.ColorFromHex(seriesColor(s, i))
+		values := make([]float64, len(s.Values))
+		for j, value := range s.Values {
+			if config.Stacked {
+				totals[j] += value
+				value = totals[j]
+			}
+			values[j] = value
+			maxValue = max(maxValue, value)
+		}
+
+		style := chart.Style{StrokeColor: color, StrokeWidth: 2}
+		if config.Stacked {
+			style.FillColor = color
+			style.StrokeWidth = 1
+		}
+		series[i] = chart.TimeSeries{Name: s.Label, Style: style, XValues: config.XValues, YValues: values}
+	}
+
+	if config.Stacked {
+		for i, j := 0, len(series)-1; i < j; i, j = i+1, j-1 {
+			series[i], series[j] = series[j], series[i]
+		}
+	}
+	return series, maxValue
+}
+
+// seriesColor returns the color of a series, or a default color if none is provided.
+func seriesColor(s LineSeriesData, index int) string {
+	return getColorOrDefault(s.ColorHex, defaultColors[index%len(defaultColors)])
+}
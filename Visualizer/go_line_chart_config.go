@@ -0,0 +1,75 @@
+package Visualizer
+
+import (
+	"strings"
+	"time"
+)
+
+// GoLineChartConfig holds all the configuration settings for a line chart over time.
+type GoLineChartConfig struct {
+	ChartAppearance GoChartAppearance   // Overall appearance settings
+	ChartTitle      GoChartTitle        // Title configuration
+	LabelConfig     GoChartLabelConfig  // Axis and legend label appearance
+	LegendConfig    GoChartLegendConfig // Legend structure and positioning
+	Stacked         bool                // Stack the series on top of each other as filled areas
+	XValues         []time.Time         // Shared X values of all series, in increasing order
+	Series          []LineSeriesData
+	OutputPath      string
+}
+
+// BuildGoLineChartConfig is a constructor function that creates a GoLineChartConfig based on the provided parameters.
+// The legend is placed below the plot.
+//
+// Args:
+//
+//	title: The title of the chart.
+//	xValues: The points in time of the X axis, in increasing order.
+//	series: A slice of LineSeriesData, each with one value per X value.
+//	stacked: Whether the series are drawn as stacked areas instead of lines.
+//	width: The desired width of the chart.
+//	height: The desired height of the chart (excluding the legend).
+//	outputPath: The path where the generated chart image will be saved.
+//
+// Returns:
+//
+//	A configured GoLineChartConfig struct.
+func BuildGoLineChartConfig(
+	title string,
+	xValues []time.Time,
+	series []LineSeriesData,
+	stacked bool,
+	width int,
+	height int,
+	outputPath string,
+) GoLineChartConfig {
+	titleHeight := defaultGoChart.TitleHeight
+	titleMargin := defaultGoChart.TitleMargin
+
+	if strings.TrimSpace(title) == "" {
+		titleHeight = 0
+		titleMargin = 0
+	}
+
+	columnCount := max(width/defaultGoChart.LegendItemWidth, 1)
+	rowCount := calculateRowCount(len(series), columnCount)
+	legendHeight := rowCount*defaultGoChart.LegendRowSpacing + 2*defaultGoChart.LegendPadding
+	totalHeight := height + titleHeight + legendHeight
+
+	chartAppearance := createDefaultChartAppearance(width, totalHeight, Padding{
+		Left:   defaultGoChart.Padding,
+		Top:    titleHeight,
+		Right:  defaultGoChart.Padding,
+		Bottom: legendHeight,
+	})
+
+	return GoLineChartConfig{
+		ChartAppearance: chartAppearance,
+		ChartTitle:      createDefaultChartTitle(title, titleMargin),
+		LabelConfig:     createDefaultLabelConfig(),
+		LegendConfig:    createBottomLegendConfig(columnCount, rowCount, totalHeight, legendHeight),
+		Stacked:         stacked,
+		XValues:         xValues,
+		Series:          series,
+		OutputPath:      outputPath,
+	}
+}
@@ -0,0 +1,131 @@
+package Visualizer
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/wcharczuk/go-chart/v2"
+	"github.com/wcharczuk/go-chart/v2/drawing"
+)
+
+// CreateGoScatterChart creates a scatter chart based on the provided configuration and saves it to a file.
+// Points with a label are annotated on the chart.
+//
+// Args:
+//
+//	config: A GoScatterChartConfig struct containing all necessary chart settings and data.
+//
+// Returns:
+//
+//	An error if there is no data or if any step of the chart creation or file writing process fails.
+func CreateGoScatterChart(config GoScatterChartConfig) error {
+	if len(config.Data) == 0 {
+		return fmt.Errorf("no data to render in scatter chart '%s'", config.ChartTitle.Text)
+	}
+
+	xValues := make([]float64, len(config.Data))
+	yValues := make([]float64, len(config.Data))
+	colors := make([]drawing.Color, len(config.Data))
+	var annotations []chart.Value2
+	maxX, maxY := 1.0, 1.0
+	for i, d := range config.Data {
+		xValues[i], yValues[i] = d.X, d.Y
+		colors[i] = drawing.ColorFromHex(scatterPointColor(d, i))
+		maxX, maxY = max(maxX, d.X), max(maxY, d.Y)
+		if d.Label != "" {
+			annotations = append(annotations, chart.Value2{XValue: d.X, YValue: d.Y, Label: d.Label})
+		}
+	}
+
+	series := []chart.Series{
+		chart.ContinuousSeries{
+			Style: chart.Style{
+				StrokeWidth: chart.Disabled,
+				DotWidth:    config.DotSize,
+				DotColorProvider: func(_, _ chart.Range, index int, _, _ float64) drawing.Color {
+					return colors[index]
+				},
+			},
+			XValues: xValues,
+			YValues: yValues,
+		},
+	}
+	if len(annotations) > 0 {
+		series = append(series, chart.AnnotationSeries{
+			Style: chart.Style{
+				FontSize:    config.LabelConfig.FontSize - 2,
+				FontColor:   drawing.ColorFromHex(config.LabelConfig.ColorHex),
+				StrokeColor: drawing.ColorFromHex(defaultGoChart.AxisColor),
+				FillColor:   drawing.ColorFromHex(config.ChartAppearance.BackgroundColorHex),
+			},
+			Annotations: annotations,
+		})
+	}
+
+	legendData := scatterLegendData(config.Data)
+	axisStyle := createAxisStyle(config.LabelConfig)
+	scatterChart := chart.Chart{
+		Title: config.ChartTitle.Text,
+		TitleStyle: chart.Style{
+			FontSize:  config.ChartTitle.FontSize,
+			FontColor: drawing.ColorFromHex(config.ChartTitle.ColorHex),
+			Padding:   chart.Box{Top: config.ChartTitle.Margin - int(config.ChartTitle.FontSize)},
+		},
+		Width:  config.ChartAppearance.Width,
+		Height: config.ChartAppearance.Height,
+		Background: chart.Style{
+			FillColor: drawing.ColorFromHex(config.ChartAppearance.BackgroundColorHex),
+			Padding: chart.Box{
+				Top:    config.ChartAppearance.Padding.Top,
+				Bottom: config.ChartAppearance.Padding.Bottom,
+				Left:   config.ChartAppearance.Padding.Left,
+				Right:  config.ChartAppearance.Padding.Right,
+			},
+		},
+		XAxis: chart.XAxis{
+			Name:      config.XAxisName,
+			NameStyle: axisStyle,
+			Style:     axisStyle,
+			Range:     &chart.ContinuousRange{Min: 0, Max: maxX * 1.05},
+		},
+		YAxis: chart.YAxis{
+			Name:      config.YAxisName,
+			NameStyle: axisStyle,
+			Style:     axisStyle,
+			Range:     &chart.ContinuousRange{Min: 0, Max: maxY * 1.05},
+		},
+		Series: series,
+		Elements: []chart.Renderable{
+			func(r chart.Renderer, _ chart.Box, _ chart.Style) {
+				renderLegend(r, legendData, config.LabelConfig, config.LegendConfig)
+			},
+		},
+	}
+
+	file, err := os.Create(config.OutputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create output file '%s': %w", config.OutputPath, err)
+	}
+	defer file.Close()
+
+	return scatterChart.Render(chart.SVG, file)
+}
+
+// scatterLegendData returns one legend entry per group, in order of first appearance.
+func scatterLegendData(data []ScatterPointData) []PieChartData {
+	var legend []PieChartData
+	seen := make(map[string]bool)
+	for i, d := range data {
+		if d.Group == "" || seen[d.Group] {
+			continue
+		}
+		seen[d.Group] = true
+		legend = append(legend, PieChartData{Label: d.Group, ColorHex: scatterPointColor(d, i)})
+	}
+	return legend
+}
+
+// scatterPointColor returns the color of a point, or a default color if none is provided.
+func scatterPointColor(d ScatterPointData, index int) string {
+	return getColorOrDefault(d.ColorHex, defaultColors[index%len(defaultColors)])
+}
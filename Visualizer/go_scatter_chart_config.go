@@ -0,0 +1,74 @@
+package Visualizer
+
+import "strings"
+
+// GoScatterChartConfig holds all the configuration settings for a scatter chart.
+type GoScatterChartConfig struct {
+	ChartAppearance GoChartAppearance   // Overall appearance settings
+	ChartTitle      GoChartTitle        // Title configuration
+	LabelConfig     GoChartLabelConfig  // Axis and legend label appearance
+	LegendConfig    GoChartLegendConfig // Legend structure and positioning
+	XAxisName       string
+	YAxisName       string
+	DotSize         float64 // Diameter of a point
+	Data            []ScatterPointData
+	OutputPath      string
+}
+
+// BuildGoScatterChartConfig is a constructor function that creates a GoScatterChartConfig based on the provided parameters.
+// The legend lists the groups of the points and is placed below the plot.
+//
+// Args:
+//
+//	title: The title of the chart.
+//	xAxisName: The name of the X axis.
+//	yAxisName: The name of the Y axis.
+//	data: A slice of ScatterPointData, one entry per point.
+//	width: The desired width of the chart.
+//	height: The desired height of the chart (excluding the legend).
+//	outputPath: The path where the generated chart image will be saved.
+//
+// Returns:
+//
+//	A configured GoScatterChartConfig struct.
+func BuildGoScatterChartConfig(
+	title string,
+	xAxisName string,
+	yAxisName string,
+	data []ScatterPointData,
+	width int,
+	height int,
+	outputPath string,
+) GoScatterChartConfig {
+	titleHeight := defaultGoChart.TitleHeight
+	titleMargin := defaultGoChart.TitleMargin
+
+	if strings.TrimSpace(title) == "" {
+		titleHeight = 0
+		titleMargin = 0
+	}
+
+	columnCount := max(width/defaultGoChart.LegendItemWidth, 1)
+	rowCount := calculateRowCount(len(scatterLegendData(data)), columnCount)
+	legendHeight := rowCount*defaultGoChart.LegendRowSpacing + 2*defaultGoChart.LegendPadding
+	totalHeight := height + titleHeight + legendHeight
+
+	chartAppearance := createDefaultChartAppearance(width, totalHeight, Padding{
+		Left:   defaultGoChart.Padding,
+		Top:    titleHeight,
+		Right:  defaultGoChart.Padding,
+		Bottom: legendHeight,
+	})
+
+	return GoScatterChartConfig{
+		ChartAppearance: chartAppearance,
+		ChartTitle:      createDefaultChartTitle(title, titleMargin),
+		LabelConfig:     createDefaultLabelConfig(),
+		LegendConfig:    createBottomLegendConfig(columnCount, rowCount, totalHeight, legendHeight),
+		XAxisName:       xAxisName,
+		YAxisName:       yAxisName,
+		DotSize:         defaultGoChart.DotSize,
+		Data:            data,
+		OutputPath:      outputPath,
+	}
+}
@@ -36,3 +36,40 @@ func BuildMermaidPieChartConfig(
 		OutputPath: outputPath,
 	}
 }
+
+// MermaidFlowchartConfig defines the structure for configuring a Mermaid flowchart.
+type MermaidFlowchartConfig struct {
+	Title      MermaidTitle
+	Direction  string // LR, RL, TB or BT
+	Nodes      []GraphNode
+	Edges      []GraphEdge
+	OutputPath string
+}
+
+// BuildMermaidFlowchartConfig creates a Mermaid flowchart configuration.
+//
+// Args:
+//   - title: The text of the flowchart title.
+//   - nodes: The nodes of the graph.
+//   - edges: The edges between the nodes, referencing them by Id.
+//   - outputPath: The file path where the Mermaid configuration will be written.
+//
+// Returns:
+//   - MermaidFlowchartConfig: A configuration struct for a Mermaid flowchart.
+func BuildMermaidFlowchartConfig(
+	title string,
+	nodes []GraphNode,
+	edges []GraphEdge,
+	outputPath string,
+) MermaidFlowchartConfig {
+	return MermaidFlowchartConfig{
+		Title: MermaidTitle{
+			Text:     title,
+			FontSize: defaultMermaidChart.TitleFontSize,
+		},
+		Direction:  defaultMermaidChart.FlowchartDirection,
+		Nodes:      nodes,
+		Edges:      edges,
+		OutputPath: outputPath,
+	}
+}
@@ -2,12 +2,16 @@ package Visualizer
 
 // mermaidChartDefaults holds default configuration values for Mermaid charts.
 type mermaidChartDefaults struct {
-	TitleFontSize float64
+	TitleFontSize      float64
+	FlowchartDirection string
+	NodeColor          string
 }
 
 // defaultMermaidChart provides a default instance of mermaidChartDefaults.
 var defaultMermaidChart = mermaidChartDefaults{
-	TitleFontSize: 16,
+	TitleFontSize:      16,
+	FlowchartDirection: "LR",
+	NodeColor:          "#ECECFF",
 }
 
 // getMermaidTitleFontSizeOrDefault returns the provided title font size, or the default if the provided value is zero or negative.
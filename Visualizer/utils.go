@@ -46,3 +46,6 @@ func sortPieChartDataByValue(data []PieChartData) []PieChartData {
 
 	return sortedData
 }
+
+// nonIdentifierChars matches characters that are not allowed in graph node identifiers.
+var nonIdentifierChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
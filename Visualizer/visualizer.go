@@ -5,3 +5,35 @@ type PieChartData struct {
 	Value    float64
 	ColorHex string
 }
+
+type BarChartData struct {
+	Label    string
+	Value    float64
+	ColorHex string
+}
+
+type GraphNode struct {
+	Id       string
+	Label    string
+	ColorHex string
+}
+
+type GraphEdge struct {
+	From   string
+	To     string
+	Weight int // Shown as the edge label when greater than zero
+}
+
+type LineSeriesData struct {
+	Label    string
+	Values   []float64 // One value per X value of the chart
+	ColorHex string
+}
+
+type ScatterPointData struct {
+	Label    string // Shown next to the point if set
+	Group    string // Points of a group share a legend entry
+	X        float64
+	Y        float64
+	ColorHex string
+}
@@ -1,13 +1,24 @@
 package main
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"io/fs"
 	"log"
+	"net/url"
 	"os"
+	"os/signal"
+	"path"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	"statfiy/Analyzer"
 	"statfiy/ArgManager"
+	"statfiy/Database"
 	"statfiy/FileManager"
 	"statfiy/Visualizer"
 )
@@ -16,81 +27,1141 @@ import (
 // 1. Multiple root paths: `go run . -p /path1 -p /path2`
 // 2. Include comments: `go run . -ic -p /path`
 // 3. Specify output path: `go run . -op /output/path -p /path`
-// 4. Help message: `go run . -h`
+// 4. Detect copy-pasted code: `go run . -dc --clone-min-tokens 40 -p /path`
+// 5. Custom test file patterns: `go run . -tp "*_spec.lua" -tp "testdata/" -p /path`
+// 6. Custom long line limit: `go run . -mll 100 -p /path`
+// 7. Flag hard to maintain files: `go run . -mit 30 -p /path`
+// 8. Per-project reports for monorepos: `go run . -pr -p /path`
+// 9. Combined report of several roots: `go run . -ag -p /path1 -p /path2`
+// 10. Compare two roots or stored snapshots: `go run . diff -op /output/path /old/root /new/analyzed/root/data/snapshot.json`
+// 11. Analyze a release tag without checking it out: `go run . -r v1.0.0 -p /path/to/repo`
+// 12. Authorship, CODEOWNERS ownership and bus factors: `go run . -ow -p /path/to/repo`
+// 13. Files that change often and are complex: `go run . -hs --churn-days 180 -p /path/to/repo`
+// 14. Monthly history of a repository: `go run . history /path/to/repo`, or every 50th commit: `go run . history -n 50 /path/to/repo`
+// 15. Vendor source drops: `go run . -p drop.zip -p release.tar.gz`, or a tar stream: `tar c src | go run . -p -`
+// 16. Follow symlinks, but not into hidden directories or below 5 levels: `go run . -fsl -sh -md 5 -p /path`
+// 17. Analyze every file again instead of reusing cached results: `go run . -nc -p /path`
+// 18. Live reports while refactoring: `go run . watch --debounce 1s /path`
+// 19. Store a run in the database: `go run . --db -p /path`, and regenerate its reports later: `go run . --from-db -p /path`
+// 20. Use another database: `STATIFY_DB=/tmp/statify.db go run . -p /path` or `go run . --db-path /tmp/statify.db -p /path`
+// 21. Upgrade the database schema: `go run . db migrate`, and list the applied migrations: `go run . db status`
+// 22. Help message: `go run . -h`
+
+// store is the database of the result cache, --db and --from-db, nil if none of them is used.
+var store *Database.Store
+
+// resultCache reuses the analysis of files unchanged since the last run, nil with --no-cache.
+var resultCache Analyzer.ResultCache
+
+// cacheStats sums up how the files of all analyzed roots were served by resultCache.
+var cacheStats Analyzer.CacheStats
+
 func main() {
 	args, err := ArgManager.ParseArgs(os.Args)
 	if err != nil {
 		log.Fatalf("Error parsing arguments: %v", err)
 	}
 
-	// Set default output path or use the provided one
-	if !args.OutputPaths.IsSet || len(args.OutputPaths.Value) == 1 {
-		outputPath := "analyzed"
-		if len(args.OutputPaths.Value) == 1 {
-			outputPath = args.OutputPaths.Value[0]
-			args.OutputPaths.Value = []string{}
+	Analyzer.LineLengthLimit = args.MaxLineLength
+
+	// Only the help or the version was printed
+	if args.Command == "" && len(args.RootPaths) == 0 {
+		return
+	}
+
+	// Opening the result cache would already migrate the database
+	if args.Command == ArgManager.CommandDatabase {
+		runDatabase(args)
+		return
+	}
+
+	if !args.NoCache || args.SaveToDB || args.FromDB {
+		store, err = Database.Open(args.DatabasePath)
+		if err != nil {
+			if args.SaveToDB || args.FromDB {
+				log.Fatalf("Error opening the database: %v", err)
+			}
+			log.Printf("Error opening the database, analyzing all files: %v", err)
+		} else {
+			defer store.Close()
+		}
+	}
+
+	if !args.NoCache && store != nil {
+		cache, err := store.AnalysisCache()
+		if err != nil {
+			log.Printf("Error opening the analysis cache, analyzing all files: %v", err)
+		} else {
+			resultCache = cache
+			defer func() {
+				log.Printf("Cache: %d hits, %d misses, %d invalidations", cacheStats.Hits, cacheStats.Misses, cacheStats.Invalidations)
+			}()
+		}
+	}
+
+	switch args.Command {
+	case ArgManager.CommandDiff:
+		runDiff(args)
+		return
+	case ArgManager.CommandHistory:
+		runHistory(args)
+		return
+	case ArgManager.CommandWatch:
+		runWatch(args)
+		return
+	}
+
+	// Set default output path or use the provided one
+	var aggregateOutputPath string
+	if !args.OutputPaths.IsSet || len(args.OutputPaths.Value) == 1 {
+		outputPath := "analyzed"
+		if len(args.OutputPaths.Value) == 1 {
+			outputPath = args.OutputPaths.Value[0]
+			args.OutputPaths.Value = []string{}
+		}
+		aggregateOutputPath = filepath.Join(outputPath, "aggregate")
+
+		// With --aggregate, the per-root reports go to a separate subdirectory, so a root named "aggregate"
+		// can't collide with the combined report.
+		rootsOutputPath := outputPath
+		if args.Aggregate {
+			rootsOutputPath = filepath.Join(outputPath, "roots")
+		}
+
+		// Generate output paths for each root path
+		for _, rootPath := range args.RootPaths {
+			absPath, err := FileManager.GetAbsolutePath(rootPath)
+			if err != nil {
+				log.Fatalf("Invalid path '%s': %v", rootPath, err)
+			}
+
+			baseName := filepath.Base(absPath)
+			if rootPath == FileManager.StdinArchive {
+				baseName = "stdin"
+			}
+			args.OutputPaths.Value = append(args.OutputPaths.Value, filepath.Join(rootsOutputPath, baseName))
+		}
+	}
+
+	// Ensure the number of output paths matches the number of root paths
+	if len(args.OutputPaths.Value) != len(args.RootPaths) {
+		log.Fatal("If you provide more than one output path, the number of root paths and output paths must match.")
+	}
+	if aggregateOutputPath == "" {
+		aggregateOutputPath = filepath.Join(filepath.Dir(args.OutputPaths.Value[0]), "aggregate")
+	}
+	if args.Aggregate {
+		for _, outputPath := range args.OutputPaths.Value {
+			if filepath.Clean(outputPath) == aggregateOutputPath {
+				log.Fatalf("The output path '%s' is used by the aggregate report, choose another one.", outputPath)
+			}
+		}
+	}
+
+	// Process each root path with the corresponding output path
+	var roots []Analyzer.RootResults
+	for i, rootPath := range args.RootPaths {
+		outputPath := args.OutputPaths.Value[i]
+		absPath, err := FileManager.GetAbsolutePath(rootPath)
+		if err != nil {
+			log.Fatalf("Invalid path '%s': %v", rootPath, err)
+		}
+		results, err := processRoot(rootPath, absPath, outputPath, args)
+		if err != nil {
+			log.Fatalf("Error analyzing '%s': %v", rootPath, err)
+		}
+		roots = append(roots, Analyzer.RootResults{RootPath: absPath, Results: results})
+	}
+
+	if args.Aggregate {
+		createAggregateReport(Analyzer.MergeRootResults(roots), args.IncludeComment, aggregateOutputPath)
+	}
+}
+
+// processRoot analyzes a root path: an archive, the git revision given by --rev, or the working tree.
+// With --from-db, the reports are regenerated from the stored analysis instead.
+// Archives are analyzed as if they were unpacked at their absolute path.
+func processRoot(rootPath, absPath, outputBase string, args *ArgManager.Args) ([]Analyzer.AnalyzeFileResult, error) {
+	if args.FromDB {
+		if args.Revision != "" {
+			return nil, fmt.Errorf("--rev can't be used with --from-db")
+		}
+		return processStoredRoot(absPath, outputBase, args)
+	}
+
+	if rootPath == FileManager.StdinArchive || (FileManager.IsArchivePath(rootPath) && FileManager.IsFileExists(absPath)) {
+		if args.Revision != "" {
+			return nil, fmt.Errorf("--rev can't be used with the archive '%s'", rootPath)
+		}
+		archive, err := FileManager.OpenArchive(rootPath, absPath, FileManager.DefaultArchiveLimits)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open archive: %w", err)
+		}
+		return processPath(archive, absPath, "", outputBase, args)
+	}
+
+	if args.Revision == "" {
+		return processPath(FileManager.OSFS, absPath, "", outputBase, args)
+	}
+
+	tree, err := FileManager.OpenGitRevision(absPath, args.Revision)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open git revision: %w", err)
+	}
+	defer tree.Close()
+
+	return processPath(tree, absPath, tree.Commit, outputBase, args)
+}
+
+// processPath handles the analysis of a single root path read from fsys and returns its results.
+func processPath(fsys fs.FS, rootPath, revision, outputBase string, args *ArgManager.Args) ([]Analyzer.AnalyzeFileResult, error) {
+	analyzedFiles, collectStats, err := analyzeRoot(fsys, rootPath, args)
+	if err != nil {
+		return nil, err
+	}
+	if err := createReports(fsys, rootPath, revision, analyzedFiles, collectStats, outputBase, args); err != nil {
+		return nil, err
+	}
+	return analyzedFiles, nil
+}
+
+// createReports saves the run with --db and writes all reports of the analyzed files of a root.
+func createReports(fsys fs.FS, rootPath, revision string, analyzedFiles []Analyzer.AnalyzeFileResult, collectStats FileManager.CollectStats, outputBase string, args *ArgManager.Args) error {
+	goModule := Analyzer.ReadGoModulePathFS(fsys, rootPath)
+	if args.SaveToDB {
+		saveRun(rootPath, revision, goModule, analyzedFiles, collectStats, args)
+	}
+
+	createResultReports(rootPath, revision, goModule, analyzedFiles, collectOptions(args), collectStats, outputBase, args)
+
+	imagesPath := filepath.Join(outputBase, "images")
+	mdFilesPath := filepath.Join(outputBase, "mds")
+
+	if args.Projects {
+		createProjectReports(fsys, rootPath, analyzedFiles, args.IncludeComment, outputBase)
+	}
+
+	if args.DetectClones {
+		config := Analyzer.DuplicationConfig{MinTokens: args.CloneMinTokens}
+		duplication := Analyzer.DetectDuplicatesFS(fsys, analyzedFiles, config)
+		createDuplicationReport(rootPath, duplication, mdFilesPath)
+	}
+
+	if args.Ownership {
+		createOwnershipReport(fsys, rootPath, revision, analyzedFiles, mdFilesPath, imagesPath)
+	}
+
+	if args.Hotspots {
+		createHotspotReport(rootPath, revision, analyzedFiles, args.ChurnDays, mdFilesPath, imagesPath)
+	}
+
+	return nil
+}
+
+// storedRunOptions is stored as JSON in the Options of the runs saved to the database. The collection
+// stats are kept with the options so the regenerated files.md shows what the collection left out.
+type storedRunOptions struct {
+	Revision       string // Analyzed git revision, empty for the working tree
+	GoModule       string // Module path of the go.mod at the root, so the dependency graph needs no files
+	Collect        FileManager.CollectOptions
+	CollectStats   FileManager.CollectStats
+	IncludeComment bool
+	MaxLineLength  int
+	TestPatterns   []string
+}
+
+// saveRun stores the analyzed files of a root as a new run in the database and applies --db-keep.
+// Errors are logged, since the reports don't depend on the database.
+func saveRun(rootPath, revision, goModule string, analyzedFiles []Analyzer.AnalyzeFileResult, collectStats FileManager.CollectStats, args *ArgManager.Args) {
+	options, err := json.Marshal(storedRunOptions{
+		Revision:       revision,
+		GoModule:       goModule,
+		Collect:        collectOptions(args),
+		CollectStats:   collectStats,
+		IncludeComment: args.IncludeComment,
+		MaxLineLength:  args.MaxLineLength,
+		TestPatterns:   testPatterns(args),
+	})
+	if err != nil {
+		log.Printf("Error encoding the run options: %v", err)
+		return
+	}
+
+	commit := revision
+	if commit == "" {
+		// Not every root is in a git repository
+		commit, _ = FileManager.GitHeadCommit(rootPath)
+	}
+
+	run := Database.AnalysisRun{RootPath: rootPath, GitCommit: commit, Options: string(options), ToolVersion: ArgManager.Version}
+	run, stats, err := store.SaveAnalysis(run, analyzedFiles)
+	if err != nil {
+		log.Printf("Error saving the analysis to the database: %v", err)
+		return
+	}
+	log.Printf("Saved run %d: %d files in %v (%.0f files/s)", run.Id, stats.Rows, stats.Duration.Round(time.Millisecond), stats.RowsPerSecond())
+
+	if args.KeepRuns > 0 {
+		if _, err := store.DeleteRuns(Database.RetentionPolicy{KeepLast: args.KeepRuns}); err != nil {
+			log.Printf("Error deleting old runs from the database: %v", err)
+		}
+	}
+}
+
+// processStoredRoot regenerates the reports of a root from the latest run stored in the database with --db,
+// without reading the root. Reports that need the files themselves are skipped.
+func processStoredRoot(rootPath, outputBase string, args *ArgManager.Args) ([]Analyzer.AnalyzeFileResult, error) {
+	run, analyzedFiles, err := store.LoadAnalysis(rootPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the analysis from the database: %w", err)
+	}
+	if run.Id == 0 {
+		return nil, fmt.Errorf("no analysis of '%s' is stored in the database, analyze it with --db first", rootPath)
+	}
+	if args.Projects || args.DetectClones || args.Ownership || args.Hotspots {
+		log.Printf("The project, clone, ownership and hotspot reports need the files and are skipped with --from-db")
+	}
+
+	// The test patterns may differ from the stored run
+	Analyzer.ClassifyTestFiles(rootPath, analyzedFiles, testPatterns(args))
+
+	var options storedRunOptions
+	if err := json.Unmarshal([]byte(run.Options), &options); err != nil {
+		log.Printf("Error reading the options of run %d, the collection details are left out of files.md: %v", run.Id, err)
+	}
+	log.Printf("Regenerating the reports of '%s' from run %d of %s", rootPath, run.Id, run.CreatedAt.Format(time.DateTime))
+
+	createResultReports(rootPath, options.Revision, options.GoModule, analyzedFiles, options.Collect, options.CollectStats, outputBase, args)
+	return analyzedFiles, nil
+}
+
+// createResultReports writes the snapshot and the reports and charts computed from the analyzed files and the
+// Go module path of the root alone.
+func createResultReports(rootPath, revision, goModule string, analyzedFiles []Analyzer.AnalyzeFileResult, options FileManager.CollectOptions, collectStats FileManager.CollectStats, outputBase string, args *ArgManager.Args) {
+	imagesPath := filepath.Join(outputBase, "images")
+	mdFilesPath := filepath.Join(outputBase, "mds")
+	dataPath := filepath.Join(outputBase, "data")
+
+	createDirectoryOrExit(imagesPath)
+	createDirectoryOrExit(mdFilesPath)
+	createDirectoryOrExit(dataPath)
+
+	snapshot := Analyzer.NewSnapshot(rootPath, analyzedFiles)
+	snapshot.Revision = revision
+	if err := Analyzer.WriteSnapshot(filepath.Join(dataPath, "snapshot.json"), snapshot); err != nil {
+		log.Printf("Error writing snapshot: %v", err)
+	}
+
+	// Generate markdown report for analyzed files
+	createAnalysisReport(rootPath, options, collectStats, analyzedFiles, mdFilesPath)
+	createTestRatioReport(rootPath, analyzedFiles, args.IncludeComment, mdFilesPath, imagesPath)
+	createHygieneReport(analyzedFiles, mdFilesPath)
+	createQualityReport(rootPath, analyzedFiles, args.MIThreshold, mdFilesPath)
+	createNestingReport(rootPath, analyzedFiles, mdFilesPath)
+	createDependencyReport(Analyzer.BuildDependencyGraphWithModule(goModule, rootPath, analyzedFiles), mdFilesPath, dataPath)
+
+	createLanguageCharts(analyzedFiles, args.IncludeComment, mdFilesPath, imagesPath)
+}
+
+// createLanguageCharts generates the language distribution charts: two SVG pie charts and a Mermaid chart.
+func createLanguageCharts(analyzedFiles []Analyzer.AnalyzeFileResult, includeComment bool, mdDir, imagesDir string) {
+	// Calculate language distribution and generate charts
+	langDistributions := Analyzer.CalculateLanguagePercentages(analyzedFiles, includeComment)
+	chartData := buildChartData(langDistributions)
+
+	// Generate visual charts in multiple styles
+	generateChart("Language Distribution", chartData, imagesDir, 600, 400, Visualizer.LegendBottom, "go_chart_bottom_legend.svg")
+	generateChart("Language Distribution", chartData, imagesDir, 400, 500, Visualizer.LegendLeft, "go_chart_left_legend.svg")
+	generateMermaidChart(chartData, mdDir, "mermaid_chart.md")
+}
+
+// analyzeRoot collects, analyzes and classifies all files under a root path of fsys.
+// It also returns what the collection options left out.
+func analyzeRoot(fsys fs.FS, rootPath string, args *ArgManager.Args) ([]Analyzer.AnalyzeFileResult, FileManager.CollectStats, error) {
+	// Collect metadata for all files under the root path
+	files, collectStats, err := FileManager.CollectFilesMetadataWithOptionsFS(fsys, rootPath, collectOptions(args))
+	if err != nil {
+		return nil, collectStats, fmt.Errorf("failed to collect file metadata: %w", err)
+	}
+
+	// Run analysis on collected files, reusing the cached results of unchanged files
+	var analyzedFiles []Analyzer.AnalyzeFileResult
+	if resultCache != nil {
+		var stats Analyzer.CacheStats
+		analyzedFiles, stats, err = Analyzer.AnalyzeMultipleFilesCachedFS(fsys, files, resultCache)
+		cacheStats = cacheStats.Add(stats)
+	} else {
+		analyzedFiles, err = Analyzer.AnalyzeMultipleFilesFS(fsys, files)
+	}
+	if err != nil {
+		return nil, collectStats, fmt.Errorf("failed to analyze files: %w", err)
+	}
+
+	// Split files into test and production code
+	Analyzer.ClassifyTestFiles(rootPath, analyzedFiles, testPatterns(args))
+
+	return analyzedFiles, collectStats, nil
+}
+
+// testPatterns returns the test file patterns given on the command line, or the defaults.
+func testPatterns(args *ArgManager.Args) []string {
+	if args.TestPatterns.IsSet {
+		return args.TestPatterns.Value
+	}
+	return Analyzer.DefaultTestFilePatterns
+}
+
+// collectOptions returns the options of the file collection given on the command line.
+func collectOptions(args *ArgManager.Args) FileManager.CollectOptions {
+	return FileManager.CollectOptions{
+		FollowSymlinks: args.FollowSymlinks,
+		MaxDepth:       args.MaxDepth,
+		SkipHidden:     args.SkipHidden,
+		OneFileSystem:  args.OneFileSystem,
+	}
+}
+
+// runWatch analyzes a root, then watches it and regenerates files.md and the language charts after every
+// burst of changes. Only the paths reported by the watcher are collected and analyzed again.
+func runWatch(args *ArgManager.Args) {
+	if args.Revision != "" {
+		log.Fatalf("--rev can't be used with %s", ArgManager.CommandWatch)
+	}
+	absPath, err := FileManager.GetAbsolutePath(args.WatchPath)
+	if err != nil || !FileManager.IsDirExists(absPath) {
+		log.Fatalf("Invalid path '%s': not a directory", args.WatchPath)
+	}
+
+	outputBase := filepath.Join("analyzed", filepath.Base(absPath))
+	if args.OutputPaths.IsSet {
+		outputBase = args.OutputPaths.Value[0]
+	}
+	if resultCache == nil {
+		resultCache = Analyzer.MemoryCache{}
+	}
+
+	analyzedFiles, collectStats, err := analyzeRoot(FileManager.OSFS, absPath, args)
+	if err != nil {
+		log.Fatalf("Error analyzing '%s': %v", absPath, err)
+	}
+	if err := createReports(FileManager.OSFS, absPath, "", analyzedFiles, collectStats, outputBase, args); err != nil {
+		log.Fatalf("Error analyzing '%s': %v", absPath, err)
+	}
+	root := newWatchedRoot(absPath, analyzedFiles, collectStats)
+
+	watcher, err := FileManager.WatchDirectory(absPath, args.WatchInterval, args.WatchPoll)
+	if err != nil {
+		log.Fatalf("Error watching '%s': %v", absPath, err)
+	}
+	defer watcher.Close()
+	switch {
+	case watcher.NativeError != nil:
+		log.Printf("Polling '%s' every %v, inotify isn't available: %v", absPath, args.WatchInterval, watcher.NativeError)
+	case watcher.Polling:
+		log.Printf("Polling '%s' every %v", absPath, args.WatchInterval)
+	default:
+		log.Printf("Watching '%s'", absPath)
+	}
+
+	// The reports and the database may be inside the root, their changes mustn't trigger new runs
+	var ignored []string
+	for _, path := range []string{outputBase, args.DatabasePath, args.DatabasePath + "-wal", args.DatabasePath + "-shm", args.DatabasePath + "-journal"} {
+		if absIgnored, err := FileManager.GetAbsolutePath(path); err == nil {
+			ignored = append(ignored, absIgnored)
+		}
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	var debounce <-chan time.Time
+	changed := make(map[string]bool)
+	for {
+		select {
+		case path, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if isWithinAny(path, ignored) {
+				continue
+			}
+			changed[path] = true
+			debounce = time.After(args.WatchDebounce)
+		case err := <-watcher.Errors:
+			log.Printf("Error watching '%s': %v", absPath, err)
+		case <-debounce:
+			debounce = nil
+			refreshWatchReports(root, changed, outputBase, args)
+			changed = make(map[string]bool)
+		case <-interrupt:
+			return
+		}
+	}
+}
+
+// watchedRoot holds the analysis of a watched root between refreshes.
+type watchedRoot struct {
+	rootPath     string
+	results      map[string]Analyzer.AnalyzeFileResult // By file path
+	collectStats FileManager.CollectStats              // Of the last walk of the whole root
+}
+
+func newWatchedRoot(rootPath string, analyzedFiles []Analyzer.AnalyzeFileResult, collectStats FileManager.CollectStats) *watchedRoot {
+	root := &watchedRoot{rootPath: rootPath, collectStats: collectStats}
+	root.replaceAll(analyzedFiles)
+	return root
+}
+
+func (r *watchedRoot) replaceAll(analyzedFiles []Analyzer.AnalyzeFileResult) {
+	r.results = make(map[string]Analyzer.AnalyzeFileResult, len(analyzedFiles))
+	for _, result := range analyzedFiles {
+		r.results[result.FileMetadata.Path] = result
+	}
+}
+
+// sortedResults returns the results ordered by path.
+func (r *watchedRoot) sortedResults() []Analyzer.AnalyzeFileResult {
+	results := make([]Analyzer.AnalyzeFileResult, 0, len(r.results))
+	for _, result := range r.results {
+		results = append(results, result)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].FileMetadata.Path < results[j].FileMetadata.Path })
+	return results
+}
+
+// update replaces the results of the changed paths, files or directories, by a new analysis of what is there
+// now, drops the results of removed files from the cache, and returns the number of files analyzed again.
+// With --follow-symlinks or --one-file-system, a path may be reachable through several links or be a new mount
+// point, so the whole root is collected again, with unchanged files served by the cache.
+func (r *watchedRoot) update(changed map[string]bool, args *ArgManager.Args) (int, error) {
+	options := collectOptions(args)
+	if options.FollowSymlinks || options.OneFileSystem {
+		before := cacheStats
+		analyzedFiles, collectStats, err := analyzeRoot(FileManager.OSFS, r.rootPath, args)
+		if err != nil {
+			return 0, err
+		}
+		removed := make(map[string]bool, len(r.results))
+		for filePath := range r.results {
+			removed[filePath] = true
+		}
+		r.replaceAll(analyzedFiles)
+		r.collectStats = collectStats
+		for filePath := range r.results {
+			delete(removed, filePath)
+		}
+		r.evict(removed)
+		return cacheStats.Misses + cacheStats.Invalidations - before.Misses - before.Invalidations, nil
+	}
+
+	// Parents first, so paths inside a changed directory are collected with it
+	paths := make([]string, 0, len(changed))
+	for changedPath := range changed {
+		paths = append(paths, changedPath)
+	}
+	sort.Strings(paths)
+
+	removed := make(map[string]bool)
+	for filePath := range r.results {
+		if isWithinAny(filePath, paths) {
+			removed[filePath] = true
+			delete(r.results, filePath)
+		}
+	}
+
+	var files []FileManager.FileMetadata
+	var collected []string
+	for _, changedPath := range paths {
+		if isWithinAny(changedPath, collected) {
+			continue
+		}
+		// Links to directories aren't followed, like in the walk of the whole root
+		info, err := os.Lstat(changedPath)
+		if err != nil || (info.Mode()&fs.ModeSymlink != 0 && FileManager.IsDirExists(changedPath)) {
+			continue
+		}
+		pathOptions, selected := r.optionsBelow(changedPath, options)
+		if !selected {
+			continue
+		}
+		pathFiles, _, err := FileManager.CollectFilesMetadataWithOptions(changedPath, pathOptions)
+		if err != nil {
+			// Removed, or removed again before it could be read
+			continue
+		}
+		files = append(files, pathFiles...)
+		collected = append(collected, changedPath)
+	}
+
+	var analyzedFiles []Analyzer.AnalyzeFileResult
+	var err error
+	if resultCache != nil {
+		var stats Analyzer.CacheStats
+		analyzedFiles, stats, err = Analyzer.AnalyzeMultipleFilesCachedFS(FileManager.OSFS, files, resultCache)
+		cacheStats = cacheStats.Add(stats)
+	} else {
+		analyzedFiles, err = Analyzer.AnalyzeMultipleFilesFS(FileManager.OSFS, files)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to analyze files: %w", err)
+	}
+	Analyzer.ClassifyTestFiles(r.rootPath, analyzedFiles, testPatterns(args))
+
+	for _, result := range analyzedFiles {
+		r.results[result.FileMetadata.Path] = result
+		delete(removed, result.FileMetadata.Path)
+	}
+	r.evict(removed)
+	return len(analyzedFiles), nil
+}
+
+// optionsBelow returns the collection options for a changed path of the root, with MaxDepth counted from the
+// path, and whether the options select the path at all.
+func (r *watchedRoot) optionsBelow(changedPath string, options FileManager.CollectOptions) (FileManager.CollectOptions, bool) {
+	relativePath, err := FileManager.GetRelativePath(r.rootPath, changedPath)
+	if err != nil || relativePath == ".." || strings.HasPrefix(relativePath, ".."+string(filepath.Separator)) {
+		return options, false
+	}
+	if relativePath == "." {
+		return options, true
+	}
+
+	parts := strings.Split(relativePath, string(filepath.Separator))
+	if options.SkipHidden {
+		for _, part := range parts {
+			if strings.HasPrefix(part, ".") {
+				return options, false
+			}
+		}
+	}
+	if options.MaxDepth > 0 {
+		if len(parts) > options.MaxDepth {
+			return options, false
+		}
+		if FileManager.IsDirExists(changedPath) {
+			if len(parts) == options.MaxDepth {
+				return options, false
+			}
+			options.MaxDepth -= len(parts)
+		}
+	}
+	return options, true
+}
+
+// evict drops the cached results of removed files.
+func (r *watchedRoot) evict(removed map[string]bool) {
+	if resultCache == nil || len(removed) == 0 {
+		return
+	}
+	paths := make([]string, 0, len(removed))
+	for filePath := range removed {
+		paths = append(paths, filePath)
+	}
+	if err := resultCache.Remove(paths...); err != nil {
+		log.Printf("Error removing deleted files from the cache: %v", err)
+	}
+}
+
+// refreshWatchReports analyzes the changed paths of a watched root and regenerates files.md and the language charts.
+func refreshWatchReports(root *watchedRoot, changed map[string]bool, outputBase string, args *ArgManager.Args) {
+	reanalyzed, err := root.update(changed, args)
+	if err != nil {
+		// Usually a file removed during the analysis, whose removal triggers another run
+		log.Printf("Error analyzing '%s': %v", root.rootPath, err)
+		return
+	}
+
+	analyzedFiles := root.sortedResults()
+	mdFilesPath := filepath.Join(outputBase, "mds")
+	createAnalysisReport(root.rootPath, collectOptions(args), root.collectStats, analyzedFiles, mdFilesPath)
+	createLanguageCharts(analyzedFiles, args.IncludeComment, mdFilesPath, filepath.Join(outputBase, "images"))
+
+	log.Printf("Updated reports after %d changed paths: %d of %d files analyzed again", len(changed), reanalyzed, len(analyzedFiles))
+}
+
+// isWithinAny reports whether a path is one of the given paths or below one of them.
+func isWithinAny(path string, parents []string) bool {
+	for _, parent := range parents {
+		if relativePath, err := filepath.Rel(parent, path); err == nil && relativePath != ".." && !strings.HasPrefix(relativePath, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// runDatabase migrates the database or prints its schema status.
+func runDatabase(args *ArgManager.Args) {
+	open := Database.Open
+	if args.DatabaseAction == ArgManager.DatabaseStatus {
+		// The status doesn't create or change the database
+		open = Database.OpenReadOnly
+	}
+	store, err := open(args.DatabasePath)
+	if err != nil {
+		log.Fatalf("Error opening the database: %v", err)
+	}
+	defer store.Close()
+
+	if args.DatabaseAction == ArgManager.DatabaseMigrate {
+		applied, err := store.Migrate()
+		for _, migration := range applied {
+			fmt.Printf("Applied migration %d: %s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			log.Fatalf("Error migrating the database: %v", err)
+		}
+		if len(applied) == 0 {
+			fmt.Printf("%s is up to date\n", store.Path())
+		}
+		return
+	}
+
+	status, err := store.SchemaStatus()
+	if err != nil {
+		log.Fatalf("Error reading the schema status: %v", err)
+	}
+	current, pending := 0, 0
+	fmt.Printf("Database: %s\n\n", store.Path())
+	fmt.Printf("%-8s %-24s %s\n", "Version", "Name", "Applied")
+	for _, migration := range status {
+		applied := "pending"
+		if migration.Legacy {
+			applied = "before migrations"
+			current = migration.Version
+		} else if migration.Applied() {
+			applied = migration.AppliedAt.Format(time.DateTime)
+			current = migration.Version
+		} else {
+			pending++
+		}
+		fmt.Printf("%-8d %-24s %s\n", migration.Version, migration.Name, applied)
+	}
+	fmt.Printf("\nSchema version %d, %d pending migrations\n", current, pending)
+}
+
+// runDiff compares two roots or snapshot files and writes the diff report.
+func runDiff(args *ArgManager.Args) {
+	outputBase := filepath.Join("analyzed", "diff")
+	if args.OutputPaths.IsSet {
+		outputBase = args.OutputPaths.Value[0]
+	}
+
+	before := loadSnapshot(args.DiffPaths[0], args)
+	after := loadSnapshot(args.DiffPaths[1], args)
+	createDiffReport(Analyzer.DiffSnapshots(before, after), before, after, outputBase)
+}
+
+// loadSnapshot reads a snapshot file, or analyzes the path if it's a directory.
+func loadSnapshot(path string, args *ArgManager.Args) Analyzer.Snapshot {
+	absPath, err := FileManager.GetAbsolutePath(path)
+	if err != nil {
+		log.Fatalf("Invalid path '%s': %v", path, err)
+	}
+
+	if FileManager.IsFileExists(absPath) {
+		snapshot, err := Analyzer.ReadSnapshot(absPath)
+		if err != nil {
+			log.Fatalf("Error reading snapshot: %v", err)
+		}
+		return snapshot
+	}
+	if !FileManager.IsDirExists(absPath) {
+		log.Fatalf("Invalid path '%s': neither a snapshot file nor a directory", path)
+	}
+
+	results, _, err := analyzeRoot(FileManager.OSFS, absPath, args)
+	if err != nil {
+		log.Fatalf("Error analyzing '%s': %v", absPath, err)
+	}
+	return Analyzer.NewSnapshot(absPath, results)
+}
+
+// createDiffReport writes a markdown report and delta charts of the differences between two snapshots.
+func createDiffReport(diff Analyzer.SnapshotDiff, before, after Analyzer.Snapshot, outputBase string) {
+	imagesPath := filepath.Join(outputBase, "images")
+	mdFilesPath := filepath.Join(outputBase, "mds")
+	createDirectoryOrExit(imagesPath)
+	createDirectoryOrExit(mdFilesPath)
+
+	var builder strings.Builder
+	builder.WriteString("## Summary\n\n")
+	builder.WriteString(fmt.Sprintf("- Before: `%v` (%v)\n", before.RootPath, before.CreatedAt.Format("2006-01-02 15:04")))
+	builder.WriteString(fmt.Sprintf("- After: `%v` (%v)\n\n", after.RootPath, after.CreatedAt.Format("2006-01-02 15:04")))
+	builder.WriteString("| Added | Removed | Changed | Unchanged |\n")
+	builder.WriteString("|---|---|---|---|\n")
+	builder.WriteString(fmt.Sprintf("| %v | %v | %v | %v |\n", diff.Added, diff.Removed, diff.Changed, diff.Unchanged))
+
+	builder.WriteString("\n## Languages\n\n")
+	builder.WriteString("| Language | Files | Code Size | Code Δ | Comment Δ | Blank Lines Δ |\n")
+	builder.WriteString("|---|---|---|---|---|---|\n")
+	for _, delta := range diff.Languages {
+		builder.WriteString(fmt.Sprintf("| %v | %v → %v | %v → %v | %+d | %+d | %+d |\n",
+			delta.Language, delta.FilesBefore, delta.FilesAfter, delta.CodeSizeBefore, delta.CodeSizeAfter,
+			delta.CodeSizeDelta, delta.CommentSizeDelta, delta.BlankLinesDelta))
+	}
+
+	builder.WriteString("\n## Files\n\n")
+	builder.WriteString("| File | Change | Language | Code Δ | Comment Δ | Blank Lines Δ |\n")
+	builder.WriteString("|---|---|---|---|---|---|\n")
+	for _, file := range diff.Files {
+		builder.WriteString(fmt.Sprintf("| %v | %v | %v | %+d | %+d | %+d |\n",
+			file.Path, file.Change, file.Language, file.CodeSizeDelta, file.CommentSizeDelta, file.BlankLinesDelta))
+	}
+
+	if err := FileManager.OverwriteFileString(filepath.Join(mdFilesPath, "diff.md"), builder.String()); err != nil {
+		log.Printf("Error writing diff report: %v", err)
+	}
+
+	var codeData, commentData []Visualizer.BarChartData
+	for _, delta := range diff.Languages {
+		if delta.CodeSizeDelta != 0 {
+			codeData = append(codeData, Visualizer.BarChartData{Label: delta.Language.String(), Value: float64(delta.CodeSizeDelta), ColorHex: delta.Language.GetColor()})
+		}
+		if delta.CommentSizeDelta != 0 {
+			commentData = append(commentData, Visualizer.BarChartData{Label: delta.Language.String(), Value: float64(delta.CommentSizeDelta), ColorHex: delta.Language.GetColor()})
+		}
+	}
+	generateBarChart("Code Size Change by Language", codeData, "", imagesPath, 600, 400, "code_delta.svg")
+	generateBarChart("Comment Size Change by Language", commentData, "", imagesPath, 600, 400, "comment_delta.svg")
+}
+
+// runHistory analyzes sampled commits of a repository and reports the lines per language over time.
+// Snapshots are stored per commit in data/snapshots and reused by later runs.
+func runHistory(args *ArgManager.Args) {
+	repoPath, err := FileManager.GetAbsolutePath(args.HistoryPath)
+	if err != nil {
+		log.Fatalf("Invalid path '%s': %v", args.HistoryPath, err)
+	}
+
+	outputBase := filepath.Join("analyzed", "history", filepath.Base(repoPath))
+	if args.OutputPaths.IsSet {
+		outputBase = args.OutputPaths.Value[0]
+	}
+	revision := "HEAD"
+	if args.Revision != "" {
+		revision = args.Revision
+	}
+
+	commits, err := FileManager.ListGitCommits(repoPath, revision)
+	if err != nil {
+		log.Fatalf("Error reading git history: %v", err)
+	}
+	if args.HistoryEvery > 0 {
+		commits = FileManager.SampleCommitsEvery(commits, args.HistoryEvery)
+	} else if commits, err = FileManager.SampleCommitsByPeriod(commits, FileManager.SamplePeriod(args.HistoryPeriod)); err != nil {
+		log.Fatalf("Error sampling git history: %v", err)
+	}
+
+	snapshotsPath := filepath.Join(outputBase, "data", "snapshots")
+	createDirectoryOrExit(snapshotsPath)
+
+	var snapshots []Analyzer.Snapshot
+	for i, commit := range commits {
+		log.Printf("Analyzing commit %d/%d %.8s (%v)", i+1, len(commits), commit.Hash, commit.Time.Format("2006-01-02"))
+		snapshot, err := loadCommitSnapshot(repoPath, commit, snapshotsPath, args)
+		if err != nil {
+			log.Fatalf("Error analyzing commit %.8s: %v", commit.Hash, err)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	createHistoryReport(Analyzer.BuildHistory(snapshots, args.IncludeComment), outputBase)
+}
+
+// analysisOptionsKey returns a short hash of the options changing the analysis results, so stored snapshots
+// are only reused by runs with the same options.
+func analysisOptionsKey(args *ArgManager.Args) string {
+	options, _ := json.Marshal(struct {
+		Collect      FileManager.CollectOptions
+		TestPatterns []string
+	}{collectOptions(args), testPatterns(args)})
+	sum := sha256.Sum256(options)
+	return hex.EncodeToString(sum[:6])
+}
+
+// loadCommitSnapshot reads the stored snapshot of a commit, or analyzes the commit and stores its snapshot.
+func loadCommitSnapshot(repoPath string, commit FileManager.GitCommit, snapshotsDir string, args *ArgManager.Args) (Analyzer.Snapshot, error) {
+	snapshotPath := filepath.Join(snapshotsDir, commit.Hash+"-"+analysisOptionsKey(args)+".json")
+	if FileManager.IsFileExists(snapshotPath) {
+		snapshot, err := Analyzer.ReadSnapshot(snapshotPath)
+		if err == nil && snapshot.Revision == commit.Hash && snapshot.RootPath == repoPath {
+			return snapshot, nil
+		}
+	}
+
+	tree, err := FileManager.OpenGitRevision(repoPath, commit.Hash)
+	if err != nil {
+		return Analyzer.Snapshot{}, fmt.Errorf("failed to open git revision: %w", err)
+	}
+	defer tree.Close()
+
+	// A subdirectory of the repository may not exist yet in early commits
+	var results []Analyzer.AnalyzeFileResult
+	if _, err := fs.Stat(tree, repoPath); err == nil {
+		if results, _, err = analyzeRoot(tree, repoPath, args); err != nil {
+			return Analyzer.Snapshot{}, err
+		}
+	}
+
+	snapshot := Analyzer.NewSnapshot(repoPath, results)
+	snapshot.Revision = commit.Hash
+	snapshot.CreatedAt = commit.Time
+	if err := Analyzer.WriteSnapshot(snapshotPath, snapshot); err != nil {
+		log.Printf("Error writing snapshot: %v", err)
+	}
+	return snapshot, nil
+}
+
+// createHistoryReport writes the history table, its CSV and the line and stacked area charts.
+// The charts and the table show the largest languages and sum up the rest as "Other".
+func createHistoryReport(history Analyzer.History, outputBase string) {
+	imagesPath := filepath.Join(outputBase, "images")
+	mdFilesPath := filepath.Join(outputBase, "mds")
+	dataPath := filepath.Join(outputBase, "data")
+	createDirectoryOrExit(imagesPath)
+	createDirectoryOrExit(mdFilesPath)
+	createDirectoryOrExit(dataPath)
+
+	if err := Analyzer.WriteHistoryCSV(filepath.Join(dataPath, "history.csv"), history); err != nil {
+		log.Printf("Error writing history CSV: %v", err)
+	}
+
+	const maxLanguages = 8
+	shown := history.Languages[:min(len(history.Languages), maxLanguages)]
+	hasOther := len(history.Languages) > len(shown)
+
+	xValues := make([]time.Time, len(history.Points))
+	series := make([]Visualizer.LineSeriesData, len(shown))
+	for i, lang := range shown {
+		series[i] = Visualizer.LineSeriesData{Label: lang.String(), ColorHex: lang.GetColor(), Values: make([]float64, len(history.Points))}
+	}
+	other := Visualizer.LineSeriesData{Label: "Other", ColorHex: "#999999", Values: make([]float64, len(history.Points))}
+
+	var builder strings.Builder
+	builder.WriteString("## Lines per Language\n\n")
+	builder.WriteString("| Date | Commit | Total |")
+	for _, lang := range shown {
+		builder.WriteString(fmt.Sprintf(" %v |", lang))
+	}
+	if hasOther {
+		builder.WriteString(" Other |")
+	}
+	builder.WriteString("\n|---|---|---|" + strings.Repeat("---|", len(series)))
+	if hasOther {
+		builder.WriteString("---|")
+	}
+	builder.WriteString("\n")
+
+	for i, point := range history.Points {
+		xValues[i] = point.Time
+		total := 0
+		for _, lines := range point.Lines {
+			total += lines
+		}
+		other.Values[i] = float64(total)
+
+		builder.WriteString(fmt.Sprintf("| %v | %.8s | %v |", point.Time.Format("2006-01-02"), point.Commit, total))
+		for j, lang := range shown {
+			series[j].Values[i] = float64(point.Lines[lang])
+			other.Values[i] -= float64(point.Lines[lang])
+			builder.WriteString(fmt.Sprintf(" %v |", point.Lines[lang]))
+		}
+		if hasOther {
+			builder.WriteString(fmt.Sprintf(" %.0f |", other.Values[i]))
+		}
+		builder.WriteString("\n")
+	}
+	if hasOther {
+		series = append(series, other)
+	}
+
+	if err := FileManager.OverwriteFileString(filepath.Join(mdFilesPath, "history.md"), builder.String()); err != nil {
+		log.Printf("Error writing history report: %v", err)
+	}
+
+	generateLineChart("Lines per Language", xValues, series, false, imagesPath, 900, 450, "history_lines.svg")
+	generateLineChart("Lines per Language (Stacked)", xValues, series, true, imagesPath, 900, 450, "history_stacked.svg")
+}
+
+// createAggregateReport writes a combined summary of all roots with one column per root,
+// a language comparison table and charts of the merged results.
+func createAggregateReport(aggregate Analyzer.AggregateResult, includeComment bool, outputBase string) {
+	imagesPath := filepath.Join(outputBase, "images")
+	mdFilesPath := filepath.Join(outputBase, "mds")
+	createDirectoryOrExit(imagesPath)
+	createDirectoryOrExit(mdFilesPath)
+
+	rootNames := make([]string, len(aggregate.Roots))
+	summaries := make([]Analyzer.ResultSummary, len(aggregate.Roots))
+	for i, root := range aggregate.Roots {
+		rootNames[i] = filepath.Base(root.RootPath)
+		summaries[i] = Analyzer.SummarizeResults(root.Results)
+	}
+	combined := Analyzer.SummarizeResults(aggregate.Results)
+
+	var builder strings.Builder
+	builder.WriteString("## Summary\n\n")
+	builder.WriteString(fmt.Sprintf("| Metric | %v | Combined |\n", strings.Join(rootNames, " | ")))
+	builder.WriteString(fmt.Sprintf("|---|%v---|\n", strings.Repeat("---|", len(rootNames))))
+	summaryRows := []struct {
+		name  string
+		value func(summary Analyzer.ResultSummary) string
+	}{
+		{"Files", func(s Analyzer.ResultSummary) string { return fmt.Sprint(s.Files) }},
+		{"Test Files", func(s Analyzer.ResultSummary) string { return fmt.Sprint(s.TestFiles) }},
+		{"Languages", func(s Analyzer.ResultSummary) string { return fmt.Sprint(s.Languages) }},
+		{"Total Size", func(s Analyzer.ResultSummary) string { return fmt.Sprint(s.TotalSize) }},
+		{"Code Size", func(s Analyzer.ResultSummary) string { return fmt.Sprint(s.CodeSize) }},
+		{"Comment Size", func(s Analyzer.ResultSummary) string { return fmt.Sprint(s.CommentSize) }},
+		{"Blank Lines", func(s Analyzer.ResultSummary) string { return fmt.Sprint(s.BlankLines) }},
+		{"Avg Maintainability Index", func(s Analyzer.ResultSummary) string {
+			return fmt.Sprintf("%.1f", s.AverageMaintainabilityIndex)
+		}},
+	}
+	for _, row := range summaryRows {
+		cells := make([]string, len(summaries))
+		for i, summary := range summaries {
+			cells[i] = row.value(summary)
 		}
+		builder.WriteString(fmt.Sprintf("| %v | %v | %v |\n", row.name, strings.Join(cells, " | "), row.value(combined)))
+	}
+	builder.WriteString(fmt.Sprintf("\n%v files are contained in more than one root and counted once in the combined column.\n", aggregate.SharedFiles))
 
-		// Generate output paths for each root path
-		for _, rootPath := range args.RootPaths {
-			absPath, err := FileManager.GetAbsolutePath(rootPath)
-			if err != nil {
-				log.Fatalf("Invalid path '%s': %v", rootPath, err)
-			}
-
-			baseName := filepath.Base(absPath)
-			args.OutputPaths.Value = append(args.OutputPaths.Value, filepath.Join(outputPath, baseName))
+	builder.WriteString("\n## Languages\n\n")
+	builder.WriteString("Each cell shows `files / size`.\n\n")
+	builder.WriteString(fmt.Sprintf("| Language | %v | Combined | Share |\n", strings.Join(rootNames, " | ")))
+	builder.WriteString(fmt.Sprintf("|---|%v---|---|\n", strings.Repeat("---|", len(rootNames))))
+	percentages := Analyzer.CalculateLanguagePercentages(aggregate.Results, includeComment)
+	for _, comparison := range aggregate.CompareLanguages(includeComment) {
+		cells := make([]string, len(rootNames))
+		for i := range rootNames {
+			cells[i] = fmt.Sprintf("%v / %v", comparison.RootFiles[i], comparison.RootSizes[i])
 		}
+		builder.WriteString(fmt.Sprintf("| %v | %v | %v / %v | %.1f%% |\n",
+			comparison.Language, strings.Join(cells, " | "), comparison.TotalFiles, comparison.TotalSize, percentages[comparison.Language]))
 	}
 
-	// Ensure the number of output paths matches the number of root paths
-	if len(args.OutputPaths.Value) != len(args.RootPaths) {
-		log.Fatal("If you provide more than one output path, the number of root paths and output paths must match.")
+	if err := FileManager.OverwriteFileString(filepath.Join(mdFilesPath, "aggregate.md"), builder.String()); err != nil {
+		log.Printf("Error writing aggregate report: %v", err)
 	}
 
-	// Process each root path with the corresponding output path
-	for i, rootPath := range args.RootPaths {
-		outputPath := args.OutputPaths.Value[i]
-		absPath, err := FileManager.GetAbsolutePath(rootPath)
-		if err != nil {
-			log.Fatalf("Invalid path '%s': %v", rootPath, err)
+	chartData := buildChartData(percentages)
+	generateChart("Language Distribution", chartData, imagesPath, 600, 400, Visualizer.LegendBottom, "go_chart_bottom_legend.svg")
+	generateChart("Language Distribution", chartData, imagesPath, 400, 500, Visualizer.LegendLeft, "go_chart_left_legend.svg")
+	generateMermaidChart(chartData, mdFilesPath, "mermaid_chart.md")
+
+	rootData := make([]Visualizer.BarChartData, len(aggregate.Roots))
+	for i, root := range aggregate.Roots {
+		var size int64
+		for _, file := range root.Results {
+			size += file.Size(includeComment)
 		}
-		processPath(absPath, outputPath, args.IncludeComment)
+		rootData[i] = Visualizer.BarChartData{Label: rootNames[i], Value: float64(size)}
 	}
+	generateBarChart("Size by Root", rootData, "", imagesPath, 600, 400, "root_sizes.svg")
 }
 
-// processPath handles the analysis of a single root path.
-func processPath(rootPath, outputBase string, includeComment bool) {
+// createProjectReports detects the sub-projects of the root and writes a language breakdown and charts
+// for each of them, plus an index of all projects.
+func createProjectReports(fsys fs.FS, rootPath string, analyzedFiles []Analyzer.AnalyzeFileResult, includeComment bool, outputBase string) {
+	projects, err := FileManager.DetectProjectsFS(fsys, rootPath)
+	if err != nil {
+		log.Printf("Error detecting projects: %v", err)
+		return
+	}
 
-	imagesPath := filepath.Join(outputBase, "images")
-	mdFilesPath := filepath.Join(outputBase, "mds")
+	var builder strings.Builder
+	builder.WriteString("## Projects\n\n")
+	builder.WriteString("| Project | Manifests | Files | Size | Main Language | Report |\n")
+	builder.WriteString("|---|---|---|---|---|---|\n")
 
-	createDirectoryOrExit(imagesPath)
-	createDirectoryOrExit(mdFilesPath)
+	for _, group := range Analyzer.GroupResultsByProject(rootPath, projects, analyzedFiles) {
+		outputName := projectOutputName(rootPath, group.Project)
+		projectBase := filepath.Join(outputBase, "projects", outputName)
+		imagesPath := filepath.Join(projectBase, "images")
+		mdFilesPath := filepath.Join(projectBase, "mds")
+		createDirectoryOrExit(imagesPath)
+		createDirectoryOrExit(mdFilesPath)
 
-	// Collect metadata for all files under the root path
-	files, err := FileManager.CollectFilesMetadata(rootPath)
-	if err != nil {
-		log.Fatalf("Error collecting file metadata: %v", err)
+		createLanguageReport(group.Results, includeComment, mdFilesPath)
+		chartData := buildChartData(Analyzer.CalculateLanguagePercentages(group.Results, includeComment))
+		generateChart("Language Distribution", chartData, imagesPath, 600, 400, Visualizer.LegendBottom, "go_chart_bottom_legend.svg")
+		generateMermaidChart(chartData, mdFilesPath, "mermaid_chart.md")
+
+		var size int64
+		languageSizes := make(map[Analyzer.Language]int64)
+		for _, file := range group.Results {
+			size += file.Size(includeComment)
+			languageSizes[file.Language] += file.Size(includeComment)
+		}
+		mainLanguage := "-"
+		var mainLanguageSize int64 = -1
+		for lang, languageSize := range languageSizes {
+			if languageSize > mainLanguageSize || (languageSize == mainLanguageSize && lang.String() < mainLanguage) {
+				mainLanguage, mainLanguageSize = lang.String(), languageSize
+			}
+		}
+		manifests := "-"
+		if len(group.Project.Manifests) > 0 {
+			manifests = strings.Join(group.Project.Manifests, ", ")
+		}
+
+		builder.WriteString(fmt.Sprintf("| %v | %v | %v | %v | %v | [languages](../projects/%v/mds/languages.md) |\n",
+			group.Project.Name, manifests, len(group.Results), size, mainLanguage, url.PathEscape(outputName)))
+	}
+
+	if err := FileManager.OverwriteFileString(filepath.Join(outputBase, "mds", "projects.md"), builder.String()); err != nil {
+		log.Printf("Error writing projects report: %v", err)
 	}
+}
 
-	// Run analysis on collected files
-	analyzedFiles, err := Analyzer.AnalyzeMultipleFiles(files)
+// projectOutputName returns the name of the directory below `projects` holding the reports of a project.
+// It is the project's path relative to the root escaped into a single path segment, so no two projects share
+// a directory. The root itself is named "(root)", which the escaping never produces.
+func projectOutputName(rootPath string, project FileManager.Project) string {
+	relativePath, err := FileManager.GetRelativePath(rootPath, project.Path)
 	if err != nil {
-		log.Fatalf("Error analyzing files: %v", err)
+		relativePath = project.Path
 	}
+	if relativePath == "." {
+		return "(root)"
+	}
+	return url.PathEscape(filepath.ToSlash(relativePath))
+}
 
-	// Generate markdown report for analyzed files
-	createAnalysisReport(rootPath, analyzedFiles, mdFilesPath)
+// createLanguageReport generates a markdown file with the number of files and the size of each language.
+func createLanguageReport(analyzedFiles []Analyzer.AnalyzeFileResult, includeComment bool, outputDir string) {
+	files := make(map[Analyzer.Language]int)
+	sizes := make(map[Analyzer.Language]int64)
+	for _, file := range analyzedFiles {
+		files[file.Language]++
+		sizes[file.Language] += file.Size(includeComment)
+	}
+	percentages := Analyzer.CalculateLanguagePercentages(analyzedFiles, includeComment)
 
-	// Calculate language distribution and generate charts
-	langDistributions := Analyzer.CalculateLanguagePercentages(analyzedFiles, includeComment)
-	chartData := buildChartData(langDistributions)
+	languages := make([]Analyzer.Language, 0, len(files))
+	for lang := range files {
+		languages = append(languages, lang)
+	}
+	sort.Slice(languages, func(i, j int) bool {
+		if sizes[languages[i]] != sizes[languages[j]] {
+			return sizes[languages[i]] > sizes[languages[j]]
+		}
+		return languages[i].String() < languages[j].String()
+	})
 
-	// Generate visual charts in multiple styles
-	generateChart(chartData, imagesPath, 600, 400, Visualizer.LegendBottom, "go_chart_bottom_legend.svg")
-	generateChart(chartData, imagesPath, 400, 500, Visualizer.LegendLeft, "go_chart_left_legend.svg")
-	generateMermaidChart(chartData, mdFilesPath, "mermaid_chart.md")
+	var builder strings.Builder
+	builder.WriteString("## Languages\n\n")
+	builder.WriteString("| Language | Files | Size | Share |\n")
+	builder.WriteString("|---|---|---|---|\n")
+	for _, lang := range languages {
+		builder.WriteString(fmt.Sprintf("| %v | %v | %v | %.1f%% |\n", lang, files[lang], sizes[lang], percentages[lang]))
+	}
+
+	if err := FileManager.OverwriteFileString(filepath.Join(outputDir, "languages.md"), builder.String()); err != nil {
+		log.Printf("Error writing language report: %v", err)
+	}
 }
 
 // createDirectoryOrExit creates a directory, exiting on failure.
@@ -114,12 +1185,39 @@ func buildChartData(distributions map[Analyzer.Language]float64) []Visualizer.Pi
 }
 
 // createAnalysisReport generates a markdown file with metadata of analyzed files.
-func createAnalysisReport(root string, analyzedFiles []Analyzer.AnalyzeFileResult, outputDir string) {
+// The header records the collection options and what they left out.
+func createAnalysisReport(root string, options FileManager.CollectOptions, stats FileManager.CollectStats, analyzedFiles []Analyzer.AnalyzeFileResult, outputDir string) {
 	outputPath := filepath.Join(outputDir, "files.md")
 
+	header := fmt.Sprintf(`# Files
+
+| Property      | Value       |
+|---------------|-------------|
+| Root          | %v          |
+| Files         | %v          |
+| Collection    | %v          |
+| Symlink Cycles Skipped | %v |
+| Duplicate Links Skipped | %v |
+| Directories Beyond Max Depth | %v |
+| Hidden Entries Skipped | %v |
+| Other Filesystems Skipped | %v |
+| Unreadable Entries Skipped | %v |
+
+`,
+		root,
+		len(analyzedFiles),
+		options,
+		stats.SymlinkCycles,
+		stats.Duplicates,
+		stats.DepthLimited,
+		stats.Hidden,
+		stats.OtherFileSystems,
+		stats.Unreadable,
+	)
+
 	// Clear the file first
-	if err := FileManager.OverwriteFile(outputPath, nil); err != nil {
-		log.Printf("Error clearing output file: %v", err)
+	if err := FileManager.OverwriteFileString(outputPath, header); err != nil {
+		log.Printf("Error writing report header: %v", err)
 	}
 
 	for _, file := range analyzedFiles {
@@ -140,6 +1238,24 @@ func createAnalysisReport(root string, analyzedFiles []Analyzer.AnalyzeFileResul
 | Code Size     | %v          |
 | Comment Size  | %v          |
 | Blank Lines   | %v          |
+| Test File     | %v          |
+| Lines         | %v          |
+| Max Line Length | %v        |
+| Avg Line Length | %.1f      |
+| P95 Line Length | %v        |
+| Long Lines    | %v          |
+| Indentation   | %v          |
+| Trailing Whitespace Lines | %v |
+| Line Endings  | %v          |
+| Final Newline | %v          |
+| Code Lines    | %v          |
+| Complexity    | %v          |
+| Halstead Volume | %.1f      |
+| Halstead Difficulty | %.1f  |
+| Halstead Effort | %.1f      |
+| Maintainability Index | %.1f |
+| Max Nesting Depth | %v      |
+| Avg Nesting Depth | %.2f    |
 `,
 			filePath,
 			file.FileMetadata.Name,
@@ -149,6 +1265,24 @@ func createAnalysisReport(root string, analyzedFiles []Analyzer.AnalyzeFileResul
 			file.CodeSize,
 			file.CommentSize,
 			file.BlankLines,
+			file.IsTest,
+			file.LineStats.Lines,
+			file.LineStats.MaxLineLength,
+			file.LineStats.AverageLineLength,
+			file.LineStats.P95LineLength,
+			file.LineStats.LongLines,
+			file.LineStats.Indentation(),
+			file.LineStats.TrailingWhitespaceLines,
+			file.LineStats.LineEnding(),
+			!file.LineStats.MissingFinalNewline,
+			file.CodeLines,
+			file.Complexity,
+			file.Halstead.Volume(),
+			file.Halstead.Difficulty(),
+			file.Halstead.Effort(),
+			file.MaintainabilityIndex,
+			file.Nesting.MaxDepth,
+			file.Nesting.AverageDepth,
 		)
 
 		if err := FileManager.AppendFileString(outputPath, report); err != nil {
@@ -157,12 +1291,537 @@ func createAnalysisReport(root string, analyzedFiles []Analyzer.AnalyzeFileResul
 	}
 }
 
+// createTestRatioReport generates a markdown file and a chart comparing test code with production code.
+func createTestRatioReport(root string, analyzedFiles []Analyzer.AnalyzeFileResult, includeComment bool, mdDir, imagesDir string) {
+	outputPath := filepath.Join(mdDir, "tests.md")
+
+	byLanguage := Analyzer.CalculateTestRatiosByLanguage(analyzedFiles, includeComment)
+	byDirectory := Analyzer.CalculateTestRatiosByDirectory(root, analyzedFiles, includeComment)
+
+	languages := make([]Analyzer.Language, 0, len(byLanguage))
+	for lang := range byLanguage {
+		languages = append(languages, lang)
+	}
+	sort.Slice(languages, func(i, j int) bool {
+		a, b := byLanguage[languages[i]], byLanguage[languages[j]]
+		if a.TestShare() != b.TestShare() {
+			return a.TestShare() > b.TestShare()
+		}
+		return languages[i].String() < languages[j].String()
+	})
+
+	directories := make([]string, 0, len(byDirectory))
+	for directory := range byDirectory {
+		directories = append(directories, directory)
+	}
+	sort.Strings(directories)
+
+	var builder strings.Builder
+	builder.WriteString("## Test Ratio by Language\n\n")
+	writeTestRatioTable(&builder, "Language", len(languages), func(i int) (string, Analyzer.TestRatio) {
+		return languages[i].String(), byLanguage[languages[i]]
+	})
+
+	builder.WriteString("\n## Test Ratio by Directory\n\n")
+	writeTestRatioTable(&builder, "Directory", len(directories), func(i int) (string, Analyzer.TestRatio) {
+		return directories[i], byDirectory[directories[i]]
+	})
+
+	if err := FileManager.OverwriteFileString(outputPath, builder.String()); err != nil {
+		log.Printf("Error writing test ratio report: %v", err)
+	}
+
+	var chartData []Visualizer.BarChartData
+	for _, lang := range languages {
+		chartData = append(chartData, Visualizer.BarChartData{
+			Label:    lang.String(),
+			Value:    byLanguage[lang].TestShare(),
+			ColorHex: lang.GetColor(),
+		})
+	}
+	generateBarChart("Test Code Share by Language", chartData, "%", imagesDir, 600, 400, "test_ratio.svg")
+}
+
+// writeTestRatioTable writes one markdown table row per entry returned by row.
+func writeTestRatioTable(builder *strings.Builder, keyTitle string, count int, row func(i int) (string, Analyzer.TestRatio)) {
+	builder.WriteString(fmt.Sprintf("| %v | Test Files | Production Files | Test Size | Production Size | Test/Code Ratio | Test Share |\n", keyTitle))
+	builder.WriteString("|---|---|---|---|---|---|---|\n")
+	for i := 0; i < count; i++ {
+		key, ratio := row(i)
+		builder.WriteString(fmt.Sprintf("| %v | %v | %v | %v | %v | %.2f | %.1f%% |\n",
+			key,
+			ratio.TestFiles,
+			ratio.ProductionFiles,
+			ratio.TestSize,
+			ratio.ProductionSize,
+			ratio.Ratio(),
+			ratio.TestShare(),
+		))
+	}
+}
+
+// createHygieneReport generates a markdown file with line length, indentation and whitespace statistics per language.
+func createHygieneReport(analyzedFiles []Analyzer.AnalyzeFileResult, outputDir string) {
+	outputPath := filepath.Join(outputDir, "hygiene.md")
+
+	stats := Analyzer.CalculateLanguageLineStats(analyzedFiles)
+	languages := make([]Analyzer.Language, 0, len(stats))
+	for lang := range stats {
+		languages = append(languages, lang)
+	}
+	sort.Slice(languages, func(i, j int) bool {
+		return stats[languages[i]].Lines > stats[languages[j]].Lines
+	})
+
+	var builder strings.Builder
+	builder.WriteString("## Line Lengths\n\n")
+	builder.WriteString(fmt.Sprintf("Lines longer than %v characters count as long lines.\n\n", Analyzer.LineLengthLimit))
+	builder.WriteString("| Language | Files | Lines | Max | Average | P95 | Long Lines |\n")
+	builder.WriteString("|---|---|---|---|---|---|---|\n")
+	for _, lang := range languages {
+		s := stats[lang]
+		builder.WriteString(fmt.Sprintf("| %v | %v | %v | %v | %.1f | %v | %v |\n",
+			lang, s.Files, s.Lines, s.MaxLineLength, s.AverageLineLength, s.P95LineLength, s.LongLines))
+	}
+
+	builder.WriteString("\n## Whitespace\n\n")
+	builder.WriteString("| Language | Indentation | Tab Lines | Space Lines | Mixed Lines | Mixed Files | Trailing Whitespace | Line Endings | LF Lines | CRLF Lines | Mixed Ending Files | Missing Final Newline |\n")
+	builder.WriteString("|---|---|---|---|---|---|---|---|---|---|---|---|\n")
+	for _, lang := range languages {
+		s := stats[lang]
+		builder.WriteString(fmt.Sprintf("| %v | %v | %v | %v | %v | %v | %v | %v | %v | %v | %v | %v |\n",
+			lang,
+			s.Indentation(),
+			s.TabIndentedLines,
+			s.SpaceIndentedLines,
+			s.MixedIndentedLines,
+			s.MixedIndentationFiles,
+			s.TrailingWhitespaceLines,
+			s.LineEnding(),
+			s.LFLines,
+			s.CRLFLines,
+			s.MixedLineEndingFiles,
+			s.MissingFinalNewlineFiles,
+		))
+	}
+
+	if err := FileManager.OverwriteFileString(outputPath, builder.String()); err != nil {
+		log.Printf("Error writing hygiene report: %v", err)
+	}
+}
+
+// createQualityReport generates a markdown file with the maintainability index distribution per language
+// and the files below the threshold.
+func createQualityReport(root string, analyzedFiles []Analyzer.AnalyzeFileResult, threshold float64, outputDir string) {
+	outputPath := filepath.Join(outputDir, "quality.md")
+
+	distributions := Analyzer.CalculateMaintainabilityDistributions(analyzedFiles, threshold)
+	languages := make([]Analyzer.Language, 0, len(distributions))
+	for lang := range distributions {
+		languages = append(languages, lang)
+	}
+	sort.Slice(languages, func(i, j int) bool {
+		return distributions[languages[i]].Mean < distributions[languages[j]].Mean
+	})
+
+	var builder strings.Builder
+	builder.WriteString("## Maintainability by Language\n\n")
+	builder.WriteString("| Language | Files | Min MI | Median MI | Mean MI | Max MI | Avg Complexity | Avg Volume | Avg Difficulty | Avg Effort | Below Threshold |\n")
+	builder.WriteString("|---|---|---|---|---|---|---|---|---|---|---|\n")
+	for _, lang := range languages {
+		d := distributions[lang]
+		builder.WriteString(fmt.Sprintf("| %v | %v | %.1f | %.1f | %.1f | %.1f | %.1f | %.1f | %.1f | %.1f | %v |\n",
+			lang, d.Files, d.Min, d.Median, d.Mean, d.Max,
+			d.AverageComplexity, d.AverageVolume, d.AverageDifficulty, d.AverageEffort, d.BelowThreshold))
+	}
+
+	builder.WriteString(fmt.Sprintf("\n## Files Below Maintainability Index %.1f\n\n", threshold))
+	builder.WriteString("| File | Language | MI | Complexity | Code Lines | Volume |\n")
+	builder.WriteString("|---|---|---|---|---|---|\n")
+	for _, file := range Analyzer.FilesBelowMaintainability(analyzedFiles, threshold) {
+		filePath, err := FileManager.GetRelativePath(root, file.FileMetadata.Path)
+		if err != nil {
+			filePath = file.FileMetadata.Path
+		}
+		builder.WriteString(fmt.Sprintf("| %v | %v | %.1f | %v | %v | %.1f |\n",
+			filePath, file.Language, file.MaintainabilityIndex, file.Complexity, file.CodeLines, file.Halstead.Volume()))
+	}
+
+	if err := FileManager.OverwriteFileString(outputPath, builder.String()); err != nil {
+		log.Printf("Error writing quality report: %v", err)
+	}
+}
+
+// createNestingReport generates a markdown file with the nesting depth per language
+// and the most deeply nested files.
+func createNestingReport(root string, analyzedFiles []Analyzer.AnalyzeFileResult, outputDir string) {
+	outputPath := filepath.Join(outputDir, "nesting.md")
+
+	summaries := Analyzer.CalculateNestingByLanguage(analyzedFiles)
+	languages := make([]Analyzer.Language, 0, len(summaries))
+	for lang := range summaries {
+		languages = append(languages, lang)
+	}
+	sort.Slice(languages, func(i, j int) bool {
+		if summaries[languages[i]].MaxDepth != summaries[languages[j]].MaxDepth {
+			return summaries[languages[i]].MaxDepth > summaries[languages[j]].MaxDepth
+		}
+		return languages[i].String() < languages[j].String()
+	})
+
+	var builder strings.Builder
+	builder.WriteString("## Nesting Depth by Language\n\n")
+	builder.WriteString("| Language | Files | Max Depth | Avg Max Depth | Avg Depth |\n")
+	builder.WriteString("|---|---|---|---|---|\n")
+	for _, lang := range languages {
+		summary := summaries[lang]
+		builder.WriteString(fmt.Sprintf("| %v | %v | %v | %.2f | %.2f |\n",
+			lang, summary.Files, summary.MaxDepth, summary.AverageMaxDepth, summary.AverageDepth))
+	}
+
+	files := make([]Analyzer.AnalyzeFileResult, 0, len(analyzedFiles))
+	for _, file := range analyzedFiles {
+		if file.Nesting.MaxDepth > 0 {
+			files = append(files, file)
+		}
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].Nesting.MaxDepth > files[j].Nesting.MaxDepth
+	})
+
+	builder.WriteString("\n## Most Deeply Nested Files\n\n")
+	builder.WriteString("| File | Language | Max Depth | Avg Depth | Deepest Locations |\n")
+	builder.WriteString("|---|---|---|---|---|\n")
+	for _, file := range files {
+		filePath, err := FileManager.GetRelativePath(root, file.FileMetadata.Path)
+		if err != nil {
+			filePath = file.FileMetadata.Path
+		}
+
+		locations := make([]string, 0, len(file.Nesting.Deepest))
+		for _, location := range file.Nesting.Deepest {
+			locations = append(locations, fmt.Sprintf("%v-%v (%v)", location.StartLine, location.EndLine, location.Depth))
+		}
+		builder.WriteString(fmt.Sprintf("| %v | %v | %v | %.2f | %v |\n",
+			filePath, file.Language, file.Nesting.MaxDepth, file.Nesting.AverageDepth, strings.Join(locations, ", ")))
+	}
+
+	if err := FileManager.OverwriteFileString(outputPath, builder.String()); err != nil {
+		log.Printf("Error writing nesting report: %v", err)
+	}
+}
+
+// createDependencyReport writes the module dependency graph as a markdown table, a Mermaid flowchart,
+// a Graphviz DOT file and JSON.
+func createDependencyReport(graph Analyzer.DependencyGraph, mdDir, dataDir string) {
+	var builder strings.Builder
+	builder.WriteString("## Module Dependencies\n\n")
+	builder.WriteString("| Module | Files | Fan-In | Fan-Out | External Fan-Out |\n")
+	builder.WriteString("|---|---|---|---|---|\n")
+	for _, module := range graph.Modules {
+		builder.WriteString(fmt.Sprintf("| %v | %v | %v | %v | %v |\n",
+			module.Module, module.Files, module.FanIn, module.FanOut, module.ExternalFanOut))
+	}
+
+	builder.WriteString("\n## External Dependencies\n\n")
+	for _, name := range graph.External {
+		builder.WriteString(fmt.Sprintf("- `%v`\n", name))
+	}
+
+	if err := FileManager.OverwriteFileString(filepath.Join(mdDir, "dependencies.md"), builder.String()); err != nil {
+		log.Printf("Error writing dependency report: %v", err)
+	}
+
+	nodes, edges := buildGraphData(graph)
+	mermaidConfig := Visualizer.BuildMermaidFlowchartConfig("Module Dependencies", nodes, edges, filepath.Join(mdDir, "dependency_graph.md"))
+	if err := Visualizer.CreateMermaidFlowchart(mermaidConfig); err != nil {
+		log.Printf("Error generating Mermaid dependency graph: %v", err)
+	}
+
+	dotConfig := Visualizer.BuildDotGraphConfig("Module Dependencies", nodes, edges, filepath.Join(dataDir, "dependencies.dot"))
+	if err := Visualizer.CreateDotGraph(dotConfig); err != nil {
+		log.Printf("Error generating DOT dependency graph: %v", err)
+	}
+
+	data, err := json.MarshalIndent(graph, "", "  ")
+	if err != nil {
+		log.Printf("Error encoding dependency graph: %v", err)
+		return
+	}
+	if err := FileManager.OverwriteFile(filepath.Join(dataDir, "dependencies.json"), data); err != nil {
+		log.Printf("Error writing dependency graph: %v", err)
+	}
+}
+
+// buildGraphData converts a dependency graph into Visualizer nodes and edges.
+// Internal modules and external packages get different colors.
+func buildGraphData(graph Analyzer.DependencyGraph) ([]Visualizer.GraphNode, []Visualizer.GraphEdge) {
+	ids := make(map[string]string)
+	var nodes []Visualizer.GraphNode
+
+	for i, module := range graph.Modules {
+		ids[module.Module] = fmt.Sprintf("m%d", i)
+		nodes = append(nodes, Visualizer.GraphNode{Id: ids[module.Module], Label: module.Module, ColorHex: "#ECECFF"})
+	}
+	for i, name := range graph.External {
+		ids["external:"+name] = fmt.Sprintf("e%d", i)
+		nodes = append(nodes, Visualizer.GraphNode{Id: ids["external:"+name], Label: name, ColorHex: "#EEEEEE"})
+	}
+
+	edges := make([]Visualizer.GraphEdge, 0, len(graph.Edges))
+	for _, edge := range graph.Edges {
+		to := ids[edge.To]
+		if edge.External {
+			to = ids["external:"+edge.To]
+		}
+		edges = append(edges, Visualizer.GraphEdge{From: ids[edge.From], To: to, Weight: edge.Imports})
+	}
+
+	return nodes, edges
+}
+
+// createOwnershipReport writes the lines per author and CODEOWNERS owner, the files without an owner
+// and the bus factor per directory. The CODEOWNERS file is read from fsys, so it matches the analyzed revision.
+func createOwnershipReport(fsys fs.FS, rootPath, revision string, analyzedFiles []Analyzer.AnalyzeFileResult, mdDir, imagesDir string) {
+	repoRoot, err := FileManager.GitRepositoryRoot(rootPath)
+	if err != nil {
+		log.Printf("Skipping the ownership report: %v", err)
+		return
+	}
+	codeOwners, codeOwnersPath, err := FileManager.ReadCodeOwners(fsys, repoRoot)
+	if err != nil {
+		log.Printf("Error reading CODEOWNERS, files are reported without owners: %v", err)
+	}
+
+	report := Analyzer.AnalyzeOwnership(repoRoot, revision, analyzedFiles, codeOwners)
+	totalLines := 0
+	for _, author := range report.Authors {
+		totalLines += author.Lines
+	}
+
+	var builder strings.Builder
+	builder.WriteString("## Authors\n\n")
+	if revision != "" {
+		builder.WriteString(fmt.Sprintf("Blamed at commit `%v`.\n\n", revision))
+	}
+	writeOwnerStatsTable(&builder, "Author", report.Authors, totalLines)
+
+	builder.WriteString("\n## Owners\n\n")
+	if codeOwnersPath == "" {
+		builder.WriteString("No CODEOWNERS file found.\n")
+	} else {
+		relativePath, _ := FileManager.GetRelativePath(repoRoot, codeOwnersPath)
+		builder.WriteString(fmt.Sprintf("From `%v`. Files with several owners count for each of them.\n\n", filepath.ToSlash(relativePath)))
+		writeOwnerStatsTable(&builder, "Owner", report.Owners, totalLines)
+
+		builder.WriteString(fmt.Sprintf("\n### Files Without Owner (%v)\n\n", len(report.Unowned)))
+		for _, file := range report.Unowned {
+			builder.WriteString(fmt.Sprintf("- %v\n", file))
+		}
+	}
+
+	builder.WriteString("\n## Bus Factor\n\n")
+	builder.WriteString(fmt.Sprintf("Smallest number of authors who wrote more than half of the lines of a directory, including its subdirectories, up to %v levels deep.\n\n", Analyzer.MaxOwnershipDirectoryDepth))
+	builder.WriteString("| Directory | Lines | Authors | Bus Factor | Top Author | Top Author Share |\n")
+	builder.WriteString("|---|---|---|---|---|---|\n")
+	for _, dir := range report.Directories {
+		builder.WriteString(fmt.Sprintf("| %v | %v | %v | %v | %v | %.1f%% |\n", dir.Path, dir.Lines, dir.Authors, dir.BusFactor, dir.TopAuthor, dir.TopAuthorShare))
+	}
+
+	if len(report.Skipped) > 0 {
+		builder.WriteString(fmt.Sprintf("\n## Not Blamed (%v)\n\n", len(report.Skipped)))
+		builder.WriteString("Files git blame doesn't know, e.g. untracked files.\n\n")
+		for _, file := range report.Skipped {
+			builder.WriteString(fmt.Sprintf("- %v\n", file))
+		}
+	}
+
+	if err := FileManager.OverwriteFileString(filepath.Join(mdDir, "ownership.md"), builder.String()); err != nil {
+		log.Printf("Error writing ownership report: %v", err)
+	}
+
+	if totalLines > 0 {
+		var chartData []Visualizer.PieChartData
+		for _, author := range report.Authors {
+			share := float64(author.Lines) / float64(totalLines) * 100
+			chartData = append(chartData, Visualizer.PieChartData{Label: fmt.Sprintf("%v %.1f%%", author.Name, share), Value: share})
+		}
+		generateChart("Lines by Author", chartData, imagesDir, 600, 400, Visualizer.LegendBottom, "authors.svg")
+	}
+}
+
+// writeOwnerStatsTable writes the lines, files and top languages of authors or owners as a table.
+func writeOwnerStatsTable(builder *strings.Builder, nameTitle string, stats []Analyzer.OwnerStats, totalLines int) {
+	const maxLanguages = 3
+
+	builder.WriteString(fmt.Sprintf("| %v | Files | Lines | Share | Languages |\n", nameTitle))
+	builder.WriteString("|---|---|---|---|---|\n")
+	for _, stat := range stats {
+		languages := make([]Analyzer.Language, 0, len(stat.Languages))
+		for lang := range stat.Languages {
+			languages = append(languages, lang)
+		}
+		sort.Slice(languages, func(i, j int) bool {
+			a, b := languages[i], languages[j]
+			if stat.Languages[a] != stat.Languages[b] {
+				return stat.Languages[a] > stat.Languages[b]
+			}
+			return a.String() < b.String()
+		})
+
+		var languageShares []string
+		for _, lang := range languages[:min(len(languages), maxLanguages)] {
+			languageShares = append(languageShares, fmt.Sprintf("%v %.1f%%", lang, float64(stat.Languages[lang])/float64(max(stat.Lines, 1))*100))
+		}
+		if len(languages) > maxLanguages {
+			languageShares = append(languageShares, fmt.Sprintf("+%v more", len(languages)-maxLanguages))
+		}
+		if len(languageShares) == 0 {
+			languageShares = []string{"-"}
+		}
+
+		share := 0.0
+		if totalLines > 0 {
+			share = float64(stat.Lines) / float64(totalLines) * 100
+		}
+		builder.WriteString(fmt.Sprintf("| %v | %v | %v | %.1f%% | %v |\n", stat.Name, stat.Files, stat.Lines, share, strings.Join(languageShares, ", ")))
+	}
+}
+
+// createHotspotReport writes the files ranked by churn × complexity, the churn per language and
+// directory, and a scatter chart of commits against complexity with the top hotspots labeled.
+func createHotspotReport(rootPath, revision string, analyzedFiles []Analyzer.AnalyzeFileResult, churnDays int, mdDir, imagesDir string) {
+	const maxRows, maxLabels = 30, 10
+
+	repoRoot, err := FileManager.GitRepositoryRoot(rootPath)
+	if err != nil {
+		log.Printf("Skipping the hotspot report: %v", err)
+		return
+	}
+	churn, err := FileManager.ReadGitChurn(repoRoot, revision, churnDays)
+	if err != nil {
+		log.Printf("Skipping the hotspot report: %v", err)
+		return
+	}
+	report := Analyzer.BuildHotspotReport(repoRoot, analyzedFiles, churn.Files)
+
+	var builder strings.Builder
+	builder.WriteString("## Hotspots\n\n")
+	builder.WriteString(fmt.Sprintf("Churn from %v to %v (%v days). Score = commits × complexity.\n\n",
+		churn.Since.Format("2006-01-02"), churn.Until.Format("2006-01-02"), churnDays))
+	builder.WriteString("| Rank | File | Language | Commits | Lines Changed | Complexity | Code Lines | Score |\n")
+	builder.WriteString("|---|---|---|---|---|---|---|---|\n")
+	for i, hotspot := range report.Hotspots[:min(len(report.Hotspots), maxRows)] {
+		builder.WriteString(fmt.Sprintf("| %v | %v | %v | %v | %v | %v | %v | %.0f |\n",
+			i+1, hotspot.Path, hotspot.Language, hotspot.Commits, hotspot.LinesChanged, hotspot.Complexity, hotspot.CodeLines, hotspot.Score))
+	}
+
+	builder.WriteString("\n## Churn by Language\n\n")
+	writeChurnStatsTable(&builder, "Language", report.Languages)
+	builder.WriteString("\n## Churn by Directory\n\n")
+	writeChurnStatsTable(&builder, "Directory", report.Directories)
+
+	if err := FileManager.OverwriteFileString(filepath.Join(mdDir, "hotspots.md"), builder.String()); err != nil {
+		log.Printf("Error writing hotspot report: %v", err)
+	}
+
+	if len(report.Hotspots) == 0 {
+		return
+	}
+	data := make([]Visualizer.ScatterPointData, len(report.Hotspots))
+	for i, hotspot := range report.Hotspots {
+		data[i] = Visualizer.ScatterPointData{
+			Group:    hotspot.Language.String(),
+			X:        float64(hotspot.Commits),
+			Y:        float64(hotspot.Complexity),
+			ColorHex: hotspot.Language.GetColor(),
+		}
+		if i < maxLabels {
+			data[i].Label = path.Base(hotspot.Path)
+		}
+	}
+	config := Visualizer.BuildGoScatterChartConfig("Hotspots", "Commits", "Complexity", data, 800, 500, filepath.Join(imagesDir, "hotspots.svg"))
+	if err := Visualizer.CreateGoScatterChart(config); err != nil {
+		log.Printf("Error generating chart hotspots.svg: %v", err)
+	}
+}
+
+// writeChurnStatsTable writes the churn of languages or directories as a table.
+func writeChurnStatsTable(builder *strings.Builder, nameTitle string, stats []Analyzer.ChurnStats) {
+	builder.WriteString(fmt.Sprintf("| %v | Changed Files | Commits | Lines Added | Lines Deleted |\n", nameTitle))
+	builder.WriteString("|---|---|---|---|---|\n")
+	for _, stat := range stats {
+		builder.WriteString(fmt.Sprintf("| %v | %v | %v | %v | %v |\n", stat.Name, stat.Files, stat.Commits, stat.LinesAdded, stat.LinesDeleted))
+	}
+}
+
+// createDuplicationReport generates a markdown file listing duplication per language and all clone pairs.
+func createDuplicationReport(root string, report Analyzer.DuplicationReport, outputDir string) {
+	outputPath := filepath.Join(outputDir, "duplication.md")
+
+	var builder strings.Builder
+	builder.WriteString("## Duplication by Language\n\n")
+	builder.WriteString("| Language | Code Lines | Duplicated Lines | Duplication |\n")
+	builder.WriteString("|----------|------------|------------------|-------------|\n")
+
+	languages := make([]Analyzer.Language, 0, len(report.Languages))
+	for lang := range report.Languages {
+		languages = append(languages, lang)
+	}
+	sort.Slice(languages, func(i, j int) bool {
+		return report.Languages[languages[i]].Percentage > report.Languages[languages[j]].Percentage
+	})
+
+	for _, lang := range languages {
+		stats := report.Languages[lang]
+		builder.WriteString(fmt.Sprintf("| %v | %v | %v | %.1f%% |\n", lang, stats.TotalLines, stats.DuplicatedLines, stats.Percentage))
+	}
+
+	builder.WriteString("\n## Clone Pairs\n\n")
+	builder.WriteString("| Language | Tokens | First | Second |\n")
+	builder.WriteString("|----------|--------|-------|--------|\n")
+
+	for _, pair := range report.Pairs {
+		builder.WriteString(fmt.Sprintf("| %v | %v | %v | %v |\n",
+			pair.Language,
+			pair.Tokens,
+			formatCloneLocation(root, pair.First),
+			formatCloneLocation(root, pair.Second),
+		))
+	}
+
+	if len(report.Skipped) > 0 {
+		builder.WriteString(fmt.Sprintf("\n## Not Compared (%v)\n\n", len(report.Skipped)))
+		builder.WriteString("Files that couldn't be read.\n\n")
+		for _, file := range report.Skipped {
+			relativePath, err := FileManager.GetRelativePath(root, file)
+			if err != nil {
+				relativePath = file
+			}
+			builder.WriteString(fmt.Sprintf("- %v\n", relativePath))
+		}
+	}
+
+	if err := FileManager.OverwriteFileString(outputPath, builder.String()); err != nil {
+		log.Printf("Error writing duplication report: %v", err)
+	}
+}
+
+// formatCloneLocation renders a clone location as `relative/path:start-end`.
+func formatCloneLocation(root string, location Analyzer.CloneLocation) string {
+	filePath, err := FileManager.GetRelativePath(root, location.Path)
+	if err != nil {
+		filePath = location.Path
+	}
+	return fmt.Sprintf("%v:%v-%v", filePath, location.StartLine, location.EndLine)
+}
+
 // generateChart creates a Go-pie chart image based on the given data and config.
-func generateChart(data []Visualizer.PieChartData, outputDir string, width, height int, legend Visualizer.LegendPosition, filename string) {
+func generateChart(title string, data []Visualizer.PieChartData, outputDir string, width, height int, legend Visualizer.LegendPosition, filename string) {
 	outputPath := filepath.Join(outputDir, filename)
 
 	config := Visualizer.BuildGoChartConfig(
-		"Language Distribution",
+		title,
 		data,
 		width,
 		height,
@@ -175,6 +1834,34 @@ func generateChart(data []Visualizer.PieChartData, outputDir string, width, heig
 	}
 }
 
+// generateBarChart creates a Go-bar chart image based on the given data.
+func generateBarChart(title string, data []Visualizer.BarChartData, valueSuffix, outputDir string, width, height int, filename string) {
+	if len(data) == 0 {
+		return
+	}
+
+	outputPath := filepath.Join(outputDir, filename)
+	config := Visualizer.BuildGoBarChartConfig(title, data, width, height, valueSuffix, outputPath)
+
+	if err := Visualizer.CreateGoBarChart(config); err != nil {
+		log.Printf("Error generating chart %s: %v", filename, err)
+	}
+}
+
+// generateLineChart creates a line or stacked area chart over time if there are at least two points in time.
+func generateLineChart(title string, xValues []time.Time, series []Visualizer.LineSeriesData, stacked bool, outputDir string, width, height int, filename string) {
+	if len(xValues) < 2 || len(series) == 0 {
+		return
+	}
+
+	outputPath := filepath.Join(outputDir, filename)
+	config := Visualizer.BuildGoLineChartConfig(title, xValues, series, stacked, width, height, outputPath)
+
+	if err := Visualizer.CreateGoLineChart(config); err != nil {
+		log.Printf("Error generating chart %s: %v", filename, err)
+	}
+}
+
 // generateMermaidChart creates a MermaidJS-compatible pie chart markdown.
 func generateMermaidChart(data []Visualizer.PieChartData, outputDir, filename string) {
 	outputPath := filepath.Join(outputDir, filename)